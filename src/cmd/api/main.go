@@ -9,6 +9,7 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -18,9 +19,15 @@ import (
 	"github.com/hafiztri123/src/internal/pkg/config"
 	"github.com/hafiztri123/src/internal/pkg/database"
 	"github.com/hafiztri123/src/internal/pkg/health"
+	"github.com/hafiztri123/src/internal/pkg/i18n"
 	"github.com/hafiztri123/src/internal/pkg/logger"
 	customMiddleware "github.com/hafiztri123/src/internal/pkg/middleware"
+	"github.com/hafiztri123/src/internal/pkg/mailer"
+	"github.com/hafiztri123/src/internal/pkg/payment"
+	"github.com/hafiztri123/src/internal/pkg/scheduler"
 	"github.com/hafiztri123/src/internal/pkg/storage"
+	"github.com/hafiztri123/src/internal/pkg/tenant"
+	"github.com/hafiztri123/src/internal/pkg/ticket"
 	"github.com/hafiztri123/src/internal/repository"
 	"github.com/hafiztri123/src/internal/repository/postgres"
 	"github.com/hafiztri123/src/internal/service"
@@ -66,32 +73,54 @@ func main(){
 
 	cfg := loadConfig(appLogger, ctx)
 
+	catalog := catalogInit(appLogger, ctx, cfg)
+
 	db := loadDatabase(appLogger, ctx, &cfg.Database)
-	startMigration(appLogger, ctx, db)
+	startMigration(appLogger, ctx, db, catalog, cfg)
 
 	redisClient := redisClientInit(appLogger, ctx, cfg.Redis)
 	redisCache := redisCacheInit(appLogger, ctx, redisClient, cfg.Redis)
 
-	repository := newMainRepository(db, redisCache)
-	service := newMainService(repository, cfg)
-	handler := newMainHandler(service)
-	middleware := newMainMiddleware(cfg, redisClient)
+	repository := newMainRepository(db, redisCache, catalog)
+	storageService := storageInit(appLogger, ctx, cfg)
+	paymentProvider := paymentInit(appLogger, ctx, cfg)
+	mailService := mailerInit(appLogger, ctx, cfg)
+	service := newMainService(repository, storageService, paymentProvider, mailService, cfg, catalog)
+	handler := newMainHandler(service, paymentProvider)
+	realIP := realIPInit(appLogger, ctx, cfg)
+	middleware := newMainMiddleware(cfg, redisClient, catalog, service.Organization, service.Auth, realIP)
 
 
 
 	router := chi.NewRouter()
 	applyMiddleware(appLogger, router, middleware)
 
-	mainRoute := newMainRoute(appLogger, ctx, handler, router, middleware)
+	api := router.With(middleware.Tenant.Resolve)
+	mainRoute := newMainRoute(appLogger, ctx, handler, api, middleware)
 	mainRoute.auth()
 	mainRoute.event()
 	mainRoute.user()
 	mainRoute.category()
+	mainRoute.registration()
+	mainRoute.order()
+	mainRoute.seating()
+	mainRoute.transfer()
+	mainRoute.refund()
+	mainRoute.promo()
+	mainRoute.analytics()
+	mainRoute.trending()
+	mainRoute.recommendation()
+	mainRoute.translation()
+	mainRoute.organization()
 
 	sideRoute := newSideRoute(appLogger, ctx, router, db, redisClient)
 	sideRoute.health()
 	sideRoute.swagger()
 
+	jobCtx, stopJobs := context.WithCancel(ctx)
+	defer stopJobs()
+	startJobs(appLogger, jobCtx, repository, storageService, cfg)
+
 	startServer(appLogger, ctx, router)
 }
 
@@ -100,6 +129,17 @@ type mainRoute struct {
 	event func()
 	user func()
 	category func()
+	registration func()
+	order func()
+	seating func()
+	transfer func()
+	refund func()
+	promo func()
+	analytics func()
+	trending func()
+	recommendation func()
+	translation func()
+	organization func()
 }
 
 type sideRoute struct {
@@ -107,12 +147,23 @@ type sideRoute struct {
 	swagger func()
 }
 
-func newMainRoute(log *logger.Logger, ctx context.Context, handler *mainHandler, router *chi.Mux, middleware *mainMiddleware) *mainRoute {
+func newMainRoute(log *logger.Logger, ctx context.Context, handler *mainHandler, router chi.Router, middleware *mainMiddleware) *mainRoute {
 	return &mainRoute{
-		auth: authRouteInit(log, ctx, handler.Auth, router ),
-		event: eventRouteInit(log, ctx, handler.Event, router, middleware.JWT, middleware.RateLimiter),
+		auth: authRouteInit(log, ctx, handler.Auth, router, middleware),
+		event: eventRouteInit(log, ctx, handler.Event, router, middleware),
 		user: userRouteInit(log, ctx, handler.User, router, middleware.JWT),
 		category: categoryRouteInit(log, ctx, handler.Category, router, middleware),
+		registration: registrationRouteInit(log, ctx, handler.Registration, handler.RegistrationForm, router, middleware),
+		order: orderRouteInit(log, ctx, handler.Order, handler.Invoice, handler.FakePayment, router, middleware),
+		seating: seatingRouteInit(log, ctx, handler.Seating, router, middleware),
+		transfer: transferRouteInit(log, ctx, handler.Transfer, router, middleware),
+		refund: refundRouteInit(log, ctx, handler.Refund, router, middleware),
+		promo: promoRouteInit(log, ctx, handler.Promo, handler.Order, router, middleware),
+		analytics: analyticsRouteInit(log, ctx, handler.Analytics, router, middleware),
+		trending: trendingRouteInit(log, ctx, handler.Trending, handler.Bookmark, router, middleware),
+		recommendation: recommendationRouteInit(log, ctx, handler.Recommendation, router, middleware),
+		translation: translationRouteInit(log, ctx, handler.Translation, router, middleware),
+		organization: organizationRouteInit(log, ctx, handler.Organization, router, middleware),
 	}
 }
 
@@ -153,8 +204,9 @@ func applyMiddleware(log *logger.Logger, router *chi.Mux, customMiddleware *main
 	router.Use(customMiddleware.Logger.LoggerMiddleware(log))
 	router.Use(middleware.Recoverer)
 	router.Use(middleware.RequestID)
-	router.Use(middleware.RealIP)
+	router.Use(customMiddleware.RealIP.Resolve)
 	router.Use(middleware.Timeout(60 * time.Second))
+	router.Use(customMiddleware.Locale.Negotiate)
 }
 
 func healthRouteInit(log *logger.Logger, ctx context.Context, router *chi.Mux, db *gorm.DB, redis *redis.Client) func() {
@@ -248,39 +300,75 @@ func loadDatabase(log *logger.Logger, ctx context.Context, cfg *config.DatabaseC
 	return db
 }
 
-func startMigration(log *logger.Logger, ctx context.Context, db *gorm.DB) {
+func startMigration(log *logger.Logger, ctx context.Context, db *gorm.DB, catalog *i18n.Catalog, cfg *config.Config) {
 	log.Info(ctx, "Starting database migrations", nil)
-	postgres.RunMigrations(db)
+	postgres.RunMigrations(db, catalog, defaultOrganization(cfg))
 	log.Info(ctx, "Database migrations completed", nil)
 }
 
 
-func authRouteInit(log *logger.Logger, ctx context.Context, authHandler handler.AuthHandler, router *chi.Mux) func() {
+func authRouteInit(log *logger.Logger, ctx context.Context, authHandler handler.AuthHandler, router chi.Router, middleware *mainMiddleware) func() {
 	return func ()  {
 		log.Info(ctx, "Initializing authentication routes", nil)
-		router.Post("/api/v1/auth/register", authHandler.Register)
 		router.Post("/api/v1/auth/login", authHandler.Login)	
-		
+		router.Post("/api/v1/auth/refresh", authHandler.Refresh)
+		router.Post("/api/v1/auth/verify-email", authHandler.VerifyEmail)
+
+		// These routes send email, so they are limited per client and per address.
+		router.Group(func(r chi.Router) {
+			r.Use(middleware.RateLimiter.RateLimit)
+			r.Use(middleware.RateLimiter.RateLimitEmail)
+			r.Post("/api/v1/auth/register", authHandler.Register)
+			r.Post("/api/v1/auth/forgot-password", authHandler.ForgotPassword)
+		})
+
+		router.Group(func(r chi.Router) {
+			r.Use(middleware.RateLimiter.RateLimit)
+			r.Post("/api/v1/auth/reset-password", authHandler.ResetPassword)
+		})
+
+		router.Group(func(r chi.Router) {
+			r.Use(middleware.JWT.Authenticate)
+			r.Post("/api/v1/auth/logout", authHandler.Logout)
+		})
+
+		router.Group(func(r chi.Router) {
+			r.Use(middleware.JWT.Authenticate)
+			r.Use(middleware.RateLimiter.RateLimit)
+			r.Post("/api/v1/auth/verify-email/resend", authHandler.ResendVerification)
+		})
 	}
 }
 
 
-func eventRouteInit(log *logger.Logger, ctx context.Context, eventHandler handler.EventHandler, router *chi.Mux, authMiddleware *customMiddleware.AuthMiddleware, rateLimitMiddleware *customMiddleware.RateLimiter) func() {
+func eventRouteInit(log *logger.Logger, ctx context.Context, eventHandler handler.EventHandler, router chi.Router, middleware *mainMiddleware) func() {
 	return func ()  {
 		log.Info(ctx, "Initializing event routes", nil)
 		router.Group(func(r chi.Router) {
 			r.Get("/api/v1/events", eventHandler.ListEvents)
 			r.Get("/api/v1/events/search", eventHandler.SearchEvents)
+			r.Get("/api/v1/events/calendar", eventHandler.GetCalendar)
 			r.Get("/api/v1/events/{id}", eventHandler.GetEvent)
+			r.Get("/api/v1/events/{id}/files", eventHandler.ListFiles)
 		})
 
 		router.Group(func(r chi.Router) {
-			r.Use(authMiddleware.Authenticate)
-			r.Use(rateLimitMiddleware.RateLimit)
+			r.Use(middleware.JWT.Authenticate)
+			r.Use(middleware.Tenant.Authorize)
+			r.Use(middleware.RateLimiter.RateLimit)
+			r.Get("/api/v1/events/conflicts", eventHandler.ListConflicts)
+			r.Get("/api/v1/events/trash", eventHandler.ListTrash)
+			r.Post("/api/v1/events/{id}/restore", eventHandler.RestoreEvent)
+			r.Get("/api/v1/events/{id}/revisions", eventHandler.ListRevisions)
+			r.Post("/api/v1/events/{id}/revisions/{revisionId}/rollback", eventHandler.RollbackEvent)
 			r.Post("/api/v1/events", eventHandler.CreateEvent)
 			r.Put("/api/v1/events/{id}", eventHandler.UpdateEvent)
+			r.Patch("/api/v1/events/{id}", eventHandler.PatchEvent)
 			r.Delete("/api/v1/events/{id}", eventHandler.DeleteEvent)
 			r.Post("/api/v1/events/{id}/upload", eventHandler.UploadFile)
+			r.Put("/api/v1/events/{id}/files/order", eventHandler.ReorderFiles)
+			r.Patch("/api/v1/events/{id}/files/{fileId}", eventHandler.PatchFile)
+			r.Delete("/api/v1/events/{id}/files/{fileId}", eventHandler.DeleteFile)
 		})
 	
 	}
@@ -319,13 +407,18 @@ func redisCacheInit(log *logger.Logger, ctx context.Context, client *redis.Clien
 
 
 
-func userRouteInit(log *logger.Logger, ctx context.Context, userHandler handler.UserHandler, router *chi.Mux, authMiddleware *customMiddleware.AuthMiddleware) func() {
+func userRouteInit(log *logger.Logger, ctx context.Context, userHandler handler.UserHandler, router chi.Router, authMiddleware *customMiddleware.AuthMiddleware) func() {
 	return func ()  {
 		log.Info(ctx, "Initializing user routes", nil)
 
+		router.Group(func(r chi.Router) {
+			r.Get("/api/v1/organizers/{id}", userHandler.GetOrganizer)
+		})
+
 		router.Group(func(r chi.Router) {
 			r.Use(authMiddleware.Authenticate)
 			r.Put("/api/v1/users/profile", userHandler.UpdateProfile)
+			r.Patch("/api/v1/users/profile", userHandler.PatchProfile)
 			r.Get("/api/v1/users/profile", userHandler.GetProfile)
 			r.Put("/api/v1/users/password", userHandler.ChangePassword)
 			r.Put("/api/v1/users/profile-image", userHandler.UploadProfileImage)
@@ -333,7 +426,7 @@ func userRouteInit(log *logger.Logger, ctx context.Context, userHandler handler.
 	}
 }
 
-func categoryRouteInit(log *logger.Logger, ctx context.Context, categoryHandler handler.CategoryHandler, router *chi.Mux, middleware *mainMiddleware) func() {
+func categoryRouteInit(log *logger.Logger, ctx context.Context, categoryHandler handler.CategoryHandler, router chi.Router, middleware *mainMiddleware) func() {
 	return func ()  {
 		log.Info(ctx, "Initializing category routes", nil)
 
@@ -344,25 +437,292 @@ func categoryRouteInit(log *logger.Logger, ctx context.Context, categoryHandler
 	
 		router.Group(func(r chi.Router) {
 			r.Use(middleware.JWT.Authenticate)
+			r.Use(middleware.Tenant.Authorize)
 			r.Use(middleware.RateLimiter.RateLimit)
+			r.Get("/api/v1/categories/trash", categoryHandler.ListTrash)
+			r.Post("/api/v1/categories/{id}/restore", categoryHandler.RestoreCategory)
 			r.Post("/api/v1/categories", categoryHandler.CreateCategory)
 			r.Put("/api/v1/categories/{id}", categoryHandler.UpdateCategory)
+			r.Patch("/api/v1/categories/{id}", categoryHandler.PatchCategory)
 			r.Delete("/api/v1/categories/{id}", categoryHandler.DeleteCategory)
 		})	
 	}
 }
 
+func registrationRouteInit(log *logger.Logger, ctx context.Context, registrationHandler handler.RegistrationHandler, registrationFormHandler handler.RegistrationFormHandler, router chi.Router, middleware *mainMiddleware) func() {
+	return func ()  {
+		log.Info(ctx, "Initializing registration routes", nil)
+
+		router.Group(func(r chi.Router) {
+			r.Get("/api/v1/events/{id}/registration-form", registrationFormHandler.GetForm)
+		})
+
+		router.Group(func(r chi.Router) {
+			r.Use(middleware.JWT.Authenticate)
+			r.Use(middleware.Tenant.Authorize)
+			r.Use(middleware.RateLimiter.RateLimit)
+			r.Post("/api/v1/events/{id}/register", registrationHandler.Register)
+			r.Post("/api/v1/events/{id}/check-ins", registrationHandler.CheckIn)
+			r.Get("/api/v1/events/{id}/ticket", registrationHandler.GetTicket)
+			r.Put("/api/v1/events/{id}/attendees", registrationHandler.UpdateAttendees)
+			r.Get("/api/v1/events/{id}/registrations/{userId}", registrationHandler.GetRegistration)
+			r.Get("/api/v1/events/{id}/attendees/export", registrationHandler.ExportAttendees)
+			r.Put("/api/v1/events/{id}/registration-form", registrationFormHandler.PutForm)
+			r.Delete("/api/v1/events/{id}/registration-form", registrationFormHandler.DeleteForm)
+		})
+	}
+}
+
+func orderRouteInit(log *logger.Logger, ctx context.Context, orderHandler handler.OrderHandler, invoiceHandler handler.InvoiceHandler, fakePaymentHandler handler.FakePaymentHandler, router chi.Router, middleware *mainMiddleware) func() {
+	return func ()  {
+		log.Info(ctx, "Initializing order routes", nil)
+
+		router.Group(func(r chi.Router) {
+			r.Get("/api/v1/events/{id}/ticket-types", orderHandler.ListTicketTypes)
+			r.Get("/api/v1/events/{id}/ticket-limit", orderHandler.GetTicketLimit)
+			r.Post("/api/v1/payments/webhook", orderHandler.Webhook)
+		})
+
+		router.Group(func(r chi.Router) {
+			r.Use(middleware.JWT.Authenticate)
+			r.Use(middleware.Tenant.Authorize)
+			r.Use(middleware.RateLimiter.RateLimit)
+			if fakePaymentHandler != nil {
+				r.Post("/api/v1/payments/fake/{intentId}", fakePaymentHandler.Simulate)
+			}
+			r.Post("/api/v1/events/{id}/ticket-types", orderHandler.CreateTicketType)
+			r.Put("/api/v1/events/{id}/ticket-limit", orderHandler.SetTicketLimit)
+			r.Post("/api/v1/orders", orderHandler.Checkout)
+			r.Get("/api/v1/orders", orderHandler.ListOrders)
+			r.Get("/api/v1/orders/{id}", orderHandler.GetOrder)
+			r.Get("/api/v1/orders/{id}/invoice", invoiceHandler.GetInvoice)
+			r.Get("/api/v1/orders/{id}/invoice/download", invoiceHandler.DownloadInvoice)
+			r.Get("/api/v1/orders/{id}/receipt/download", invoiceHandler.DownloadReceipt)
+		})
+	}
+}
+
+func seatingRouteInit(log *logger.Logger, ctx context.Context, seatingHandler handler.SeatingHandler, router chi.Router, middleware *mainMiddleware) func() {
+	return func ()  {
+		log.Info(ctx, "Initializing seating routes", nil)
+
+		router.Group(func(r chi.Router) {
+			r.Get("/api/v1/events/{id}/seat-map", seatingHandler.GetSeatMap)
+		})
+
+		router.Group(func(r chi.Router) {
+			r.Use(middleware.JWT.Authenticate)
+			r.Use(middleware.Tenant.Authorize)
+			r.Use(middleware.RateLimiter.RateLimit)
+			r.Put("/api/v1/events/{id}/seat-map", seatingHandler.PutSeatMap)
+			r.Post("/api/v1/events/{id}/seat-holds", seatingHandler.HoldSeats)
+			r.Delete("/api/v1/seat-holds/{id}", seatingHandler.ReleaseHold)
+			r.Post("/api/v1/seat-holds/{id}/confirm", seatingHandler.ConfirmHold)
+		})
+	}
+}
+
+func transferRouteInit(log *logger.Logger, ctx context.Context, transferHandler handler.TransferHandler, router chi.Router, middleware *mainMiddleware) func() {
+	return func ()  {
+		log.Info(ctx, "Initializing transfer routes", nil)
+
+		router.Group(func(r chi.Router) {
+			r.Get("/api/v1/events/{id}/transfer-policy", transferHandler.GetPolicy)
+		})
+
+		router.Group(func(r chi.Router) {
+			r.Use(middleware.JWT.Authenticate)
+			r.Use(middleware.Tenant.Authorize)
+			r.Use(middleware.RateLimiter.RateLimit)
+			r.Put("/api/v1/events/{id}/transfer-policy", transferHandler.SetPolicy)
+			r.Post("/api/v1/events/{id}/transfers", transferHandler.InitiateTransfer)
+			r.Get("/api/v1/events/{id}/transfers", transferHandler.ListEventTransfers)
+			r.Get("/api/v1/transfers/incoming", transferHandler.ListIncoming)
+			r.Get("/api/v1/transfers/outgoing", transferHandler.ListOutgoing)
+			r.Post("/api/v1/transfers/{id}/accept", transferHandler.AcceptTransfer)
+			r.Post("/api/v1/transfers/{id}/decline", transferHandler.DeclineTransfer)
+			r.Post("/api/v1/transfers/{id}/cancel", transferHandler.CancelTransfer)
+		})
+	}
+}
+
+func refundRouteInit(log *logger.Logger, ctx context.Context, refundHandler handler.RefundHandler, router chi.Router, middleware *mainMiddleware) func() {
+	return func ()  {
+		log.Info(ctx, "Initializing refund routes", nil)
+
+		router.Group(func(r chi.Router) {
+			r.Get("/api/v1/events/{id}/refund-policy", refundHandler.GetPolicy)
+		})
+
+		router.Group(func(r chi.Router) {
+			r.Use(middleware.JWT.Authenticate)
+			r.Use(middleware.Tenant.Authorize)
+			r.Use(middleware.RateLimiter.RateLimit)
+			r.Put("/api/v1/events/{id}/refund-policy", refundHandler.SetPolicy)
+			r.Get("/api/v1/events/{id}/refunds", refundHandler.ListEventRefunds)
+			r.Get("/api/v1/events/{id}/refund-ledger", refundHandler.ListLedger)
+			r.Post("/api/v1/events/{id}/cancel", refundHandler.CancelEvent)
+			r.Post("/api/v1/orders/{id}/refunds", refundHandler.RequestRefund)
+			r.Get("/api/v1/orders/{id}/refunds", refundHandler.ListOrderRefunds)
+			r.Post("/api/v1/refunds/{id}/approve", refundHandler.ApproveRefund)
+			r.Post("/api/v1/refunds/{id}/reject", refundHandler.RejectRefund)
+		})
+	}
+}
+
+func promoRouteInit(log *logger.Logger, ctx context.Context, promoHandler handler.PromoHandler, orderHandler handler.OrderHandler, router chi.Router, middleware *mainMiddleware) func() {
+	return func ()  {
+		log.Info(ctx, "Initializing promo code routes", nil)
+
+		router.Group(func(r chi.Router) {
+			r.Use(middleware.JWT.Authenticate)
+			r.Use(middleware.Tenant.Authorize)
+			r.Use(middleware.RateLimiter.RateLimit)
+			r.Post("/api/v1/promo-codes/validate", orderHandler.PreviewPromoCode)
+			r.Post("/api/v1/promo-codes", promoHandler.CreatePromoCode)
+			r.Get("/api/v1/promo-codes", promoHandler.ListPromoCodes)
+			r.Delete("/api/v1/promo-codes/{id}", promoHandler.DeletePromoCode)
+		})
+	}
+}
+
+func analyticsRouteInit(log *logger.Logger, ctx context.Context, analyticsHandler handler.AnalyticsHandler, router chi.Router, middleware *mainMiddleware) func() {
+	return func ()  {
+		log.Info(ctx, "Initializing analytics routes", nil)
+
+		router.Group(func(r chi.Router) {
+			r.Use(middleware.JWT.Authenticate)
+			r.Use(middleware.Tenant.Authorize)
+			r.Use(middleware.RateLimiter.RateLimit)
+			r.Get("/api/v1/events/{id}/analytics", analyticsHandler.GetEventAnalytics)
+		})
+	}
+}
+
+func trendingRouteInit(log *logger.Logger, ctx context.Context, trendingHandler handler.TrendingHandler, bookmarkHandler handler.BookmarkHandler, router chi.Router, middleware *mainMiddleware) func() {
+	return func ()  {
+		log.Info(ctx, "Initializing trending and bookmark routes", nil)
+
+		router.Group(func(r chi.Router) {
+			r.Get("/api/v1/events/trending", trendingHandler.GetTrending)
+		})
+
+		router.Group(func(r chi.Router) {
+			r.Use(middleware.JWT.Authenticate)
+			r.Use(middleware.Tenant.Authorize)
+			r.Use(middleware.RateLimiter.RateLimit)
+			r.Put("/api/v1/events/{id}/bookmark", bookmarkHandler.AddBookmark)
+			r.Delete("/api/v1/events/{id}/bookmark", bookmarkHandler.RemoveBookmark)
+			r.Get("/api/v1/users/bookmarks", bookmarkHandler.ListBookmarks)
+		})
+	}
+}
+
+func recommendationRouteInit(log *logger.Logger, ctx context.Context, recommendationHandler handler.RecommendationHandler, router chi.Router, middleware *mainMiddleware) func() {
+	return func ()  {
+		log.Info(ctx, "Initializing recommendation routes", nil)
+
+		router.Group(func(r chi.Router) {
+			r.Get("/api/v1/events/{id}/recommendations", recommendationHandler.RecommendForEvent)
+		})
+
+		router.Group(func(r chi.Router) {
+			r.Use(middleware.JWT.Authenticate)
+			r.Use(middleware.Tenant.Authorize)
+			r.Use(middleware.RateLimiter.RateLimit)
+			r.Get("/api/v1/users/recommendations", recommendationHandler.RecommendForUser)
+		})
+	}
+}
+
+func translationRouteInit(log *logger.Logger, ctx context.Context, translationHandler handler.TranslationHandler, router chi.Router, middleware *mainMiddleware) func() {
+	return func ()  {
+		log.Info(ctx, "Initializing translation routes", nil)
+
+		router.Group(func(r chi.Router) {
+			r.Get("/api/v1/events/{id}/translations", translationHandler.ListEventTranslations)
+			r.Get("/api/v1/categories/{id}/translations", translationHandler.ListCategoryTranslations)
+		})
+
+		router.Group(func(r chi.Router) {
+			r.Use(middleware.JWT.Authenticate)
+			r.Use(middleware.Tenant.Authorize)
+			r.Use(middleware.RateLimiter.RateLimit)
+			r.Put("/api/v1/events/{id}/translations/{locale}", translationHandler.PutEventTranslation)
+			r.Delete("/api/v1/events/{id}/translations/{locale}", translationHandler.DeleteEventTranslation)
+			r.Put("/api/v1/categories/{id}/translations/{locale}", translationHandler.PutCategoryTranslation)
+			r.Delete("/api/v1/categories/{id}/translations/{locale}", translationHandler.DeleteCategoryTranslation)
+		})
+	}
+}
+
+func organizationRouteInit(log *logger.Logger, ctx context.Context, organizationHandler handler.OrganizationHandler, router chi.Router, middleware *mainMiddleware) func() {
+	return func ()  {
+		log.Info(ctx, "Initializing organization routes", nil)
+
+		router.Group(func(r chi.Router) {
+			r.Use(middleware.JWT.Authenticate)
+			r.Use(middleware.RateLimiter.RateLimit)
+			r.Post("/api/v1/organizations", organizationHandler.CreateOrganization)
+			r.Get("/api/v1/organizations", organizationHandler.ListOrganizations)
+			r.Get("/api/v1/organizations/{id}/members", organizationHandler.ListMembers)
+			r.Put("/api/v1/organizations/{id}/members/{userId}", organizationHandler.SetMember)
+			r.Delete("/api/v1/organizations/{id}/members/{userId}", organizationHandler.RemoveMember)
+		})
+	}
+}
+
 type mainRepository struct {
 	User 		repository.UserRepository
+	RefreshToken repository.RefreshTokenRepository
+	PasswordReset repository.PasswordResetRepository
+	TokenRevocation repository.TokenRevocationRepository
 	Event 		repository.EventRepository
 	Category 	repository.CategoryRepository
+	Registration repository.RegistrationRepository
+	Attendee 	repository.AttendeeRepository
+	RegistrationForm repository.RegistrationFormRepository
+	Revision 	repository.RevisionRepository
+	Order 		repository.OrderRepository
+	Seat 		repository.SeatRepository
+	SeatHold 	repository.SeatHoldRepository
+	Transfer 	repository.TransferRepository
+	Refund 		repository.RefundRepository
+	Promo 		repository.PromoRepository
+	Invoice 	repository.InvoiceRepository
+	Analytics 	repository.AnalyticsRepository
+	Trending 	repository.TrendingRepository
+	Bookmark 	repository.BookmarkRepository
+	Recommendation repository.RecommendationRepository
+	Translation repository.TranslationRepository
+	Organization repository.OrganizationRepository
 }
 
-func newMainRepository(db *gorm.DB, cache *cache.RedisCache) *mainRepository {
+func newMainRepository(db *gorm.DB, cache *cache.RedisCache, catalog *i18n.Catalog) *mainRepository {
 	return &mainRepository{
 		User: 		repository.NewUserRepository(db),
-		Event: 		repository.NewEventRepository(db, cache),
+		RefreshToken: repository.NewRefreshTokenRepository(db),
+		PasswordReset: repository.NewPasswordResetRepository(db),
+		TokenRevocation: repository.NewTokenRevocationRepository(cache),
+		Event: 		repository.NewEventRepository(db, cache, catalog),
 		Category: 	repository.NewCategoryRepository(db, cache),
+		Registration: repository.NewRegistrationRepository(db),
+		Attendee: 	repository.NewAttendeeRepository(db),
+		RegistrationForm: repository.NewRegistrationFormRepository(db),
+		Revision: 	repository.NewRevisionRepository(db),
+		Order: 		repository.NewOrderRepository(db),
+		Seat: 		repository.NewSeatRepository(db),
+		SeatHold: 	repository.NewSeatHoldRepository(cache),
+		Transfer: 	repository.NewTransferRepository(db),
+		Refund: 	repository.NewRefundRepository(db),
+		Promo: 		repository.NewPromoRepository(db),
+		Invoice: 	repository.NewInvoiceRepository(db),
+		Analytics: 	repository.NewAnalyticsRepository(db, cache),
+		Trending: 	repository.NewTrendingRepository(cache),
+		Bookmark: 	repository.NewBookmarkRepository(db),
+		Recommendation: repository.NewRecommendationRepository(db),
+		Translation: repository.NewTranslationRepository(db),
+		Organization: repository.NewOrganizationRepository(db),
 	}
 }
 
@@ -372,21 +732,265 @@ type mainService struct {
 	User 		service.UserService
 	Category 	service.CategoryService
 	Event 		service.EventService
+	Registration service.RegistrationService
+	RegistrationForm service.RegistrationFormService
+	Order 		service.OrderService
+	Seating 	service.SeatingService
+	Transfer 	service.TransferService
+	Refund 		service.RefundService
+	Promo 		service.PromoService
+	Invoice 	service.InvoiceService
+	Analytics 	service.AnalyticsService
+	Trending 	service.TrendingService
+	Bookmark 	service.BookmarkService
+	Recommendation service.RecommendationService
+	Translation service.TranslationService
+	Organization service.OrganizationService
 }
 
-func newMainService (repository *mainRepository, cfg *config.Config) *mainService {
+func newMainService (repository *mainRepository, cloudinary storage.StorageService, paymentProvider payment.Provider, mailService mailer.Mailer, cfg *config.Config, catalog *i18n.Catalog) *mainService {
+	reservationMinutes := cfg.Payment.ReservationMinutes
+	if reservationMinutes <= 0 {
+		reservationMinutes = 15
+	}
 
-	cloudinary, err := storage.NewCloudinaryService(cfg)
-	if err != nil {
-		log.Fatal("Cloudinary failed")
+	holdMinutes := cfg.Seating.HoldMinutes
+	if holdMinutes <= 0 {
+		holdMinutes = 10
 	}
-	
+
+	// Ticket codes fall back to the JWT secret so existing deployments keep working.
+	ticketSecret := cfg.Ticket.SigningSecret
+	if ticketSecret == "" {
+		ticketSecret = cfg.Auth.JWTSecret
+	}
+
+	maxTicketsPerUser := cfg.Ticket.MaxPerUser
+	if maxTicketsPerUser <= 0 {
+		maxTicketsPerUser = 10
+	}
+
+	currency := cfg.Payment.Currency
+	if currency == "" {
+		currency = "IDR"
+	}
+
+	trendingService := service.NewTrendingService(repository.Trending, repository.Event)
+	analyticsService := service.NewAnalyticsService(repository.Analytics, repository.Event, trendingService)
+	organizationService := service.NewOrganizationService(repository.Organization, repository.User)
+	registrationFormService := service.NewRegistrationFormService(repository.RegistrationForm, repository.Event)
+	invoiceService := service.NewInvoiceService(repository.Invoice, repository.Order, repository.Event, repository.User, cloudinary, cfg.Invoice.TaxRatePercent, cfg.Invoice.TaxLabel)
+	orderService := service.NewOrderService(repository.Order, repository.Event, repository.Promo, repository.Refund, invoiceService, analyticsService, registrationFormService, paymentProvider, currency, time.Duration(reservationMinutes)*time.Minute, maxTicketsPerUser)
+
 	return &mainService{
-		Auth: 		service.NewAuthService(repository.User, &cfg.Auth),
+		Auth: 		service.NewAuthService(repository.User, repository.RefreshToken, repository.PasswordReset, repository.TokenRevocation, mailService, &cfg.Auth),
 		User: 		service.NewUserService(repository.User, cloudinary),
-		Category: 	service.NewCategoryService(repository.Category),
-		Event: 		service.NewEventService(repository.Event, repository.Category, cloudinary),
+		Category: 	service.NewCategoryService(repository.Category, organizationService),
+		Event: 		service.NewEventService(repository.Event, repository.Category, repository.Registration, repository.Revision, organizationService, repository.User, cloudinary, cfg.Auth.RequireVerifiedEmail),
+		Registration: service.NewRegistrationService(repository.Registration, repository.Attendee, repository.Event, repository.User, analyticsService, registrationFormService, ticket.NewSigner(ticketSecret)),
+		RegistrationForm: registrationFormService,
+		Order: 		orderService,
+		Seating: 	service.NewSeatingService(repository.Seat, repository.SeatHold, repository.Event, repository.Order, orderService, time.Duration(holdMinutes)*time.Minute),
+		Transfer: 	service.NewTransferService(repository.Transfer, repository.Registration, repository.Event, repository.User),
+		Refund: 	service.NewRefundService(repository.Refund, repository.Order, repository.Event, repository.Registration, paymentProvider),
+		Promo: 		service.NewPromoService(repository.Promo, repository.Order, repository.Event),
+		Invoice: 	invoiceService,
+		Analytics: 	analyticsService,
+		Trending: 	trendingService,
+		Bookmark: 	service.NewBookmarkService(repository.Bookmark, repository.Event, trendingService),
+		Recommendation: service.NewRecommendationService(repository.Recommendation, repository.Registration, repository.Event),
+		Translation: service.NewTranslationService(repository.Translation, repository.Event, repository.Category, catalog),
+		Organization: organizationService,
+	}
+}
+
+func defaultOrganization(cfg *config.Config) string {
+	if cfg.Tenant.DefaultOrganization == "" {
+		return "default"
 	}
+	return cfg.Tenant.DefaultOrganization
+}
+
+func catalogInit(log *logger.Logger, ctx context.Context, cfg *config.Config) *i18n.Catalog {
+	defaultLocale := cfg.I18n.DefaultLocale
+	if defaultLocale == "" {
+		defaultLocale = "en"
+	}
+
+	locales := cfg.I18n.Locales
+	if len(locales) == 0 {
+		locales = []string{"en", "id"}
+	}
+
+	catalog := i18n.NewCatalog(defaultLocale, locales)
+	log.Info(ctx, "Initializing locales", map[string]interface{}{
+		"default": catalog.Default(),
+		"translations": catalog.Translations(),
+	})
+	return catalog
+}
+
+func storageInit(log *logger.Logger, ctx context.Context, cfg *config.Config) storage.StorageService {
+	log.Info(ctx, "Initializing storage service", nil)
+	cloudinary, err := storage.NewCloudinaryService(cfg)
+	if err != nil {
+		log.Fatal(ctx, "Cloudinary failed", err, nil)
+	}
+	return cloudinary
+}
+
+func paymentInit(log *logger.Logger, ctx context.Context, cfg *config.Config) payment.Provider {
+	log.Info(ctx, "Initializing payment provider", map[string]interface{}{
+		"provider": cfg.Payment.Provider,
+	})
+
+	if cfg.Payment.WebhookSecret == "" {
+		log.Fatal(ctx, "Payment webhook secret is not set", fmt.Errorf("payment.webhook_secret is required"), nil)
+		return nil
+	}
+
+	switch cfg.Payment.Provider {
+	case "":
+		log.Fatal(ctx, "Payment provider is not set", fmt.Errorf("payment.provider is required"), nil)
+		return nil
+	case "fake":
+		if !cfg.Server.IsDevelopment() {
+			log.Fatal(ctx, "Fake payment provider refused", fmt.Errorf("provider %q is only allowed when server.environment is development", cfg.Payment.Provider), nil)
+			return nil
+		}
+		return payment.NewFakeProvider(cfg.Payment.WebhookSecret)
+	default:
+		log.Fatal(ctx, "Unknown payment provider", fmt.Errorf("provider %q is not supported", cfg.Payment.Provider), nil)
+		return nil
+	}
+}
+
+func realIPInit(log *logger.Logger, ctx context.Context, cfg *config.Config) *customMiddleware.RealIP {
+	log.Info(ctx, "Initializing trusted proxies", map[string]interface{}{
+		"trusted_proxies": cfg.Server.TrustedProxies,
+	})
+
+	realIP, err := customMiddleware.NewRealIP(cfg.Server.TrustedProxies)
+	if err != nil {
+		log.Fatal(ctx, "Invalid trusted proxies", err, nil)
+		return nil
+	}
+
+	return realIP
+}
+
+func mailerInit(log *logger.Logger, ctx context.Context, cfg *config.Config) mailer.Mailer {
+	log.Info(ctx, "Initializing mailer", map[string]interface{}{
+		"provider": cfg.Mail.Provider,
+	})
+
+	from := cfg.Mail.From
+	if from == "" {
+		from = "no-reply@localhost"
+	}
+
+	// The log and file sinks keep verification and reset links readable by anyone
+	// with access to the logs, so they are only for local development.
+	switch cfg.Mail.Provider {
+	case "":
+		log.Fatal(ctx, "Mail provider is not set", fmt.Errorf("mail.provider is required"), nil)
+		return nil
+	case "log", "file":
+		if !cfg.Server.IsDevelopment() {
+			log.Fatal(ctx, "Development mail provider refused", fmt.Errorf("provider %q is only allowed when server.environment is development", cfg.Mail.Provider), nil)
+			return nil
+		}
+		if cfg.Mail.Provider == "log" {
+			return mailer.NewLogMailer(log)
+		}
+
+		path := cfg.Mail.FilePath
+		if path == "" {
+			path = "logs/mail.log"
+		}
+		return mailer.NewFileMailer(path, from)
+	case "smtp":
+		if cfg.Mail.SMTPHost == "" {
+			log.Fatal(ctx, "SMTP host is not set", fmt.Errorf("mail.smtp_host is required for provider %q", cfg.Mail.Provider), nil)
+			return nil
+		}
+
+		port := cfg.Mail.SMTPPort
+		if port <= 0 {
+			port = 25
+		}
+		return mailer.NewSMTPMailer(cfg.Mail.SMTPHost, port, cfg.Mail.SMTPUsername, cfg.Mail.SMTPPassword, from)
+	default:
+		log.Fatal(ctx, "Unknown mail provider", fmt.Errorf("provider %q is not supported", cfg.Mail.Provider), nil)
+		return nil
+	}
+}
+
+func startJobs(log *logger.Logger, ctx context.Context, repository *mainRepository, storageService storage.StorageService, cfg *config.Config) {
+	log.Info(ctx, "Starting background jobs", nil)
+
+	retentionDays := cfg.Trash.RetentionDays
+	if retentionDays <= 0 {
+		retentionDays = 30
+	}
+
+	purgeInterval := cfg.Trash.PurgeIntervalMinutes
+	if purgeInterval <= 0 {
+		purgeInterval = 60
+	}
+
+	jobs := scheduler.New(log)
+	jobs.Add(
+		service.NewTrashPurger(repository.Event, repository.Category, storageService, time.Duration(retentionDays)*24*time.Hour),
+		time.Duration(purgeInterval)*time.Minute,
+	)
+
+	expiryInterval := cfg.Payment.ExpiryIntervalMinutes
+	if expiryInterval <= 0 {
+		expiryInterval = 1
+	}
+
+	jobs.Add(
+		service.NewOrderExpirer(repository.Order),
+		time.Duration(expiryInterval)*time.Minute,
+	)
+
+	rollupInterval := cfg.Analytics.RollupIntervalMinutes
+	if rollupInterval <= 0 {
+		rollupInterval = 15
+	}
+
+	jobs.Add(
+		service.NewAnalyticsRollup(repository.Analytics),
+		time.Duration(rollupInterval)*time.Minute,
+	)
+
+	decayInterval := cfg.Trending.DecayIntervalMinutes
+	if decayInterval <= 0 {
+		decayInterval = 10
+	}
+
+	jobs.Add(
+		service.NewTrendingDecayer(repository.Trending),
+		time.Duration(decayInterval)*time.Minute,
+	)
+
+	recommendationInterval := cfg.Recommendation.IntervalMinutes
+	if recommendationInterval <= 0 {
+		recommendationInterval = 60
+	}
+
+	jobs.Add(
+		service.NewRecommendationBuilder(repository.Recommendation),
+		time.Duration(recommendationInterval)*time.Minute,
+	)
+
+	jobs.Add(
+		service.NewAuthTokenPurger(repository.RefreshToken, repository.PasswordReset),
+		time.Hour,
+	)
+	// Jobs work across every organization.
+	jobs.Start(tenant.WithSystem(ctx))
 }
 
 type mainHandler struct {
@@ -394,46 +998,97 @@ type mainHandler struct {
 	User	 	handler.UserHandler
 	Category 	handler.CategoryHandler
 	Event 		handler.EventHandler
+	Registration handler.RegistrationHandler
+	RegistrationForm handler.RegistrationFormHandler
+	Order 		handler.OrderHandler
+	Seating 	handler.SeatingHandler
+	Transfer 	handler.TransferHandler
+	FakePayment handler.FakePaymentHandler
+	Refund 		handler.RefundHandler
+	Promo 		handler.PromoHandler
+	Invoice 	handler.InvoiceHandler
+	Analytics 	handler.AnalyticsHandler
+	Trending 	handler.TrendingHandler
+	Bookmark 	handler.BookmarkHandler
+	Recommendation handler.RecommendationHandler
+	Translation handler.TranslationHandler
+	Organization handler.OrganizationHandler
 }
 
-func newMainHandler (service *mainService) *mainHandler {
+func newMainHandler (service *mainService, paymentProvider payment.Provider) *mainHandler {
+	var fakePayment handler.FakePaymentHandler
+	if fake, ok := paymentProvider.(*payment.FakeProvider); ok {
+		fakePayment = handler.NewFakePaymentHandler(fake, service.Order)
+	}
+
 	return &mainHandler{
 		Auth: 		handler.NewAuthHandler(service.Auth),
 		User: 		handler.NewUserHandler(service.User),
-		Category: 	handler.NewCategoryHandler(service.Category),
-		Event: 		handler.NewEventHandler(service.Event),
+		Category: 	handler.NewCategoryHandler(service.Category, service.Translation),
+		Event: 		handler.NewEventHandler(service.Event, service.Analytics, service.Translation),
+		Registration: handler.NewRegistrationHandler(service.Registration),
+		RegistrationForm: handler.NewRegistrationFormHandler(service.RegistrationForm),
+		Order: 		handler.NewOrderHandler(service.Order),
+		Seating: 	handler.NewSeatingHandler(service.Seating),
+		Transfer: 	handler.NewTransferHandler(service.Transfer),
+		FakePayment: fakePayment,
+		Refund: 	handler.NewRefundHandler(service.Refund),
+		Promo: 		handler.NewPromoHandler(service.Promo),
+		Invoice: 	handler.NewInvoiceHandler(service.Invoice),
+		Analytics: 	handler.NewAnalyticsHandler(service.Analytics),
+		Trending: 	handler.NewTrendingHandler(service.Trending, service.Translation),
+		Bookmark: 	handler.NewBookmarkHandler(service.Bookmark),
+		Recommendation: handler.NewRecommendationHandler(service.Recommendation, service.Translation),
+		Translation: handler.NewTranslationHandler(service.Translation),
+		Organization: handler.NewOrganizationHandler(service.Organization),
 	}
 }
 
 type mainMiddleware struct {
+	RealIP 		*customMiddleware.RealIP
 	JWT 		*customMiddleware.AuthMiddleware
 	RateLimiter *customMiddleware.RateLimiter
 	Logger 		*customMiddleware.Logger
-
+	Locale 		*customMiddleware.Locale
+	Tenant 		*customMiddleware.Tenant
 }
 
-func newMainMiddleware(cfg *config.Config, redis *redis.Client) *mainMiddleware {
+func newMainMiddleware(cfg *config.Config, redis *redis.Client, catalog *i18n.Catalog, resolver customMiddleware.OrganizationResolver, revocations customMiddleware.TokenRevocationChecker, realIP *customMiddleware.RealIP) *mainMiddleware {
 	JWT := customMiddleware.NewAuthMiddleware(
-		cfg.Auth.JWTSecret,
+		cfg.Auth.JWTSecret, revocations,
 	)
 
+	emailRequestLimit := cfg.RateLimit.EmailRequestLimit
+	if emailRequestLimit <= 0 {
+		emailRequestLimit = 5
+	}
+
+	emailWindowSeconds := cfg.RateLimit.EmailWindowSeconds
+	if emailWindowSeconds <= 0 {
+		emailWindowSeconds = 3600
+	}
+
 	RateLimiter := customMiddleware.NewRateLimiter(
-		redis, cfg.RateLimit.RequestLimit, time.Duration(cfg.RateLimit.WindowSeconds),
+		redis, cfg.RateLimit.RequestLimit, time.Duration(cfg.RateLimit.WindowSeconds)*time.Second,
+		emailRequestLimit, time.Duration(emailWindowSeconds)*time.Second,
 	)
 	
 	return &mainMiddleware{
+		RealIP: realIP,
 		JWT: JWT,
 		RateLimiter: RateLimiter,
 		Logger: customMiddleware.NewLogger(),
+		Locale: customMiddleware.NewLocale(catalog),
+		Tenant: customMiddleware.NewTenant(resolver, cfg.Tenant.BaseDomain, defaultOrganization(cfg)),
 	}
 }
 
 func corsMiddleware() func(http.Handler) http.Handler {
     return cors.New(cors.Options{
         AllowedOrigins:   []string{"http://localhost:5173"}, // Replace with your frontend URL(s)
-        AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-        AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-        ExposedHeaders:   []string{"Link"},
+        AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+        AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "If-Match", "X-Organization"},
+        ExposedHeaders:   []string{"Link", "ETag"},
         AllowCredentials: true,
         MaxAge:           300, 
     }).Handler
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/hafiztri123/src/internal/model"
+	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"github.com/hafiztri123/src/internal/pkg/response"
+	"github.com/hafiztri123/src/internal/service"
+)
+
+type AnalyticsHandler interface {
+	GetEventAnalytics(w http.ResponseWriter, r *http.Request)
+}
+
+type analyticsHandlerImpl struct {
+	analyticsService service.AnalyticsService
+}
+
+func NewAnalyticsHandler(analyticsService service.AnalyticsService) AnalyticsHandler {
+	return &analyticsHandlerImpl{
+		analyticsService: analyticsService,
+	}
+}
+
+// GetEventAnalytics godoc
+// @Summary      Event analytics
+// @Description  Get the daily views, unique visitors, registrations and check-ins of an event, and the view to check-in funnel over the range. Only the event creator may access them.
+// @Tags         analytics
+// @Produce      json
+// @Param        id    path      string  true   "Event ID"
+// @Param        from  query     string  false  "First day (YYYY-MM-DD), defaults to 29 days before to"
+// @Param        to    query     string  false  "Last day (YYYY-MM-DD), defaults to today"
+// @Success      200  {object}  response.Response{data=model.EventAnalytics}
+// @Failure      400  {object}  response.Response
+// @Failure      401  {object}  response.Response
+// @Failure      403  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Security     Bearer
+// @Router       /events/{id}/analytics [get]
+func (h *analyticsHandlerImpl) GetEventAnalytics(w http.ResponseWriter, r *http.Request) {
+	eventID := chi.URLParam(r, "id")
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+	params := r.URL.Query()
+
+	input := &model.AnalyticsInput{}
+
+	if value := params.Get("from"); value != "" {
+		from, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			HandleErrorResponse(w, errs.NewBadRequestError("from must be YYYY-MM-DD"))
+			return
+		}
+		input.From = from
+	}
+
+	if value := params.Get("to"); value != "" {
+		to, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			HandleErrorResponse(w, errs.NewBadRequestError("to must be YYYY-MM-DD"))
+			return
+		}
+		input.To = to
+	}
+
+	analytics, err := h.analyticsService.GetEventAnalytics(r.Context(), eventID, userID, input)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+		Data:      analytics,
+	})
+}
+
+// visitorID identifies an anonymous visitor by client address and user agent.
+// Behind a trusted proxy, RemoteAddr already holds the client's address rather
+// than the proxy's; see middleware.RealIP. Only a hash is kept so that raw
+// addresses never reach Redis.
+func visitorID(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	sum := sha256.Sum256([]byte(host + "|" + r.UserAgent()))
+	return hex.EncodeToString(sum[:16])
+}
@@ -2,10 +2,12 @@ package handler
 
 import (
 	"encoding/json"
+	"io"
 	"net/http"
 	"time"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/hafiztri123/src/internal/model"
 	errs "github.com/hafiztri123/src/internal/pkg/error"
 	"github.com/hafiztri123/src/internal/pkg/response"
@@ -15,7 +17,13 @@ import (
 // AuthHandler defines the interface for authentication-related HTTP handlers.
 type AuthHandler interface {
     Register(w http.ResponseWriter, r *http.Request)
+    VerifyEmail(w http.ResponseWriter, r *http.Request)
+    ResendVerification(w http.ResponseWriter, r *http.Request)
+    ForgotPassword(w http.ResponseWriter, r *http.Request)
+    ResetPassword(w http.ResponseWriter, r *http.Request)
     Login(w http.ResponseWriter, r *http.Request)
+    Refresh(w http.ResponseWriter, r *http.Request)
+    Logout(w http.ResponseWriter, r *http.Request)
 }
 
 // authHandler implements the AuthHandler interface.
@@ -34,7 +42,7 @@ func NewAuthHandler(authService service.AuthService) AuthHandler {
 
 // Register godoc
 // @Summary      Register new user
-// @Description  Register a new user with email, password, and full name
+// @Description  Register a new user with email, password, and full name. A link to verify the email address is sent to it
 // @Tags         auth
 // @Accept       json
 // @Produce      json
@@ -56,7 +64,7 @@ func (h *authHandler) Register(w http.ResponseWriter, r *http.Request) {
         return
     }
 
-    err := h.authService.Register(&input)
+    err := h.authService.Register(r.Context(), &input)
     if err != nil {
         HandleErrorResponse(w, err)
         return
@@ -67,9 +75,132 @@ func (h *authHandler) Register(w http.ResponseWriter, r *http.Request) {
     })
 }
 
+// VerifyEmail godoc
+// @Summary      Verify email
+// @Description  Confirm the user's email address with the token from the verification link
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        input body model.VerifyEmailInput true "Verification token"
+// @Success      200  {object}  response.Response
+// @Failure      400  {object}  response.Response{message=string} "Invalid or expired link"
+// @Failure      500  {object}  response.Response{message=string} "Server error"
+// @Router       /auth/verify-email [post]
+func (h *authHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+    var input model.VerifyEmailInput
+    if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+        HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+        return
+    }
+
+    if err := h.validator.Struct(input); err != nil {
+        HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+        return
+    }
+
+    if err := h.authService.VerifyEmail(r.Context(), &input); err != nil {
+        HandleErrorResponse(w, err)
+        return
+    }
+
+    respondWithJSON(w, http.StatusOK, response.Response{
+        Timestamp: time.Now(),
+    })
+}
+
+// ResendVerification godoc
+// @Summary      Resend verification email
+// @Description  Send the current user a new link to verify their email address
+// @Tags         auth
+// @Produce      json
+// @Success      200  {object}  response.Response
+// @Failure      401  {object}  response.Response{message=string} "Unauthorized"
+// @Failure      409  {object}  response.Response{message=string} "Email already verified"
+// @Failure      500  {object}  response.Response{message=string} "Server error"
+// @Security     Bearer
+// @Router       /auth/verify-email/resend [post]
+func (h *authHandler) ResendVerification(w http.ResponseWriter, r *http.Request) {
+    userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+    if err := h.authService.ResendVerification(r.Context(), userID); err != nil {
+        HandleErrorResponse(w, err)
+        return
+    }
+
+    respondWithJSON(w, http.StatusOK, response.Response{
+        Timestamp: time.Now(),
+    })
+}
+
+// ForgotPassword godoc
+// @Summary      Forgot password
+// @Description  Email a single-use link to reset the password. The response is the same whether or not an account uses the address
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        input body model.ForgotPasswordInput true "Account email"
+// @Success      202  {object}  response.Response
+// @Failure      400  {object}  response.Response{message=string} "Invalid input"
+// @Failure      500  {object}  response.Response{message=string} "Server error"
+// @Router       /auth/forgot-password [post]
+func (h *authHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+    var input model.ForgotPasswordInput
+    if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+        HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+        return
+    }
+
+    if err := h.validator.Struct(input); err != nil {
+        HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+        return
+    }
+
+    if err := h.authService.ForgotPassword(r.Context(), &input); err != nil {
+        HandleErrorResponse(w, err)
+        return
+    }
+
+    respondWithJSON(w, http.StatusAccepted, response.Response{
+        Timestamp: time.Now(),
+    })
+}
+
+// ResetPassword godoc
+// @Summary      Reset password
+// @Description  Set a new password with the token from a reset link. The link works once, and every session of the user is logged out
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        input body model.ResetPasswordInput true "Reset token and new password"
+// @Success      200  {object}  response.Response
+// @Failure      400  {object}  response.Response{message=string} "Invalid input, or invalid, used or expired link"
+// @Failure      500  {object}  response.Response{message=string} "Server error"
+// @Router       /auth/reset-password [post]
+func (h *authHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+    var input model.ResetPasswordInput
+    if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+        HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+        return
+    }
+
+    if err := h.validator.Struct(input); err != nil {
+        HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+        return
+    }
+
+    if err := h.authService.ResetPassword(r.Context(), &input); err != nil {
+        HandleErrorResponse(w, err)
+        return
+    }
+
+    respondWithJSON(w, http.StatusOK, response.Response{
+        Timestamp: time.Now(),
+    })
+}
+
 // Login godoc
 // @Summary      Login user
-// @Description  Authenticate user and return JWT token
+// @Description  Authenticate user and return a short-lived JWT access token with a refresh token
 // @Tags         auth
 // @Accept       json
 // @Produce      json
@@ -86,7 +217,43 @@ func (h *authHandler) Login(w http.ResponseWriter, r *http.Request) {
         return
     }
 
-    loginResponse, err := h.authService.Login(&input)
+    loginResponse, err := h.authService.Login(r.Context(), &input)
+    if err != nil {
+        HandleErrorResponse(w, err)
+        return
+    }
+
+    respondWithJSON(w, http.StatusOK, response.Response{
+        Timestamp: time.Now(),
+        Data:      loginResponse,
+    })
+}
+
+// Refresh godoc
+// @Summary      Refresh tokens
+// @Description  Exchange a refresh token for a new access token and refresh token. Each refresh token works once; reusing one logs out every session descended from the same login.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        input body model.RefreshInput true "Refresh token"
+// @Success      200  {object}  response.Response{data=model.LoginResponse}
+// @Failure      400  {object}  response.Response{message=string} "Invalid input"
+// @Failure      401  {object}  response.Response{message=string} "Invalid, expired, revoked or reused refresh token"
+// @Failure      500  {object}  response.Response{message=string} "Server error"
+// @Router       /auth/refresh [post]
+func (h *authHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+    var input model.RefreshInput
+    if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+        HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+        return
+    }
+
+    if err := h.validator.Struct(input); err != nil {
+        HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+        return
+    }
+
+    loginResponse, err := h.authService.Refresh(r.Context(), &input)
     if err != nil {
         HandleErrorResponse(w, err)
         return
@@ -98,3 +265,42 @@ func (h *authHandler) Login(w http.ResponseWriter, r *http.Request) {
     })
 }
 
+// Logout godoc
+// @Summary      Logout user
+// @Description  Revoke the current access token until it expires. Pass the refresh token to end the session for good, or set all to log out of every session.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        input body model.LogoutInput false "Refresh token and log out everywhere"
+// @Success      200  {object}  response.Response
+// @Failure      400  {object}  response.Response{message=string} "Invalid input"
+// @Failure      401  {object}  response.Response{message=string} "Missing, invalid or revoked token"
+// @Failure      500  {object}  response.Response{message=string} "Server error"
+// @Security     Bearer
+// @Router       /auth/logout [post]
+func (h *authHandler) Logout(w http.ResponseWriter, r *http.Request) {
+    var input model.LogoutInput
+    if err := json.NewDecoder(r.Body).Decode(&input); err != nil && err != io.EOF {
+        HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+        return
+    }
+
+    claims := r.Context().Value("user").(jwt.MapClaims)
+    userID := claims["user_id"].(string)
+    jti, _ := claims["jti"].(string)
+
+    expiresAt, err := claims.GetExpirationTime()
+    if err != nil || expiresAt == nil {
+        HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+        return
+    }
+
+    if err := h.authService.Logout(r.Context(), userID, jti, expiresAt.Time, &input); err != nil {
+        HandleErrorResponse(w, err)
+        return
+    }
+
+    respondWithJSON(w, http.StatusOK, response.Response{
+        Timestamp: time.Now(),
+    })
+}
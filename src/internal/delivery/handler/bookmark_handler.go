@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/hafiztri123/src/internal/pkg/response"
+	"github.com/hafiztri123/src/internal/service"
+)
+
+type BookmarkHandler interface {
+	AddBookmark(w http.ResponseWriter, r *http.Request)
+	RemoveBookmark(w http.ResponseWriter, r *http.Request)
+	ListBookmarks(w http.ResponseWriter, r *http.Request)
+}
+
+type bookmarkHandlerImpl struct {
+	bookmarkService service.BookmarkService
+}
+
+func NewBookmarkHandler(bookmarkService service.BookmarkService) BookmarkHandler {
+	return &bookmarkHandlerImpl{
+		bookmarkService: bookmarkService,
+	}
+}
+
+// AddBookmark godoc
+// @Summary      Bookmark event
+// @Description  Save an event to the current user's bookmarks. Bookmarking an event twice has no effect.
+// @Tags         bookmarks
+// @Produce      json
+// @Param        id   path      string  true  "Event ID"
+// @Success      200  {object}  response.Response{data=model.Bookmark}
+// @Failure      401  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Security     Bearer
+// @Router       /events/{id}/bookmark [put]
+func (h *bookmarkHandlerImpl) AddBookmark(w http.ResponseWriter, r *http.Request) {
+	eventID := chi.URLParam(r, "id")
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	bookmark, err := h.bookmarkService.AddBookmark(r.Context(), eventID, userID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+		Data:      bookmark,
+	})
+}
+
+// RemoveBookmark godoc
+// @Summary      Remove bookmark
+// @Description  Remove an event from the current user's bookmarks
+// @Tags         bookmarks
+// @Produce      json
+// @Param        id   path      string  true  "Event ID"
+// @Success      200  {object}  response.Response
+// @Failure      401  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Security     Bearer
+// @Router       /events/{id}/bookmark [delete]
+func (h *bookmarkHandlerImpl) RemoveBookmark(w http.ResponseWriter, r *http.Request) {
+	eventID := chi.URLParam(r, "id")
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	if err := h.bookmarkService.RemoveBookmark(r.Context(), eventID, userID); err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+	})
+}
+
+// ListBookmarks godoc
+// @Summary      List bookmarks
+// @Description  List the current user's bookmarked events, most recent first
+// @Tags         bookmarks
+// @Produce      json
+// @Success      200  {object}  response.Response{data=[]model.Bookmark}
+// @Failure      401  {object}  response.Response
+// @Security     Bearer
+// @Router       /users/bookmarks [get]
+func (h *bookmarkHandlerImpl) ListBookmarks(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	bookmarks, err := h.bookmarkService.ListBookmarks(r.Context(), userID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+		Data:      bookmarks,
+	})
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/hafiztri123/src/internal/model"
 	errs "github.com/hafiztri123/src/internal/pkg/error"
 	"github.com/hafiztri123/src/internal/pkg/response"
@@ -17,19 +18,24 @@ import (
 type CategoryHandler interface {
     CreateCategory(w http.ResponseWriter, r *http.Request)
     UpdateCategory(w http.ResponseWriter, r *http.Request)
+    PatchCategory(w http.ResponseWriter, r *http.Request)
     DeleteCategory(w http.ResponseWriter, r *http.Request)
     GetCategory(w http.ResponseWriter, r *http.Request)
     ListCategories(w http.ResponseWriter, r *http.Request)
+    ListTrash(w http.ResponseWriter, r *http.Request)
+    RestoreCategory(w http.ResponseWriter, r *http.Request)
 }
 
 type categoryHandlerImpl struct {
 	categoryService service.CategoryService
+	translationService service.TranslationService
 	validator *validator.Validate
 }
 
-func NewCategoryHandler(categoryService service.CategoryService) CategoryHandler {
+func NewCategoryHandler(categoryService service.CategoryService, translationService service.TranslationService) CategoryHandler {
 	return &categoryHandlerImpl{
 		categoryService: categoryService,
+		translationService: translationService,
 		validator: validator.New(),
 	}
 }
@@ -49,7 +55,9 @@ func (h *categoryHandlerImpl) CreateCategory(w http.ResponseWriter, r *http.Requ
 	}
 
 
-	err := h.categoryService.CreateCategory(&input)
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	err := h.categoryService.CreateCategory(r.Context(), &input, userID)
 	if err != nil {
         HandleErrorResponse(w, err)
 		return
@@ -70,7 +78,15 @@ func (h *categoryHandlerImpl)  UpdateCategory(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	err = h.categoryService.UpdateCategory(categoryID, &input)
+	version, err := parseIfMatch(r)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	err = h.categoryService.UpdateCategory(r.Context(), categoryID, &input, userID, version)
 	if err != nil {
         HandleErrorResponse(w, err)
 		return
@@ -82,10 +98,45 @@ func (h *categoryHandlerImpl)  UpdateCategory(w http.ResponseWriter, r *http.Req
 
 
 }
+func (h *categoryHandlerImpl) PatchCategory(w http.ResponseWriter, r *http.Request) {
+	categoryID := chi.URLParam(r, "id")
+
+	patch, err := readMergePatch(r)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	version, err := parseIfMatch(r)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	if err := h.categoryService.PatchCategory(r.Context(), categoryID, patch, userID, version); err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, 200, response.Response{
+		Timestamp: time.Now(),
+	})
+}
+
 func (h *categoryHandlerImpl)  DeleteCategory(w http.ResponseWriter, r *http.Request) {
 	categoryID := chi.URLParam(r, "id")
 	
-	err := h.categoryService.DeleteCategory(categoryID)
+	version, err := parseIfMatch(r)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	err = h.categoryService.DeleteCategory(r.Context(), categoryID, userID, version)
 	if err != nil {
         HandleErrorResponse(w, err)
 		return
@@ -99,12 +150,22 @@ func (h *categoryHandlerImpl)  DeleteCategory(w http.ResponseWriter, r *http.Req
 func (h *categoryHandlerImpl)  GetCategory(w http.ResponseWriter, r *http.Request) {
 	categoryID := chi.URLParam(r, "id")
 	
-	category, err := h.categoryService.GetCategory(categoryID)
+	category, err := h.categoryService.GetCategory(r.Context(), categoryID)
 	if err != nil {
         HandleErrorResponse(w, err)
 		return
 	}
 
+	if redirectToSlug(w, r, "/api/v1/categories/", categoryID, category.ID, category.Slug) {
+		return
+	}
+
+	locale := requestLocale(r)
+	h.translationService.LocalizeCategories(r.Context(), locale, category)
+
+	setETag(w, category.Version)
+	setContentLanguage(w, locale)
+
 	respondWithJSON(w, 200, response.Response{
 		Timestamp: time.Now(),
 		Data: category,
@@ -112,15 +173,48 @@ func (h *categoryHandlerImpl)  GetCategory(w http.ResponseWriter, r *http.Reques
 
 }
 func (h *categoryHandlerImpl)  ListCategories(w http.ResponseWriter, r *http.Request){
-	categories, err := h.categoryService.ListCategories()
+	categories, err := h.categoryService.ListCategories(r.Context())
 	if err != nil {
         HandleErrorResponse(w, err)
 		return
 	}
 
+	locale := requestLocale(r)
+	h.translationService.LocalizeCategories(r.Context(), locale, categories...)
+	setContentLanguage(w, locale)
+
 	respondWithJSON(w, 200, response.Response{
 		Timestamp: time.Now(),
 		Data: categories,
 	})
 
-}
\ No newline at end of file
+}
+
+func (h *categoryHandlerImpl) ListTrash(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	categories, err := h.categoryService.ListTrash(r.Context(), userID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, 200, response.Response{
+		Timestamp: time.Now(),
+		Data: categories,
+	})
+}
+
+func (h *categoryHandlerImpl) RestoreCategory(w http.ResponseWriter, r *http.Request) {
+	categoryID := chi.URLParam(r, "id")
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	if err := h.categoryService.RestoreCategory(r.Context(), categoryID, userID); err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, 200, response.Response{
+		Timestamp: time.Now(),
+	})
+}
@@ -19,24 +19,39 @@ import (
 type EventHandler interface {
     CreateEvent(w http.ResponseWriter, r *http.Request)
     UpdateEvent(w http.ResponseWriter, r *http.Request)
+    PatchEvent(w http.ResponseWriter, r *http.Request)
     DeleteEvent(w http.ResponseWriter, r *http.Request)
     GetEvent(w http.ResponseWriter, r *http.Request)
     ListEvents(w http.ResponseWriter, r *http.Request)
     SearchEvents(w http.ResponseWriter, r *http.Request)
+    GetCalendar(w http.ResponseWriter, r *http.Request)
     UploadFile(w http.ResponseWriter, r *http.Request)
+    ListFiles(w http.ResponseWriter, r *http.Request)
+    PatchFile(w http.ResponseWriter, r *http.Request)
+    DeleteFile(w http.ResponseWriter, r *http.Request)
+    ReorderFiles(w http.ResponseWriter, r *http.Request)
+    ListConflicts(w http.ResponseWriter, r *http.Request)
+    ListTrash(w http.ResponseWriter, r *http.Request)
+    RestoreEvent(w http.ResponseWriter, r *http.Request)
+    ListRevisions(w http.ResponseWriter, r *http.Request)
+    RollbackEvent(w http.ResponseWriter, r *http.Request)
 }
 
 // eventHandler implements the EventHandler interface.
 type eventHandler struct {
-    eventService service.EventService
-    validator    *validator.Validate
+    eventService       service.EventService
+    analyticsService   service.AnalyticsService
+    translationService service.TranslationService
+    validator          *validator.Validate
 }
 
 // NewEventHandler creates a new instance of EventHandler.
-func NewEventHandler(eventService service.EventService) EventHandler {
+func NewEventHandler(eventService service.EventService, analyticsService service.AnalyticsService, translationService service.TranslationService) EventHandler {
     return &eventHandler{
-        eventService: eventService,
-        validator:    validator.New(),
+        eventService:       eventService,
+        analyticsService:   analyticsService,
+        translationService: translationService,
+        validator:          validator.New(),
     }
 }
 
@@ -68,7 +83,7 @@ func (h *eventHandler) CreateEvent(w http.ResponseWriter, r *http.Request) {
     claims := r.Context().Value("user").(jwt.MapClaims)
     userID := claims["user_id"].(string)
 
-    err := h.eventService.CreateEvent(&input, userID)
+    err := h.eventService.CreateEvent(r.Context(), &input, userID)
     if err != nil {
         HandleErrorResponse(w, err)
         return
@@ -86,12 +101,14 @@ func (h *eventHandler) CreateEvent(w http.ResponseWriter, r *http.Request) {
 // @Accept       json
 // @Produce      json
 // @Param        id   path      string  true  "Event ID"
+// @Param        If-Match header   string  false "ETag returned by GET"
 // @Param        input body service.UpdateEventInput true "Event Details"
 // @Success      200  {object}  response.Response
 // @Failure      400  {object}  response.Response
 // @Failure      401  {object}  response.Response
 // @Failure      403  {object}  response.Response
 // @Failure      404  {object}  response.Response
+// @Failure      412  {object}  response.Response
 // @Failure      500  {object}  response.Response
 // @Security     Bearer
 // @Router       /events/{id} [put]
@@ -108,10 +125,16 @@ func (h *eventHandler) UpdateEvent(w http.ResponseWriter, r *http.Request) {
         return
     }
 
+    version, err := parseIfMatch(r)
+    if err != nil {
+        HandleErrorResponse(w, err)
+        return
+    }
+
     claims := r.Context().Value("user").(jwt.MapClaims)
     userID := claims["user_id"].(string)
 
-    err := h.eventService.UpdateEvent(eventID, &input, userID)
+    err = h.eventService.UpdateEvent(r.Context(), eventID, &input, userID, version)
     if err != nil {
         HandleErrorResponse(w, err)
         return
@@ -122,26 +145,81 @@ func (h *eventHandler) UpdateEvent(w http.ResponseWriter, r *http.Request) {
     })
 }
 
+// PatchEvent godoc
+// @Summary      Patch event
+// @Description  Partially update an event with an RFC 7396 JSON merge patch. Omitted fields are kept and null clears a field.
+// @Tags         events
+// @Accept       application/merge-patch+json
+// @Produce      json
+// @Param        id   path      string  true  "Event ID"
+// @Param        If-Match header   string  false "ETag returned by GET"
+// @Param        input body model.EventSnapshot true "Merge patch"
+// @Success      200  {object}  response.Response
+// @Failure      400  {object}  response.Response
+// @Failure      401  {object}  response.Response
+// @Failure      403  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Failure      409  {object}  response.Response
+// @Failure      412  {object}  response.Response
+// @Failure      500  {object}  response.Response
+// @Security     Bearer
+// @Router       /events/{id} [patch]
+func (h *eventHandler) PatchEvent(w http.ResponseWriter, r *http.Request) {
+    eventID := chi.URLParam(r, "id")
+
+    patch, err := readMergePatch(r)
+    if err != nil {
+        HandleErrorResponse(w, err)
+        return
+    }
+
+    version, err := parseIfMatch(r)
+    if err != nil {
+        HandleErrorResponse(w, err)
+        return
+    }
+
+    claims := r.Context().Value("user").(jwt.MapClaims)
+    userID := claims["user_id"].(string)
+
+    if err := h.eventService.PatchEvent(r.Context(), eventID, patch, userID, version); err != nil {
+        HandleErrorResponse(w, err)
+        return
+    }
+
+    respondWithJSON(w, http.StatusOK, response.Response{
+        Timestamp: time.Now(),
+    })
+}
+
 // DeleteEvent godoc
 // @Summary      Delete event
 // @Description  Delete an existing event
 // @Tags         events
 // @Produce      json
 // @Param        id   path      string  true  "Event ID"
+// @Param        If-Match header   string  false "ETag returned by GET"
 // @Success      204  {object}  response.Response
 // @Failure      401  {object}  response.Response
 // @Failure      403  {object}  response.Response
 // @Failure      404  {object}  response.Response
+// @Failure      412  {object}  response.Response
 // @Failure      500  {object}  response.Response
 // @Security     Bearer
 // @Router       /events/{id} [delete]
 func (h *eventHandler) DeleteEvent(w http.ResponseWriter, r *http.Request) {
     eventID := chi.URLParam(r, "id")
 
+    version, err := parseIfMatch(r)
+    if err != nil {
+        HandleErrorResponse(w, err)
+        return
+    }
+
     claims := r.Context().Value("user").(jwt.MapClaims)
     userID := claims["user_id"].(string)
 
-    err := h.eventService.DeleteEvent(eventID, userID)
+    err = h.eventService.DeleteEvent(r.Context(), eventID, userID, version)
     if err != nil {
         HandleErrorResponse(w, err)
         return
@@ -154,23 +232,38 @@ func (h *eventHandler) DeleteEvent(w http.ResponseWriter, r *http.Request) {
 
 // GetEvent godoc
 // @Summary      Get event details
-// @Description  Get details of a specific event
+// @Description  Get details of a specific event by ID or slug. Old slugs redirect to the current one. The title and description are translated into the negotiated locale when a translation exists.
 // @Tags         events
 // @Produce      json
-// @Param        id   path      string  true  "Event ID"
+// @Param        id   path      string  true  "Event ID or slug"
+// @Param        lang query     string  false "Preferred locale, overrides Accept-Language"
 // @Success      200  {object}  response.Response{data=model.Event}
+// @Success      301  "Moved to the current slug"
+// @Header       200  {string}  ETag  "Current version of the event"
 // @Failure      404  {object}  response.Response
 // @Failure      500  {object}  response.Response
 // @Router       /events/{id} [get]
 func (h *eventHandler) GetEvent(w http.ResponseWriter, r *http.Request) {
     eventID := chi.URLParam(r, "id")
 
-    event, err := h.eventService.GetEvent(eventID)
+    event, err := h.eventService.GetEvent(r.Context(), eventID)
     if err != nil {
         HandleErrorResponse(w, err)
         return
     }
 
+    if redirectToSlug(w, r, "/api/v1/events/", eventID, event.ID, event.Slug) {
+        return
+    }
+
+    h.analyticsService.RecordView(r.Context(), event.ID, visitorID(r))
+
+    locale := requestLocale(r)
+    h.translationService.LocalizeEvents(r.Context(), locale, event)
+
+    setETag(w, event.Version)
+    setContentLanguage(w, locale)
+
     respondWithJSON(w, http.StatusOK, response.Response{
         Timestamp: time.Now(),
         Data:      event,
@@ -184,6 +277,7 @@ func (h *eventHandler) GetEvent(w http.ResponseWriter, r *http.Request) {
 // @Produce      json
 // @Param        page      query     int  false  "Page number"  minimum(1)
 // @Param        page_size query     int  false  "Page size"    minimum(1)  maximum(100)
+// @Param        lang      query     string  false  "Preferred locale, overrides Accept-Language"
 // @Success      200  {object}  response.Response{data=[]model.Event}
 // @Failure      500  {object}  response.Response
 // @Router       /events [get]
@@ -203,12 +297,16 @@ func (h *eventHandler) ListEvents(w http.ResponseWriter, r *http.Request) {
         PageSize: pageSize,
     }
 
-    events, err := h.eventService.ListEvents(input)
+    events, err := h.eventService.ListEvents(r.Context(), input)
     if err != nil {
         HandleErrorResponse(w, err)
         return
     }
 
+    locale := requestLocale(r)
+    h.translationService.LocalizeEvents(r.Context(), locale, events...)
+    setContentLanguage(w, locale)
+
     respondWithJSON(w, http.StatusOK, response.Response{
         Timestamp: time.Now(),
         Data:      events,
@@ -221,7 +319,7 @@ func (h *eventHandler) ListEvents(w http.ResponseWriter, r *http.Request) {
 // @Tags         events
 // @Accept       json
 // @Produce      json
-// @Param        query      query     string  false  "Search term in title and description"
+// @Param        query      query     string  false  "Full-text search in title and description, in every supported language"
 // @Param        start_date query     string  false  "Filter events starting after this date (RFC3339)"
 // @Param        end_date   query     string  false  "Filter events ending before this date (RFC3339)"
 // @Param        creator    query     string  false  "Filter by creator ID"
@@ -229,6 +327,7 @@ func (h *eventHandler) ListEvents(w http.ResponseWriter, r *http.Request) {
 // @Param        page_size  query     int     false  "Page size"    minimum(1)  maximum(100)
 // @Param        sort_by    query     string  false  "Sort field (title, start_date, end_date, created_at)"
 // @Param        sort_dir   query     string  false  "Sort direction (asc, desc)"
+// @Param        lang       query     string  false  "Preferred locale, overrides Accept-Language"
 // @Success      200  {object}  response.Response{data=service.SearchEventsOutput}
 // @Failure      400  {object}  response.Response
 // @Failure      500  {object}  response.Response
@@ -277,18 +376,126 @@ func (h *eventHandler) SearchEvents(w http.ResponseWriter, r *http.Request) {
         SortDir:   sortDir,
     }
 
-    result, err := h.eventService.SearchEvents(input)
+    result, err := h.eventService.SearchEvents(r.Context(), input)
     if err != nil {
         HandleErrorResponse(w, err)
         return
     }
 
+    locale := requestLocale(r)
+    h.translationService.LocalizeEvents(r.Context(), locale, result.Events...)
+    setContentLanguage(w, locale)
+
     respondWithJSON(w, http.StatusOK, response.Response{
         Timestamp: time.Now(),
         Data:      result,
     })
 }
 
+// GetCalendar godoc
+// @Summary      Calendar view
+// @Description  Get events grouped into day, week or month buckets. Multi-day events appear in every bucket they span.
+// @Tags         events
+// @Produce      json
+// @Param        from        query     string  true   "Range start (RFC3339 or YYYY-MM-DD)"
+// @Param        to          query     string  true   "Range end, exclusive (RFC3339 or YYYY-MM-DD)"
+// @Param        granularity query     string  false  "Bucket size (day, week, month)"  default(day)
+// @Param        tz          query     string  false  "IANA time zone used for bucket boundaries"  default(UTC)
+// @Param        query       query     string  false  "Full-text search in title and description, in every supported language"
+// @Param        creator     query     string  false  "Filter by creator ID"
+// @Param        lang        query     string  false  "Preferred locale, overrides Accept-Language"
+// @Success      200  {object}  response.Response{data=model.CalendarOutput}
+// @Failure      400  {object}  response.Response
+// @Failure      500  {object}  response.Response
+// @Router       /events/calendar [get]
+func (h *eventHandler) GetCalendar(w http.ResponseWriter, r *http.Request) {
+    params := r.URL.Query()
+
+    tz := params.Get("tz")
+    if tz == "" {
+        tz = "UTC"
+    }
+
+    location, err := time.LoadLocation(tz)
+    if err != nil {
+        HandleErrorResponse(w, errs.NewBadRequestError("tz must be an IANA time zone"))
+        return
+    }
+
+    from, err := parseCalendarTime(params.Get("from"), location)
+    if err != nil {
+        HandleErrorResponse(w, errs.NewBadRequestError("from must be RFC3339 or YYYY-MM-DD"))
+        return
+    }
+
+    to, err := parseCalendarTime(params.Get("to"), location)
+    if err != nil {
+        HandleErrorResponse(w, errs.NewBadRequestError("to must be RFC3339 or YYYY-MM-DD"))
+        return
+    }
+
+    granularity := params.Get("granularity")
+    if granularity == "" {
+        granularity = model.CalendarDay
+    }
+
+    input := &model.CalendarInput{
+        From:        from,
+        To:          to,
+        Granularity: granularity,
+        Location:    location,
+        Filters: model.SearchEventsInput{
+            Query:   params.Get("query"),
+            Creator: params.Get("creator"),
+        },
+    }
+
+    if err := h.validator.Struct(input); err != nil {
+        HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+        return
+    }
+
+    calendar, err := h.eventService.GetCalendar(r.Context(), input)
+    if err != nil {
+        HandleErrorResponse(w, err)
+        return
+    }
+
+    locale := requestLocale(r)
+    h.translationService.LocalizeEvents(r.Context(), locale, calendarEvents(calendar)...)
+    setContentLanguage(w, locale)
+
+    respondWithJSON(w, http.StatusOK, response.Response{
+        Timestamp: time.Now(),
+        Data:      calendar,
+    })
+}
+
+// calendarEvents returns each event of the calendar once, even when it spans
+// several buckets.
+func calendarEvents(calendar *model.CalendarOutput) []*model.Event {
+    var events []*model.Event
+    seen := make(map[*model.Event]bool)
+    for _, bucket := range calendar.Buckets {
+        for _, event := range bucket.Events {
+            if !seen[event] {
+                seen[event] = true
+                events = append(events, event)
+            }
+        }
+    }
+    return events
+}
+
+// parseCalendarTime accepts an RFC3339 timestamp or a plain date, which is taken as
+// midnight in the requested location.
+func parseCalendarTime(value string, location *time.Location) (time.Time, error) {
+    if t, err := time.Parse(time.RFC3339, value); err == nil {
+        return t, nil
+    }
+    return time.ParseInLocation("2006-01-02", value, location)
+}
+
 func (h *eventHandler) UploadFile(w http.ResponseWriter, r *http.Request) {
     eventID := chi.URLParam(r, "id")
 
@@ -309,11 +516,15 @@ func (h *eventHandler) UploadFile(w http.ResponseWriter, r *http.Request) {
         return
     }
 
+    claims := r.Context().Value("user").(jwt.MapClaims)
+    userID := claims["user_id"].(string)
+
     if err := h.eventService.UploadFile(r.Context(), file, model.UploadFile{
         FileName: header.Filename,
         FileType: header.Header.Get("Content-Type"),
-    }, eventID); err != nil {
+    }, eventID, userID); err != nil {
         HandleErrorResponse(w, err)
+        return
     }
 
     respondWithJSON(w, http.StatusOK, response.Response{
@@ -323,4 +534,316 @@ func (h *eventHandler) UploadFile(w http.ResponseWriter, r *http.Request) {
 
 }
 
+// ListConflicts godoc
+// @Summary      List schedule conflicts
+// @Description  List overlapping events sharing a venue, a creator, or the current user's registrations within a time range
+// @Tags         events
+// @Produce      json
+// @Param        start_date query     string  true   "Range start (RFC3339)"
+// @Param        end_date   query     string  true   "Range end (RFC3339)"
+// @Param        venue      query     string  false  "Only check this venue"
+// @Param        creator    query     string  false  "Only check events of this creator ID"
+// @Success      200  {object}  response.Response{data=[]model.ScheduleConflict}
+// @Failure      400  {object}  response.Response
+// @Failure      401  {object}  response.Response
+// @Failure      500  {object}  response.Response
+// @Security     Bearer
+// @Router       /events/conflicts [get]
+func (h *eventHandler) ListConflicts(w http.ResponseWriter, r *http.Request) {
+    startDate, err := time.Parse(time.RFC3339, r.URL.Query().Get("start_date"))
+    if err != nil {
+        HandleErrorResponse(w, errs.NewBadRequestError("start_date must be an RFC3339 timestamp"))
+        return
+    }
+
+    endDate, err := time.Parse(time.RFC3339, r.URL.Query().Get("end_date"))
+    if err != nil {
+        HandleErrorResponse(w, errs.NewBadRequestError("end_date must be an RFC3339 timestamp"))
+        return
+    }
+
+    input := &model.ConflictsInput{
+        StartDate: startDate,
+        EndDate:   endDate,
+        Venue:     r.URL.Query().Get("venue"),
+        Creator:   r.URL.Query().Get("creator"),
+    }
+
+    if err := h.validator.Struct(input); err != nil {
+        HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+        return
+    }
+
+    claims := r.Context().Value("user").(jwt.MapClaims)
+    userID := claims["user_id"].(string)
+
+    conflicts, err := h.eventService.ListConflicts(r.Context(), input, userID)
+    if err != nil {
+        HandleErrorResponse(w, err)
+        return
+    }
+
+    respondWithJSON(w, http.StatusOK, response.Response{
+        Timestamp: time.Now(),
+        Data:      conflicts,
+    })
+}
+
+// ListTrash godoc
+// @Summary      List deleted events
+// @Description  List the current user's events that are in the trash
+// @Tags         events
+// @Produce      json
+// @Success      200  {object}  response.Response{data=[]model.Event}
+// @Failure      401  {object}  response.Response
+// @Failure      500  {object}  response.Response
+// @Security     Bearer
+// @Router       /events/trash [get]
+func (h *eventHandler) ListTrash(w http.ResponseWriter, r *http.Request) {
+    claims := r.Context().Value("user").(jwt.MapClaims)
+    userID := claims["user_id"].(string)
+
+    events, err := h.eventService.ListTrash(r.Context(), userID)
+    if err != nil {
+        HandleErrorResponse(w, err)
+        return
+    }
+
+    respondWithJSON(w, http.StatusOK, response.Response{
+        Timestamp: time.Now(),
+        Data:      events,
+    })
+}
 
+// RestoreEvent godoc
+// @Summary      Restore deleted event
+// @Description  Move an event out of the trash
+// @Tags         events
+// @Produce      json
+// @Param        id   path      string  true  "Event ID"
+// @Success      200  {object}  response.Response
+// @Failure      401  {object}  response.Response
+// @Failure      403  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Failure      500  {object}  response.Response
+// @Security     Bearer
+// @Router       /events/{id}/restore [post]
+func (h *eventHandler) RestoreEvent(w http.ResponseWriter, r *http.Request) {
+    eventID := chi.URLParam(r, "id")
+
+    claims := r.Context().Value("user").(jwt.MapClaims)
+    userID := claims["user_id"].(string)
+
+    if err := h.eventService.RestoreEvent(r.Context(), eventID, userID); err != nil {
+        HandleErrorResponse(w, err)
+        return
+    }
+
+    respondWithJSON(w, http.StatusOK, response.Response{
+        Timestamp: time.Now(),
+    })
+}
+
+// ListRevisions godoc
+// @Summary      List event revisions
+// @Description  List the revision history of an event with field-level diffs, newest first
+// @Tags         events
+// @Produce      json
+// @Param        id   path      string  true  "Event ID"
+// @Success      200  {object}  response.Response{data=[]model.EventRevision}
+// @Failure      401  {object}  response.Response
+// @Failure      403  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Failure      500  {object}  response.Response
+// @Security     Bearer
+// @Router       /events/{id}/revisions [get]
+func (h *eventHandler) ListRevisions(w http.ResponseWriter, r *http.Request) {
+    eventID := chi.URLParam(r, "id")
+
+    claims := r.Context().Value("user").(jwt.MapClaims)
+    userID := claims["user_id"].(string)
+
+    revisions, err := h.eventService.ListRevisions(r.Context(), eventID, userID)
+    if err != nil {
+        HandleErrorResponse(w, err)
+        return
+    }
+
+    respondWithJSON(w, http.StatusOK, response.Response{
+        Timestamp: time.Now(),
+        Data:      revisions,
+    })
+}
+
+// RollbackEvent godoc
+// @Summary      Roll back event
+// @Description  Restore an event to the state recorded by the chosen revision
+// @Tags         events
+// @Produce      json
+// @Param        id          path      string  true  "Event ID"
+// @Param        revisionId  path      string  true  "Revision ID"
+// @Success      200  {object}  response.Response
+// @Failure      401  {object}  response.Response
+// @Failure      403  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Failure      409  {object}  response.Response
+// @Failure      500  {object}  response.Response
+// @Security     Bearer
+// @Router       /events/{id}/revisions/{revisionId}/rollback [post]
+func (h *eventHandler) RollbackEvent(w http.ResponseWriter, r *http.Request) {
+    eventID := chi.URLParam(r, "id")
+    revisionID := chi.URLParam(r, "revisionId")
+
+    claims := r.Context().Value("user").(jwt.MapClaims)
+    userID := claims["user_id"].(string)
+
+    if err := h.eventService.RollbackEvent(r.Context(), eventID, revisionID, userID); err != nil {
+        HandleErrorResponse(w, err)
+        return
+    }
+
+    respondWithJSON(w, http.StatusOK, response.Response{
+        Timestamp: time.Now(),
+    })
+}
+
+// ListFiles godoc
+// @Summary      List event files
+// @Description  List the files attached to an event in display order
+// @Tags         events
+// @Produce      json
+// @Param        id   path      string  true  "Event ID"
+// @Success      200  {object}  response.Response{data=[]model.File}
+// @Failure      404  {object}  response.Response
+// @Failure      500  {object}  response.Response
+// @Router       /events/{id}/files [get]
+func (h *eventHandler) ListFiles(w http.ResponseWriter, r *http.Request) {
+    eventID := chi.URLParam(r, "id")
+
+    files, err := h.eventService.ListFiles(r.Context(), eventID)
+    if err != nil {
+        HandleErrorResponse(w, err)
+        return
+    }
+
+    respondWithJSON(w, http.StatusOK, response.Response{
+        Timestamp: time.Now(),
+        Data:      files,
+    })
+}
+
+// PatchFile godoc
+// @Summary      Update event file metadata
+// @Description  Rename an event file with an RFC 7396 JSON merge patch
+// @Tags         events
+// @Accept       application/merge-patch+json
+// @Produce      json
+// @Param        id      path      string  true  "Event ID"
+// @Param        fileId  path      string  true  "File ID"
+// @Param        input body model.FileSnapshot true "Merge patch"
+// @Success      200  {object}  response.Response{data=model.File}
+// @Failure      400  {object}  response.Response
+// @Failure      401  {object}  response.Response
+// @Failure      403  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Failure      500  {object}  response.Response
+// @Security     Bearer
+// @Router       /events/{id}/files/{fileId} [patch]
+func (h *eventHandler) PatchFile(w http.ResponseWriter, r *http.Request) {
+    eventID := chi.URLParam(r, "id")
+    fileID := chi.URLParam(r, "fileId")
+
+    patch, err := readMergePatch(r)
+    if err != nil {
+        HandleErrorResponse(w, err)
+        return
+    }
+
+    claims := r.Context().Value("user").(jwt.MapClaims)
+    userID := claims["user_id"].(string)
+
+    file, err := h.eventService.PatchFile(r.Context(), eventID, fileID, patch, userID)
+    if err != nil {
+        HandleErrorResponse(w, err)
+        return
+    }
+
+    respondWithJSON(w, http.StatusOK, response.Response{
+        Timestamp: time.Now(),
+        Data:      file,
+    })
+}
+
+// DeleteFile godoc
+// @Summary      Delete event file
+// @Description  Delete an event file and its stored object
+// @Tags         events
+// @Produce      json
+// @Param        id      path      string  true  "Event ID"
+// @Param        fileId  path      string  true  "File ID"
+// @Success      204  {object}  response.Response
+// @Failure      401  {object}  response.Response
+// @Failure      403  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Failure      500  {object}  response.Response
+// @Security     Bearer
+// @Router       /events/{id}/files/{fileId} [delete]
+func (h *eventHandler) DeleteFile(w http.ResponseWriter, r *http.Request) {
+    eventID := chi.URLParam(r, "id")
+    fileID := chi.URLParam(r, "fileId")
+
+    claims := r.Context().Value("user").(jwt.MapClaims)
+    userID := claims["user_id"].(string)
+
+    if err := h.eventService.DeleteFile(r.Context(), eventID, fileID, userID); err != nil {
+        HandleErrorResponse(w, err)
+        return
+    }
+
+    respondWithJSON(w, http.StatusNoContent, response.Response{
+        Timestamp: time.Now(),
+    })
+}
+
+// ReorderFiles godoc
+// @Summary      Reorder event files
+// @Description  Set the display order of an event's files. Every file must be listed exactly once.
+// @Tags         events
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Event ID"
+// @Param        input body model.ReorderFilesInput true "File IDs in display order"
+// @Success      200  {object}  response.Response
+// @Failure      400  {object}  response.Response
+// @Failure      401  {object}  response.Response
+// @Failure      403  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Failure      500  {object}  response.Response
+// @Security     Bearer
+// @Router       /events/{id}/files/order [put]
+func (h *eventHandler) ReorderFiles(w http.ResponseWriter, r *http.Request) {
+    eventID := chi.URLParam(r, "id")
+
+    var input model.ReorderFilesInput
+    if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+        HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+        return
+    }
+
+    if err := h.validator.Struct(input); err != nil {
+        HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+        return
+    }
+
+    claims := r.Context().Value("user").(jwt.MapClaims)
+    userID := claims["user_id"].(string)
+
+    if err := h.eventService.ReorderFiles(r.Context(), eventID, &input, userID); err != nil {
+        HandleErrorResponse(w, err)
+        return
+    }
+
+    respondWithJSON(w, http.StatusOK, response.Response{
+        Timestamp: time.Now(),
+    })
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/hafiztri123/src/internal/model"
+	"github.com/hafiztri123/src/internal/pkg/response"
+	"github.com/hafiztri123/src/internal/service"
+)
+
+type InvoiceHandler interface {
+	GetInvoice(w http.ResponseWriter, r *http.Request)
+	DownloadInvoice(w http.ResponseWriter, r *http.Request)
+	DownloadReceipt(w http.ResponseWriter, r *http.Request)
+}
+
+type invoiceHandlerImpl struct {
+	invoiceService service.InvoiceService
+}
+
+func NewInvoiceHandler(invoiceService service.InvoiceService) InvoiceHandler {
+	return &invoiceHandlerImpl{
+		invoiceService: invoiceService,
+	}
+}
+
+// GetInvoice godoc
+// @Summary      Get invoice
+// @Description  Get the invoice of a paid order, for its buyer or the event organizer
+// @Tags         invoices
+// @Produce      json
+// @Param        id   path      string  true  "Order ID"
+// @Success      200  {object}  response.Response{data=model.Invoice}
+// @Failure      400  {object}  response.Response
+// @Failure      403  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Security     Bearer
+// @Router       /orders/{id}/invoice [get]
+func (h *invoiceHandlerImpl) GetInvoice(w http.ResponseWriter, r *http.Request) {
+	orderID := chi.URLParam(r, "id")
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	invoice, err := h.invoiceService.GetInvoice(r.Context(), orderID, userID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+		Data:      invoice,
+	})
+}
+
+// DownloadInvoice godoc
+// @Summary      Download invoice PDF
+// @Description  Redirect to a short-lived signed link to the invoice PDF of a paid order
+// @Tags         invoices
+// @Param        id   path  string  true  "Order ID"
+// @Success      302
+// @Failure      403  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Security     Bearer
+// @Router       /orders/{id}/invoice/download [get]
+func (h *invoiceHandlerImpl) DownloadInvoice(w http.ResponseWriter, r *http.Request) {
+	h.download(w, r, model.DocumentInvoice)
+}
+
+// DownloadReceipt godoc
+// @Summary      Download receipt PDF
+// @Description  Redirect to a short-lived signed link to the receipt PDF of a paid order
+// @Tags         invoices
+// @Param        id   path  string  true  "Order ID"
+// @Success      302
+// @Failure      403  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Security     Bearer
+// @Router       /orders/{id}/receipt/download [get]
+func (h *invoiceHandlerImpl) DownloadReceipt(w http.ResponseWriter, r *http.Request) {
+	h.download(w, r, model.DocumentReceipt)
+}
+
+func (h *invoiceHandlerImpl) download(w http.ResponseWriter, r *http.Request, document string) {
+	orderID := chi.URLParam(r, "id")
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	url, err := h.invoiceService.DocumentURL(r.Context(), orderID, document, userID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	http.Redirect(w, r, url, http.StatusFound)
+}
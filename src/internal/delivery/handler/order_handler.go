@@ -0,0 +1,387 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/hafiztri123/src/internal/model"
+	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"github.com/hafiztri123/src/internal/pkg/payment"
+	"github.com/hafiztri123/src/internal/pkg/response"
+	"github.com/hafiztri123/src/internal/service"
+)
+
+const maxWebhookSize = 1 << 20
+
+type OrderHandler interface {
+	CreateTicketType(w http.ResponseWriter, r *http.Request)
+	ListTicketTypes(w http.ResponseWriter, r *http.Request)
+	GetTicketLimit(w http.ResponseWriter, r *http.Request)
+	SetTicketLimit(w http.ResponseWriter, r *http.Request)
+	Checkout(w http.ResponseWriter, r *http.Request)
+	PreviewPromoCode(w http.ResponseWriter, r *http.Request)
+	GetOrder(w http.ResponseWriter, r *http.Request)
+	ListOrders(w http.ResponseWriter, r *http.Request)
+	Webhook(w http.ResponseWriter, r *http.Request)
+}
+
+type orderHandlerImpl struct {
+	orderService service.OrderService
+	validator    *validator.Validate
+}
+
+func NewOrderHandler(orderService service.OrderService) OrderHandler {
+	return &orderHandlerImpl{
+		orderService: orderService,
+		validator:    validator.New(),
+	}
+}
+
+// CreateTicketType godoc
+// @Summary      Create ticket type
+// @Description  Add a purchasable ticket type to an event. Prices are in minor currency units.
+// @Tags         orders
+// @Accept       json
+// @Produce      json
+// @Param        id     path  string                       true  "Event ID"
+// @Param        input  body  model.CreateTicketTypeInput  true  "Ticket type"
+// @Success      201  {object}  response.Response{data=model.TicketType}
+// @Failure      400  {object}  response.Response
+// @Failure      403  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Security     Bearer
+// @Router       /events/{id}/ticket-types [post]
+func (h *orderHandlerImpl) CreateTicketType(w http.ResponseWriter, r *http.Request) {
+	eventID := chi.URLParam(r, "id")
+
+	var input model.CreateTicketTypeInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+		return
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+		return
+	}
+
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	ticketType, err := h.orderService.CreateTicketType(r.Context(), eventID, &input, userID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, response.Response{
+		Timestamp: time.Now(),
+		Data:      ticketType,
+	})
+}
+
+// ListTicketTypes godoc
+// @Summary      List ticket types
+// @Description  List the ticket types of an event with their remaining inventory
+// @Tags         orders
+// @Produce      json
+// @Param        id   path      string  true  "Event ID"
+// @Success      200  {object}  response.Response{data=[]model.TicketType}
+// @Failure      404  {object}  response.Response
+// @Router       /events/{id}/ticket-types [get]
+func (h *orderHandlerImpl) ListTicketTypes(w http.ResponseWriter, r *http.Request) {
+	eventID := chi.URLParam(r, "id")
+
+	ticketTypes, err := h.orderService.ListTicketTypes(r.Context(), eventID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+		Data:      ticketTypes,
+	})
+}
+
+// GetTicketLimit godoc
+// @Summary      Get ticket limit
+// @Description  Get how many tickets one user may hold for an event. 0 means no limit.
+// @Tags         orders
+// @Produce      json
+// @Param        id   path      string  true  "Event ID"
+// @Success      200  {object}  response.Response{data=model.TicketLimit}
+// @Failure      404  {object}  response.Response
+// @Router       /events/{id}/ticket-limit [get]
+func (h *orderHandlerImpl) GetTicketLimit(w http.ResponseWriter, r *http.Request) {
+	limit, err := h.orderService.GetTicketLimit(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+		Data:      limit,
+	})
+}
+
+// SetTicketLimit godoc
+// @Summary      Set ticket limit
+// @Description  Cap how many tickets one user may hold for an event, counting pending and paid orders. 0 means no limit. Only the event creator may change it.
+// @Tags         orders
+// @Accept       json
+// @Produce      json
+// @Param        id     path      string                  true  "Event ID"
+// @Param        input  body      model.TicketLimitInput  true  "Ticket limit"
+// @Success      200  {object}  response.Response{data=model.TicketLimit}
+// @Failure      400  {object}  response.Response
+// @Failure      401  {object}  response.Response
+// @Failure      403  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Security     Bearer
+// @Router       /events/{id}/ticket-limit [put]
+func (h *orderHandlerImpl) SetTicketLimit(w http.ResponseWriter, r *http.Request) {
+	var input model.TicketLimitInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+		return
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+		return
+	}
+
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	limit, err := h.orderService.SetTicketLimit(r.Context(), chi.URLParam(r, "id"), &input, userID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+		Data:      limit,
+	})
+}
+
+// Checkout godoc
+// @Summary      Checkout
+// @Description  Reserve tickets and open a payment intent. The reservation expires if payment does not complete in time.
+// @Tags         orders
+// @Accept       json
+// @Produce      json
+// @Param        input  body  model.CheckoutInput  true  "Tickets to buy"
+// @Success      201  {object}  response.Response{data=model.CheckoutOutput}
+// @Failure      400  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Failure      409  {object}  response.Response
+// @Security     Bearer
+// @Router       /orders [post]
+func (h *orderHandlerImpl) Checkout(w http.ResponseWriter, r *http.Request) {
+	var input model.CheckoutInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+		return
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+		return
+	}
+
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	output, err := h.orderService.Checkout(r.Context(), &input, userID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, response.Response{
+		Timestamp: time.Now(),
+		Data:      output,
+	})
+}
+
+// PreviewPromoCode godoc
+// @Summary      Validate promo code
+// @Description  Check a promo code against the tickets in a cart and preview the discounted price
+// @Tags         promo-codes
+// @Accept       json
+// @Produce      json
+// @Param        input  body  model.PromoPreviewInput  true  "Code and tickets"
+// @Success      200  {object}  response.Response{data=model.PromoPreviewOutput}
+// @Failure      400  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Failure      409  {object}  response.Response
+// @Security     Bearer
+// @Router       /promo-codes/validate [post]
+func (h *orderHandlerImpl) PreviewPromoCode(w http.ResponseWriter, r *http.Request) {
+	var input model.PromoPreviewInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+		return
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+		return
+	}
+
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	preview, err := h.orderService.PreviewPromoCode(r.Context(), &input, userID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+		Data:      preview,
+	})
+}
+
+// GetOrder godoc
+// @Summary      Get order
+// @Description  Get one of the current user's orders with its items and payments
+// @Tags         orders
+// @Produce      json
+// @Param        id   path      string  true  "Order ID"
+// @Success      200  {object}  response.Response{data=model.Order}
+// @Failure      403  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Security     Bearer
+// @Router       /orders/{id} [get]
+func (h *orderHandlerImpl) GetOrder(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	order, err := h.orderService.GetOrder(r.Context(), id, userID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+		Data:      order,
+	})
+}
+
+// ListOrders godoc
+// @Summary      List orders
+// @Description  List the current user's orders, newest first
+// @Tags         orders
+// @Produce      json
+// @Success      200  {object}  response.Response{data=[]model.Order}
+// @Failure      401  {object}  response.Response
+// @Security     Bearer
+// @Router       /orders [get]
+func (h *orderHandlerImpl) ListOrders(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	orders, err := h.orderService.ListOrders(r.Context(), userID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+		Data:      orders,
+	})
+}
+
+// Webhook godoc
+// @Summary      Payment webhook
+// @Description  Receive a signed notification from the payment provider and confirm or fail the order
+// @Tags         orders
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  response.Response
+// @Failure      400  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Router       /payments/webhook [post]
+func (h *orderHandlerImpl) Webhook(w http.ResponseWriter, r *http.Request) {
+	payload, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookSize))
+	if err != nil {
+		HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+		return
+	}
+
+	if err := h.orderService.HandleWebhook(r.Context(), payload, r.Header); err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+	})
+}
+
+// FakePaymentHandler completes intents of the in-process fake provider. It is only
+// routed when the fake provider is configured, which is refused outside development.
+type FakePaymentHandler interface {
+	Simulate(w http.ResponseWriter, r *http.Request)
+}
+
+type fakePaymentHandlerImpl struct {
+	provider     *payment.FakeProvider
+	orderService service.OrderService
+}
+
+func NewFakePaymentHandler(provider *payment.FakeProvider, orderService service.OrderService) FakePaymentHandler {
+	return &fakePaymentHandlerImpl{
+		provider:     provider,
+		orderService: orderService,
+	}
+}
+
+type simulatePaymentInput struct {
+	Succeed bool `json:"succeed"`
+}
+
+// Simulate godoc
+// @Summary      Simulate payment
+// @Description  Settle a fake payment intent and deliver the signed webhook, for offline testing
+// @Tags         orders
+// @Accept       json
+// @Produce      json
+// @Param        intentId  path  string  true  "Intent ID"
+// @Success      200  {object}  response.Response
+// @Failure      400  {object}  response.Response
+// @Failure      401  {object}  response.Response
+// @Security     Bearer
+// @Router       /payments/fake/{intentId} [post]
+func (h *fakePaymentHandlerImpl) Simulate(w http.ResponseWriter, r *http.Request) {
+	intentID := chi.URLParam(r, "intentId")
+
+	var input simulatePaymentInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+		return
+	}
+
+	payload, header, err := h.provider.Simulate(intentID, input.Succeed)
+	if err != nil {
+		HandleErrorResponse(w, errs.NewNotFoundError("Intent not found"))
+		return
+	}
+
+	if err := h.orderService.HandleWebhook(r.Context(), payload, header); err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+	})
+}
@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/hafiztri123/src/internal/model"
+	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"github.com/hafiztri123/src/internal/pkg/response"
+	"github.com/hafiztri123/src/internal/service"
+)
+
+type OrganizationHandler interface {
+	CreateOrganization(w http.ResponseWriter, r *http.Request)
+	ListOrganizations(w http.ResponseWriter, r *http.Request)
+	ListMembers(w http.ResponseWriter, r *http.Request)
+	SetMember(w http.ResponseWriter, r *http.Request)
+	RemoveMember(w http.ResponseWriter, r *http.Request)
+}
+
+type organizationHandlerImpl struct {
+	organizationService service.OrganizationService
+	validator           *validator.Validate
+}
+
+func NewOrganizationHandler(organizationService service.OrganizationService) OrganizationHandler {
+	return &organizationHandlerImpl{
+		organizationService: organizationService,
+		validator:           validator.New(),
+	}
+}
+
+// CreateOrganization godoc
+// @Summary      Create organization
+// @Description  Create an organization; the caller becomes its owner
+// @Tags         organizations
+// @Accept       json
+// @Produce      json
+// @Param        input  body      model.CreateOrganizationInput  true  "Organization"
+// @Success      201    {object}  response.Response{data=model.Organization}
+// @Failure      400    {object}  response.Response
+// @Security     Bearer
+// @Router       /organizations [post]
+func (h *organizationHandlerImpl) CreateOrganization(w http.ResponseWriter, r *http.Request) {
+	var input model.CreateOrganizationInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+		return
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+		return
+	}
+
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	organization, err := h.organizationService.CreateOrganization(r.Context(), &input, userID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, response.Response{
+		Timestamp: time.Now(),
+		Data:      organization,
+	})
+}
+
+// ListOrganizations godoc
+// @Summary      List my organizations
+// @Description  List the organizations the caller belongs to with their role in each
+// @Tags         organizations
+// @Produce      json
+// @Success      200  {object}  response.Response{data=[]model.MembershipView}
+// @Security     Bearer
+// @Router       /organizations [get]
+func (h *organizationHandlerImpl) ListOrganizations(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	organizations, err := h.organizationService.ListOrganizations(r.Context(), userID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+		Data:      organizations,
+	})
+}
+
+// ListMembers godoc
+// @Summary      List organization members
+// @Description  List the members of an organization; only visible to its members
+// @Tags         organizations
+// @Produce      json
+// @Param        id   path      string  true  "Organization ID"
+// @Success      200  {object}  response.Response{data=[]model.OrganizationMember}
+// @Failure      403  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Security     Bearer
+// @Router       /organizations/{id}/members [get]
+func (h *organizationHandlerImpl) ListMembers(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	members, err := h.organizationService.ListMembers(r.Context(), chi.URLParam(r, "id"), userID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+		Data:      members,
+	})
+}
+
+// SetMember godoc
+// @Summary      Add or update member
+// @Description  Add a user to the organization or change their role. Requires owner or admin; only owners can grant or revoke ownership
+// @Tags         organizations
+// @Accept       json
+// @Produce      json
+// @Param        id      path  string                true  "Organization ID"
+// @Param        userId  path  string                true  "User ID"
+// @Param        input   body  model.SetMemberInput  true  "Role"
+// @Success      200  {object}  response.Response{data=model.OrganizationMember}
+// @Failure      400  {object}  response.Response
+// @Failure      403  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Failure      409  {object}  response.Response
+// @Security     Bearer
+// @Router       /organizations/{id}/members/{userId} [put]
+func (h *organizationHandlerImpl) SetMember(w http.ResponseWriter, r *http.Request) {
+	var input model.SetMemberInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+		return
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+		return
+	}
+
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	member, err := h.organizationService.SetMember(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "userId"), &input, userID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+		Data:      member,
+	})
+}
+
+// RemoveMember godoc
+// @Summary      Remove member
+// @Description  Remove a user from the organization. Members may leave on their own; the last owner cannot be removed
+// @Tags         organizations
+// @Produce      json
+// @Param        id      path  string  true  "Organization ID"
+// @Param        userId  path  string  true  "User ID"
+// @Success      200  {object}  response.Response
+// @Failure      403  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Failure      409  {object}  response.Response
+// @Security     Bearer
+// @Router       /organizations/{id}/members/{userId} [delete]
+func (h *organizationHandlerImpl) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	if err := h.organizationService.RemoveMember(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "userId"), userID); err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+	})
+}
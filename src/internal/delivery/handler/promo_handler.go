@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/hafiztri123/src/internal/model"
+	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"github.com/hafiztri123/src/internal/pkg/response"
+	"github.com/hafiztri123/src/internal/service"
+)
+
+type PromoHandler interface {
+	CreatePromoCode(w http.ResponseWriter, r *http.Request)
+	ListPromoCodes(w http.ResponseWriter, r *http.Request)
+	DeletePromoCode(w http.ResponseWriter, r *http.Request)
+}
+
+type promoHandlerImpl struct {
+	promoService service.PromoService
+	validator    *validator.Validate
+}
+
+func NewPromoHandler(promoService service.PromoService) PromoHandler {
+	return &promoHandlerImpl{
+		promoService: promoService,
+		validator:    validator.New(),
+	}
+}
+
+// CreatePromoCode godoc
+// @Summary      Create promo code
+// @Description  Create a percentage or fixed discount code for one event or for all of the organizer's events
+// @Tags         promo-codes
+// @Accept       json
+// @Produce      json
+// @Param        input  body  model.CreatePromoCodeInput  true  "Promo code"
+// @Success      201  {object}  response.Response{data=model.PromoCode}
+// @Failure      400  {object}  response.Response
+// @Failure      403  {object}  response.Response
+// @Failure      409  {object}  response.Response
+// @Security     Bearer
+// @Router       /promo-codes [post]
+func (h *promoHandlerImpl) CreatePromoCode(w http.ResponseWriter, r *http.Request) {
+	var input model.CreatePromoCodeInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+		return
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+		return
+	}
+
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	promo, err := h.promoService.CreatePromoCode(r.Context(), &input, userID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, response.Response{
+		Timestamp: time.Now(),
+		Data:      promo,
+	})
+}
+
+// ListPromoCodes godoc
+// @Summary      List promo codes
+// @Description  List the promo codes created by the current user
+// @Tags         promo-codes
+// @Produce      json
+// @Success      200  {object}  response.Response{data=[]model.PromoCode}
+// @Failure      401  {object}  response.Response
+// @Security     Bearer
+// @Router       /promo-codes [get]
+func (h *promoHandlerImpl) ListPromoCodes(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	promos, err := h.promoService.ListPromoCodes(r.Context(), userID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+		Data:      promos,
+	})
+}
+
+// DeletePromoCode godoc
+// @Summary      Delete promo code
+// @Description  Delete one of the current user's promo codes
+// @Tags         promo-codes
+// @Produce      json
+// @Param        id   path      string  true  "Promo code ID"
+// @Success      200  {object}  response.Response
+// @Failure      403  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Security     Bearer
+// @Router       /promo-codes/{id} [delete]
+func (h *promoHandlerImpl) DeletePromoCode(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	if err := h.promoService.DeletePromoCode(r.Context(), id, userID); err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+	})
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/hafiztri123/src/internal/model"
+	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"github.com/hafiztri123/src/internal/pkg/response"
+	"github.com/hafiztri123/src/internal/service"
+)
+
+type RecommendationHandler interface {
+	RecommendForEvent(w http.ResponseWriter, r *http.Request)
+	RecommendForUser(w http.ResponseWriter, r *http.Request)
+}
+
+type recommendationHandlerImpl struct {
+	recommendationService service.RecommendationService
+	translationService    service.TranslationService
+}
+
+func NewRecommendationHandler(recommendationService service.RecommendationService, translationService service.TranslationService) RecommendationHandler {
+	return &recommendationHandlerImpl{
+		recommendationService: recommendationService,
+		translationService:    translationService,
+	}
+}
+
+// RecommendForEvent godoc
+// @Summary      Similar events
+// @Description  Get upcoming events similar to an event, by shared tags, category, organizer and attendees
+// @Tags         recommendations
+// @Produce      json
+// @Param        id     path      string  true   "Event ID"
+// @Param        limit  query     int     false  "Number of events"  minimum(1)  maximum(20)  default(10)
+// @Param        lang   query     string  false  "Preferred locale, overrides Accept-Language"
+// @Success      200  {object}  response.Response{data=[]model.RecommendedEvent}
+// @Failure      400  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Router       /events/{id}/recommendations [get]
+func (h *recommendationHandlerImpl) RecommendForEvent(w http.ResponseWriter, r *http.Request) {
+	eventID := chi.URLParam(r, "id")
+
+	limit, ok := parseRecommendationLimit(w, r)
+	if !ok {
+		return
+	}
+
+	recommended, err := h.recommendationService.RecommendForEvent(r.Context(), eventID, limit)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	h.localize(w, r, recommended)
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+		Data:      recommended,
+	})
+}
+
+// RecommendForUser godoc
+// @Summary      Recommended events
+// @Description  Get upcoming events the current user may like, based on the events they registered for. Events they already joined are left out.
+// @Tags         recommendations
+// @Produce      json
+// @Param        limit  query     int     false  "Number of events"  minimum(1)  maximum(20)  default(10)
+// @Param        lang   query     string  false  "Preferred locale, overrides Accept-Language"
+// @Success      200  {object}  response.Response{data=[]model.RecommendedEvent}
+// @Failure      400  {object}  response.Response
+// @Failure      401  {object}  response.Response
+// @Security     Bearer
+// @Router       /users/recommendations [get]
+func (h *recommendationHandlerImpl) RecommendForUser(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	limit, ok := parseRecommendationLimit(w, r)
+	if !ok {
+		return
+	}
+
+	recommended, err := h.recommendationService.RecommendForUser(r.Context(), userID, limit)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	h.localize(w, r, recommended)
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+		Data:      recommended,
+	})
+}
+
+func (h *recommendationHandlerImpl) localize(w http.ResponseWriter, r *http.Request, recommended []*model.RecommendedEvent) {
+	events := make([]*model.Event, 0, len(recommended))
+	for _, entry := range recommended {
+		events = append(events, entry.Event)
+	}
+
+	locale := requestLocale(r)
+	h.translationService.LocalizeEvents(r.Context(), locale, events...)
+	setContentLanguage(w, locale)
+}
+
+func parseRecommendationLimit(w http.ResponseWriter, r *http.Request) (int, bool) {
+	value := r.URL.Query().Get("limit")
+	if value == "" {
+		return 10, true
+	}
+
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 || limit > 20 {
+		HandleErrorResponse(w, errs.NewBadRequestError("limit must be between 1 and 20"))
+		return 0, false
+	}
+
+	return limit, true
+}
@@ -0,0 +1,340 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/hafiztri123/src/internal/model"
+	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"github.com/hafiztri123/src/internal/pkg/response"
+	"github.com/hafiztri123/src/internal/service"
+)
+
+type RefundHandler interface {
+	GetPolicy(w http.ResponseWriter, r *http.Request)
+	SetPolicy(w http.ResponseWriter, r *http.Request)
+	RequestRefund(w http.ResponseWriter, r *http.Request)
+	ApproveRefund(w http.ResponseWriter, r *http.Request)
+	RejectRefund(w http.ResponseWriter, r *http.Request)
+	ListOrderRefunds(w http.ResponseWriter, r *http.Request)
+	ListEventRefunds(w http.ResponseWriter, r *http.Request)
+	ListLedger(w http.ResponseWriter, r *http.Request)
+	CancelEvent(w http.ResponseWriter, r *http.Request)
+}
+
+type refundHandlerImpl struct {
+	refundService service.RefundService
+	validator     *validator.Validate
+}
+
+func NewRefundHandler(refundService service.RefundService) RefundHandler {
+	return &refundHandlerImpl{
+		refundService: refundService,
+		validator:     validator.New(),
+	}
+}
+
+// GetPolicy godoc
+// @Summary      Get refund policy
+// @Description  Get the refund tiers of an event. An empty list means the event is non-refundable unless cancelled.
+// @Tags         refunds
+// @Produce      json
+// @Param        id   path      string  true  "Event ID"
+// @Success      200  {object}  response.Response{data=model.RefundPolicy}
+// @Failure      404  {object}  response.Response
+// @Router       /events/{id}/refund-policy [get]
+func (h *refundHandlerImpl) GetPolicy(w http.ResponseWriter, r *http.Request) {
+	eventID := chi.URLParam(r, "id")
+
+	policy, err := h.refundService.GetPolicy(r.Context(), eventID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+		Data:      policy,
+	})
+}
+
+// SetPolicy godoc
+// @Summary      Set refund policy
+// @Description  Replace the refund tiers of an event
+// @Tags         refunds
+// @Accept       json
+// @Produce      json
+// @Param        id     path  string                   true  "Event ID"
+// @Param        input  body  model.RefundPolicyInput  true  "Refund tiers"
+// @Success      200  {object}  response.Response{data=model.RefundPolicy}
+// @Failure      400  {object}  response.Response
+// @Failure      403  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Security     Bearer
+// @Router       /events/{id}/refund-policy [put]
+func (h *refundHandlerImpl) SetPolicy(w http.ResponseWriter, r *http.Request) {
+	eventID := chi.URLParam(r, "id")
+
+	var input model.RefundPolicyInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+		return
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+		return
+	}
+
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	policy, err := h.refundService.SetPolicy(r.Context(), eventID, &input, userID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+		Data:      policy,
+	})
+}
+
+// RequestRefund godoc
+// @Summary      Request refund
+// @Description  Request a refund for a paid order. The amount follows the event's refund policy and needs organizer approval.
+// @Tags         refunds
+// @Accept       json
+// @Produce      json
+// @Param        id     path  string                    true   "Order ID"
+// @Param        input  body  model.RefundRequestInput  false  "Reason"
+// @Success      201  {object}  response.Response{data=model.Refund}
+// @Failure      400  {object}  response.Response
+// @Failure      403  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Failure      409  {object}  response.Response
+// @Security     Bearer
+// @Router       /orders/{id}/refunds [post]
+func (h *refundHandlerImpl) RequestRefund(w http.ResponseWriter, r *http.Request) {
+	orderID := chi.URLParam(r, "id")
+
+	var input model.RefundRequestInput
+	if !h.decodeOptional(w, r, &input) {
+		return
+	}
+
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	refund, err := h.refundService.RequestRefund(r.Context(), orderID, &input, userID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, response.Response{
+		Timestamp: time.Now(),
+		Data:      refund,
+	})
+}
+
+// ApproveRefund godoc
+// @Summary      Approve refund
+// @Description  Approve a requested refund and pay it out through the payment provider
+// @Tags         refunds
+// @Produce      json
+// @Param        id   path      string  true  "Refund ID"
+// @Success      200  {object}  response.Response{data=model.Refund}
+// @Failure      403  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Failure      409  {object}  response.Response
+// @Failure      500  {object}  response.Response
+// @Security     Bearer
+// @Router       /refunds/{id}/approve [post]
+func (h *refundHandlerImpl) ApproveRefund(w http.ResponseWriter, r *http.Request) {
+	refundID := chi.URLParam(r, "id")
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	refund, err := h.refundService.ApproveRefund(r.Context(), refundID, userID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+		Data:      refund,
+	})
+}
+
+// RejectRefund godoc
+// @Summary      Reject refund
+// @Description  Reject a requested refund
+// @Tags         refunds
+// @Accept       json
+// @Produce      json
+// @Param        id     path  string                    true   "Refund ID"
+// @Param        input  body  model.RefundRequestInput  false  "Reason"
+// @Success      200  {object}  response.Response{data=model.Refund}
+// @Failure      403  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Failure      409  {object}  response.Response
+// @Security     Bearer
+// @Router       /refunds/{id}/reject [post]
+func (h *refundHandlerImpl) RejectRefund(w http.ResponseWriter, r *http.Request) {
+	refundID := chi.URLParam(r, "id")
+
+	var input model.RefundRequestInput
+	if !h.decodeOptional(w, r, &input) {
+		return
+	}
+
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	refund, err := h.refundService.RejectRefund(r.Context(), refundID, &input, userID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+		Data:      refund,
+	})
+}
+
+// ListOrderRefunds godoc
+// @Summary      List order refunds
+// @Description  List the refunds of one of the current user's orders
+// @Tags         refunds
+// @Produce      json
+// @Param        id   path      string  true  "Order ID"
+// @Success      200  {object}  response.Response{data=[]model.Refund}
+// @Failure      403  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Security     Bearer
+// @Router       /orders/{id}/refunds [get]
+func (h *refundHandlerImpl) ListOrderRefunds(w http.ResponseWriter, r *http.Request) {
+	orderID := chi.URLParam(r, "id")
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	refunds, err := h.refundService.ListOrderRefunds(r.Context(), orderID, userID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+		Data:      refunds,
+	})
+}
+
+// ListEventRefunds godoc
+// @Summary      List event refunds
+// @Description  List all refunds of an event, for its organizer
+// @Tags         refunds
+// @Produce      json
+// @Param        id   path      string  true  "Event ID"
+// @Success      200  {object}  response.Response{data=[]model.Refund}
+// @Failure      403  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Security     Bearer
+// @Router       /events/{id}/refunds [get]
+func (h *refundHandlerImpl) ListEventRefunds(w http.ResponseWriter, r *http.Request) {
+	eventID := chi.URLParam(r, "id")
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	refunds, err := h.refundService.ListEventRefunds(r.Context(), eventID, userID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+		Data:      refunds,
+	})
+}
+
+// ListLedger godoc
+// @Summary      Refund ledger
+// @Description  List every refund ledger entry of an event in chronological order
+// @Tags         refunds
+// @Produce      json
+// @Param        id   path      string  true  "Event ID"
+// @Success      200  {object}  response.Response{data=[]model.RefundLedgerEntry}
+// @Failure      403  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Security     Bearer
+// @Router       /events/{id}/refund-ledger [get]
+func (h *refundHandlerImpl) ListLedger(w http.ResponseWriter, r *http.Request) {
+	eventID := chi.URLParam(r, "id")
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	entries, err := h.refundService.ListLedger(r.Context(), eventID, userID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+		Data:      entries,
+	})
+}
+
+// CancelEvent godoc
+// @Summary      Cancel event
+// @Description  Cancel an event, release pending orders and fully refund every paid order. Repeating the call retries failed refunds.
+// @Tags         refunds
+// @Accept       json
+// @Produce      json
+// @Param        id     path  string                  true   "Event ID"
+// @Param        input  body  model.CancelEventInput  false  "Reason"
+// @Success      200  {object}  response.Response{data=model.CancelEventOutput}
+// @Failure      403  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Security     Bearer
+// @Router       /events/{id}/cancel [post]
+func (h *refundHandlerImpl) CancelEvent(w http.ResponseWriter, r *http.Request) {
+	eventID := chi.URLParam(r, "id")
+
+	var input model.CancelEventInput
+	if !h.decodeOptional(w, r, &input) {
+		return
+	}
+
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	output, err := h.refundService.CancelEvent(r.Context(), eventID, &input, userID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+		Data:      output,
+	})
+}
+
+// decodeOptional decodes and validates a request body that may be left empty.
+func (h *refundHandlerImpl) decodeOptional(w http.ResponseWriter, r *http.Request, input interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(input); err != nil && !errors.Is(err, io.EOF) {
+		HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+		return false
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/hafiztri123/src/internal/model"
+	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"github.com/hafiztri123/src/internal/pkg/response"
+	"github.com/hafiztri123/src/internal/service"
+)
+
+type RegistrationFormHandler interface {
+	GetForm(w http.ResponseWriter, r *http.Request)
+	PutForm(w http.ResponseWriter, r *http.Request)
+	DeleteForm(w http.ResponseWriter, r *http.Request)
+}
+
+type registrationFormHandlerImpl struct {
+	registrationFormService service.RegistrationFormService
+	validator               *validator.Validate
+}
+
+func NewRegistrationFormHandler(registrationFormService service.RegistrationFormService) RegistrationFormHandler {
+	return &registrationFormHandlerImpl{
+		registrationFormService: registrationFormService,
+		validator:               validator.New(),
+	}
+}
+
+// GetForm godoc
+// @Summary      Get registration form
+// @Description  Get the questions attendees answer when registering for the event
+// @Tags         registrations
+// @Produce      json
+// @Param        id   path      string  true  "Event ID"
+// @Success      200  {object}  response.Response{data=model.RegistrationForm}
+// @Failure      404  {object}  response.Response
+// @Router       /events/{id}/registration-form [get]
+func (h *registrationFormHandlerImpl) GetForm(w http.ResponseWriter, r *http.Request) {
+	form, err := h.registrationFormService.GetForm(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+		Data:      form,
+	})
+}
+
+// PutForm godoc
+// @Summary      Set registration form
+// @Description  Replace the event's registration form. Only the event creator may change it
+// @Tags         registrations
+// @Accept       json
+// @Produce      json
+// @Param        id     path      string                          true  "Event ID"
+// @Param        input  body      model.PutRegistrationFormInput  true  "Form fields"
+// @Success      200  {object}  response.Response{data=model.RegistrationForm}
+// @Failure      400  {object}  response.Response
+// @Failure      403  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Security     Bearer
+// @Router       /events/{id}/registration-form [put]
+func (h *registrationFormHandlerImpl) PutForm(w http.ResponseWriter, r *http.Request) {
+	var input model.PutRegistrationFormInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+		return
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+		return
+	}
+
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	form, err := h.registrationFormService.PutForm(r.Context(), chi.URLParam(r, "id"), &input, userID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+		Data:      form,
+	})
+}
+
+// DeleteForm godoc
+// @Summary      Delete registration form
+// @Description  Remove the event's registration form. Existing registrations keep their answers
+// @Tags         registrations
+// @Produce      json
+// @Param        id   path      string  true  "Event ID"
+// @Success      200  {object}  response.Response
+// @Failure      403  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Security     Bearer
+// @Router       /events/{id}/registration-form [delete]
+func (h *registrationFormHandlerImpl) DeleteForm(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	if err := h.registrationFormService.DeleteForm(r.Context(), chi.URLParam(r, "id"), userID); err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+		Data:      nil,
+	})
+}
@@ -0,0 +1,307 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/hafiztri123/src/internal/model"
+	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"github.com/hafiztri123/src/internal/pkg/response"
+	"github.com/hafiztri123/src/internal/service"
+)
+
+type RegistrationHandler interface {
+	Register(w http.ResponseWriter, r *http.Request)
+	CheckIn(w http.ResponseWriter, r *http.Request)
+	GetTicket(w http.ResponseWriter, r *http.Request)
+	UpdateAttendees(w http.ResponseWriter, r *http.Request)
+	GetRegistration(w http.ResponseWriter, r *http.Request)
+	ExportAttendees(w http.ResponseWriter, r *http.Request)
+}
+
+type registrationHandlerImpl struct {
+	registrationService service.RegistrationService
+	validator           *validator.Validate
+}
+
+func NewRegistrationHandler(registrationService service.RegistrationService) RegistrationHandler {
+	return &registrationHandlerImpl{
+		registrationService: registrationService,
+		validator:           validator.New(),
+	}
+}
+
+// Register godoc
+// @Summary      Register for event
+// @Description  Register the current user for an event with the answers to its registration form. Overlapping registrations are returned as warnings.
+// @Tags         registrations
+// @Accept       json
+// @Produce      json
+// @Param        id     path      string                        true   "Event ID"
+// @Param        input  body      model.EventRegistrationInput  false  "Answers to the registration form"
+// @Success      201  {object}  response.Response{data=model.RegistrationOutput}
+// @Failure      400  {object}  response.Response
+// @Failure      401  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Failure      409  {object}  response.Response
+// @Security     Bearer
+// @Router       /events/{id}/register [post]
+func (h *registrationHandlerImpl) Register(w http.ResponseWriter, r *http.Request) {
+	eventID := chi.URLParam(r, "id")
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	var input model.EventRegistrationInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil && !errors.Is(err, io.EOF) {
+		HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+		return
+	}
+
+	output, err := h.registrationService.Register(r.Context(), eventID, &input, userID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, response.Response{
+		Timestamp: time.Now(),
+		Data:      output,
+	})
+}
+
+// CheckIn godoc
+// @Summary      Check in attendee
+// @Description  Mark a registered attendee as present at the event, named by user ID, by group booking attendee ID or by the code scanned from their ticket. Only the event creator may check attendees in.
+// @Tags         registrations
+// @Accept       json
+// @Produce      json
+// @Param        id     path      string              true  "Event ID"
+// @Param        input  body      model.CheckInInput  true  "Attendee"
+// @Success      200  {object}  response.Response{data=model.Registration}
+// @Failure      400  {object}  response.Response
+// @Failure      401  {object}  response.Response
+// @Failure      403  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Failure      409  {object}  response.Response
+// @Security     Bearer
+// @Router       /events/{id}/check-ins [post]
+func (h *registrationHandlerImpl) CheckIn(w http.ResponseWriter, r *http.Request) {
+	eventID := chi.URLParam(r, "id")
+	organizerID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	var input model.CheckInInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+		return
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+		return
+	}
+
+	registration, err := h.registrationService.CheckIn(r.Context(), eventID, &input, organizerID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+		Data:      registration,
+	})
+}
+
+// GetTicket godoc
+// @Summary      Get my ticket
+// @Description  Get the current user's ticket for an event with the signed code to show as a QR code. Group bookings return a code per attendee. A code stops working when its ticket is transferred or its attendee changes.
+// @Tags         registrations
+// @Produce      json
+// @Param        id   path      string  true  "Event ID"
+// @Success      200  {object}  response.Response{data=model.Ticket}
+// @Failure      401  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Security     Bearer
+// @Router       /events/{id}/ticket [get]
+func (h *registrationHandlerImpl) GetTicket(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	ticket, err := h.registrationService.GetTicket(r.Context(), chi.URLParam(r, "id"), userID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+		Data:      ticket,
+	})
+}
+
+// UpdateAttendees godoc
+// @Summary      Name attendees
+// @Description  Set the names and emails of the attendees of the current user's group booking. Attendees whose details change get a new ticket code.
+// @Tags         registrations
+// @Accept       json
+// @Produce      json
+// @Param        id     path      string                      true  "Event ID"
+// @Param        input  body      model.UpdateAttendeesInput  true  "Attendees"
+// @Success      200  {object}  response.Response{data=model.Ticket}
+// @Failure      400  {object}  response.Response
+// @Failure      401  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Failure      409  {object}  response.Response
+// @Security     Bearer
+// @Router       /events/{id}/attendees [put]
+func (h *registrationHandlerImpl) UpdateAttendees(w http.ResponseWriter, r *http.Request) {
+	var input model.UpdateAttendeesInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+		return
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+		return
+	}
+
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	ticket, err := h.registrationService.UpdateAttendees(r.Context(), chi.URLParam(r, "id"), &input, userID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+		Data:      ticket,
+	})
+}
+
+// GetRegistration godoc
+// @Summary      Get registration
+// @Description  Get an attendee's registration with their answers to the registration form. Visible to the attendee and the event creator.
+// @Tags         registrations
+// @Produce      json
+// @Param        id      path      string  true  "Event ID"
+// @Param        userId  path      string  true  "Attendee user ID"
+// @Success      200  {object}  response.Response{data=model.Registration}
+// @Failure      401  {object}  response.Response
+// @Failure      403  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Security     Bearer
+// @Router       /events/{id}/registrations/{userId} [get]
+func (h *registrationHandlerImpl) GetRegistration(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	registration, err := h.registrationService.GetRegistration(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "userId"), userID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+		Data:      registration,
+	})
+}
+
+// ExportAttendees godoc
+// @Summary      Export attendees
+// @Description  Download the event's attendees as CSV, with one column per registration form field. Group bookings have a row per attendee. Only the event creator may export.
+// @Tags         registrations
+// @Produce      text/csv
+// @Param        id   path  string  true  "Event ID"
+// @Success      200  {file}    file
+// @Failure      401  {object}  response.Response
+// @Failure      403  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Security     Bearer
+// @Router       /events/{id}/attendees/export [get]
+func (h *registrationHandlerImpl) ExportAttendees(w http.ResponseWriter, r *http.Request) {
+	organizerID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	export, err := h.registrationService.ExportAttendees(r.Context(), chi.URLParam(r, "id"), organizerID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", export.Event.Slug+"-attendees.csv"))
+	w.WriteHeader(http.StatusOK)
+
+	writer := csv.NewWriter(w)
+
+	header := []string{"user_id", "full_name", "email", "attendee_id", "attendee_name", "attendee_email", "registered_at", "checked_in_at"}
+	for _, field := range export.Form.Fields {
+		header = append(header, field.Label)
+	}
+	writer.Write(header)
+
+	for _, registration := range export.Registrations {
+		row := []string{registration.UserID, "", "", "", "", "", registration.CreatedAt.Format(time.RFC3339), ""}
+		if registration.User != nil {
+			row[1] = registration.User.FullName
+			row[2] = registration.User.Email
+		}
+		for _, field := range export.Form.Fields {
+			row = append(row, csvAnswer(registration.Answers[field.Key]))
+		}
+
+		if len(registration.Attendees) == 0 {
+			if registration.CheckedInAt != nil {
+				row[7] = registration.CheckedInAt.Format(time.RFC3339)
+			}
+			writer.Write(escapeCSVRow(row))
+			continue
+		}
+
+		for _, attendee := range registration.Attendees {
+			attendeeRow := append([]string(nil), row...)
+			attendeeRow[3] = attendee.ID
+			attendeeRow[4] = attendee.Name
+			attendeeRow[5] = attendee.Email
+			if attendee.CheckedInAt != nil {
+				attendeeRow[7] = attendee.CheckedInAt.Format(time.RFC3339)
+			}
+			writer.Write(escapeCSVRow(attendeeRow))
+		}
+	}
+
+	writer.Flush()
+}
+
+// csvAnswer formats an answer for a CSV cell. Unanswered fields are left empty.
+func csvAnswer(answer interface{}) string {
+	switch value := answer.(type) {
+	case nil:
+		return ""
+	case bool:
+		if value {
+			return "yes"
+		}
+		return "no"
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// escapeCSVRow keeps spreadsheets from evaluating attendee input as formulas.
+func escapeCSVRow(row []string) []string {
+	for i, cell := range row {
+		if cell != "" && strings.ContainsAny(cell[:1], "=+-@\t\r") {
+			row[i] = "'" + cell
+		}
+	}
+	return row
+}
@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/hafiztri123/src/internal/model"
+	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"github.com/hafiztri123/src/internal/pkg/response"
+	"github.com/hafiztri123/src/internal/service"
+)
+
+type SeatingHandler interface {
+	GetSeatMap(w http.ResponseWriter, r *http.Request)
+	PutSeatMap(w http.ResponseWriter, r *http.Request)
+	HoldSeats(w http.ResponseWriter, r *http.Request)
+	ReleaseHold(w http.ResponseWriter, r *http.Request)
+	ConfirmHold(w http.ResponseWriter, r *http.Request)
+}
+
+type seatingHandlerImpl struct {
+	seatingService service.SeatingService
+	validator      *validator.Validate
+}
+
+func NewSeatingHandler(seatingService service.SeatingService) SeatingHandler {
+	return &seatingHandlerImpl{
+		seatingService: seatingService,
+		validator:      validator.New(),
+	}
+}
+
+// GetSeatMap godoc
+// @Summary      Get seat map
+// @Description  Get the event's sections, rows and seats with each seat's price tier and whether it is available, held or taken
+// @Tags         seating
+// @Produce      json
+// @Param        id   path      string  true  "Event ID"
+// @Success      200  {object}  response.Response{data=model.SeatMap}
+// @Failure      404  {object}  response.Response
+// @Router       /events/{id}/seat-map [get]
+func (h *seatingHandlerImpl) GetSeatMap(w http.ResponseWriter, r *http.Request) {
+	seatMap, err := h.seatingService.GetSeatMap(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+		Data:      seatMap,
+	})
+}
+
+// PutSeatMap godoc
+// @Summary      Set seat map
+// @Description  Replace the event's seat map. Only the event creator may change it, and only before any seat is booked
+// @Tags         seating
+// @Accept       json
+// @Produce      json
+// @Param        id     path      string                 true  "Event ID"
+// @Param        input  body      model.PutSeatMapInput  true  "Seat map"
+// @Success      200  {object}  response.Response{data=model.SeatMap}
+// @Failure      400  {object}  response.Response
+// @Failure      403  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Failure      409  {object}  response.Response
+// @Security     Bearer
+// @Router       /events/{id}/seat-map [put]
+func (h *seatingHandlerImpl) PutSeatMap(w http.ResponseWriter, r *http.Request) {
+	var input model.PutSeatMapInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+		return
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+		return
+	}
+
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	seatMap, err := h.seatingService.PutSeatMap(r.Context(), chi.URLParam(r, "id"), &input, userID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+		Data:      seatMap,
+	})
+}
+
+// HoldSeats godoc
+// @Summary      Hold seats
+// @Description  Lock seats for the current user for a few minutes while they check out
+// @Tags         seating
+// @Accept       json
+// @Produce      json
+// @Param        id     path      string                true  "Event ID"
+// @Param        input  body      model.HoldSeatsInput  true  "Seats"
+// @Success      201  {object}  response.Response{data=model.SeatHold}
+// @Failure      400  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Failure      409  {object}  response.Response
+// @Security     Bearer
+// @Router       /events/{id}/seat-holds [post]
+func (h *seatingHandlerImpl) HoldSeats(w http.ResponseWriter, r *http.Request) {
+	var input model.HoldSeatsInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+		return
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+		return
+	}
+
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	hold, err := h.seatingService.HoldSeats(r.Context(), chi.URLParam(r, "id"), &input, userID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, response.Response{
+		Timestamp: time.Now(),
+		Data:      hold,
+	})
+}
+
+// ReleaseHold godoc
+// @Summary      Release seat hold
+// @Description  Give the held seats back before the hold expires
+// @Tags         seating
+// @Produce      json
+// @Param        id   path      string  true  "Seat hold ID"
+// @Success      200  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Security     Bearer
+// @Router       /seat-holds/{id} [delete]
+func (h *seatingHandlerImpl) ReleaseHold(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	if err := h.seatingService.ReleaseHold(r.Context(), chi.URLParam(r, "id"), userID); err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+	})
+}
+
+// ConfirmHold godoc
+// @Summary      Confirm seat hold
+// @Description  Book the held seats. Free seats are registered immediately; paid seats open an order that is paid like any checkout
+// @Tags         seating
+// @Accept       json
+// @Produce      json
+// @Param        id     path      string                      true   "Seat hold ID"
+// @Param        input  body      model.ConfirmSeatHoldInput  false  "Promo code and registration form answers"
+// @Success      201  {object}  response.Response{data=model.CheckoutOutput}
+// @Failure      400  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Failure      409  {object}  response.Response
+// @Security     Bearer
+// @Router       /seat-holds/{id}/confirm [post]
+func (h *seatingHandlerImpl) ConfirmHold(w http.ResponseWriter, r *http.Request) {
+	var input model.ConfirmSeatHoldInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil && !errors.Is(err, io.EOF) {
+		HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+		return
+	}
+
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	output, err := h.seatingService.ConfirmHold(r.Context(), chi.URLParam(r, "id"), &input, userID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, response.Response{
+		Timestamp: time.Now(),
+		Data:      output,
+	})
+}
@@ -0,0 +1,293 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/hafiztri123/src/internal/model"
+	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"github.com/hafiztri123/src/internal/pkg/response"
+	"github.com/hafiztri123/src/internal/service"
+)
+
+type TransferHandler interface {
+	GetPolicy(w http.ResponseWriter, r *http.Request)
+	SetPolicy(w http.ResponseWriter, r *http.Request)
+	InitiateTransfer(w http.ResponseWriter, r *http.Request)
+	ListIncoming(w http.ResponseWriter, r *http.Request)
+	ListOutgoing(w http.ResponseWriter, r *http.Request)
+	AcceptTransfer(w http.ResponseWriter, r *http.Request)
+	DeclineTransfer(w http.ResponseWriter, r *http.Request)
+	CancelTransfer(w http.ResponseWriter, r *http.Request)
+	ListEventTransfers(w http.ResponseWriter, r *http.Request)
+}
+
+type transferHandlerImpl struct {
+	transferService service.TransferService
+	validator       *validator.Validate
+}
+
+func NewTransferHandler(transferService service.TransferService) TransferHandler {
+	return &transferHandlerImpl{
+		transferService: transferService,
+		validator:       validator.New(),
+	}
+}
+
+// GetPolicy godoc
+// @Summary      Get transfer policy
+// @Description  Get whether the event's tickets can be transferred and how many times each
+// @Tags         transfers
+// @Produce      json
+// @Param        id   path      string  true  "Event ID"
+// @Success      200  {object}  response.Response{data=model.TransferPolicy}
+// @Failure      404  {object}  response.Response
+// @Router       /events/{id}/transfer-policy [get]
+func (h *transferHandlerImpl) GetPolicy(w http.ResponseWriter, r *http.Request) {
+	policy, err := h.transferService.GetPolicy(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+		Data:      policy,
+	})
+}
+
+// SetPolicy godoc
+// @Summary      Set transfer policy
+// @Description  Enable or disable ticket transfers for the event and cap transfers per ticket. Only the event creator may change it
+// @Tags         transfers
+// @Accept       json
+// @Produce      json
+// @Param        id     path      string                     true  "Event ID"
+// @Param        input  body      model.TransferPolicyInput  true  "Policy"
+// @Success      200  {object}  response.Response{data=model.TransferPolicy}
+// @Failure      400  {object}  response.Response
+// @Failure      403  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Security     Bearer
+// @Router       /events/{id}/transfer-policy [put]
+func (h *transferHandlerImpl) SetPolicy(w http.ResponseWriter, r *http.Request) {
+	var input model.TransferPolicyInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+		return
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+		return
+	}
+
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	policy, err := h.transferService.SetPolicy(r.Context(), chi.URLParam(r, "id"), &input, userID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+		Data:      policy,
+	})
+}
+
+// InitiateTransfer godoc
+// @Summary      Transfer ticket
+// @Description  Offer the current user's ticket for the event to the owner of an email address. The ticket moves when the recipient accepts
+// @Tags         transfers
+// @Accept       json
+// @Produce      json
+// @Param        id     path      string                       true  "Event ID"
+// @Param        input  body      model.InitiateTransferInput  true  "Recipient"
+// @Success      201  {object}  response.Response{data=model.TicketTransfer}
+// @Failure      400  {object}  response.Response
+// @Failure      403  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Failure      409  {object}  response.Response
+// @Security     Bearer
+// @Router       /events/{id}/transfers [post]
+func (h *transferHandlerImpl) InitiateTransfer(w http.ResponseWriter, r *http.Request) {
+	var input model.InitiateTransferInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+		return
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+		return
+	}
+
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	transfer, err := h.transferService.InitiateTransfer(r.Context(), chi.URLParam(r, "id"), &input, userID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, response.Response{
+		Timestamp: time.Now(),
+		Data:      transfer,
+	})
+}
+
+// ListIncoming godoc
+// @Summary      List incoming transfers
+// @Description  List the pending transfers sent to the current user's email
+// @Tags         transfers
+// @Produce      json
+// @Success      200  {object}  response.Response{data=[]model.TicketTransfer}
+// @Security     Bearer
+// @Router       /transfers/incoming [get]
+func (h *transferHandlerImpl) ListIncoming(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	transfers, err := h.transferService.ListIncoming(r.Context(), userID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+		Data:      transfers,
+	})
+}
+
+// ListOutgoing godoc
+// @Summary      List outgoing transfers
+// @Description  List the transfers the current user has sent
+// @Tags         transfers
+// @Produce      json
+// @Success      200  {object}  response.Response{data=[]model.TicketTransfer}
+// @Security     Bearer
+// @Router       /transfers/outgoing [get]
+func (h *transferHandlerImpl) ListOutgoing(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	transfers, err := h.transferService.ListOutgoing(r.Context(), userID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+		Data:      transfers,
+	})
+}
+
+// AcceptTransfer godoc
+// @Summary      Accept transfer
+// @Description  Take over the ticket. It is reissued with a new code and the sender's code stops working
+// @Tags         transfers
+// @Produce      json
+// @Param        id   path      string  true  "Transfer ID"
+// @Success      200  {object}  response.Response{data=model.TicketTransfer}
+// @Failure      403  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Failure      409  {object}  response.Response
+// @Security     Bearer
+// @Router       /transfers/{id}/accept [post]
+func (h *transferHandlerImpl) AcceptTransfer(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	transfer, err := h.transferService.AcceptTransfer(r.Context(), chi.URLParam(r, "id"), userID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+		Data:      transfer,
+	})
+}
+
+// DeclineTransfer godoc
+// @Summary      Decline transfer
+// @Description  Turn down a transfer; the ticket stays with the sender
+// @Tags         transfers
+// @Produce      json
+// @Param        id   path      string  true  "Transfer ID"
+// @Success      200  {object}  response.Response{data=model.TicketTransfer}
+// @Failure      404  {object}  response.Response
+// @Failure      409  {object}  response.Response
+// @Security     Bearer
+// @Router       /transfers/{id}/decline [post]
+func (h *transferHandlerImpl) DeclineTransfer(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	transfer, err := h.transferService.DeclineTransfer(r.Context(), chi.URLParam(r, "id"), userID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+		Data:      transfer,
+	})
+}
+
+// CancelTransfer godoc
+// @Summary      Cancel transfer
+// @Description  Withdraw a pending transfer the current user sent
+// @Tags         transfers
+// @Produce      json
+// @Param        id   path      string  true  "Transfer ID"
+// @Success      200  {object}  response.Response{data=model.TicketTransfer}
+// @Failure      404  {object}  response.Response
+// @Failure      409  {object}  response.Response
+// @Security     Bearer
+// @Router       /transfers/{id}/cancel [post]
+func (h *transferHandlerImpl) CancelTransfer(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	transfer, err := h.transferService.CancelTransfer(r.Context(), chi.URLParam(r, "id"), userID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+		Data:      transfer,
+	})
+}
+
+// ListEventTransfers godoc
+// @Summary      List event transfers
+// @Description  List every transfer of the event's tickets with its audit trail. Only the event creator may see them
+// @Tags         transfers
+// @Produce      json
+// @Param        id   path      string  true  "Event ID"
+// @Success      200  {object}  response.Response{data=[]model.TicketTransfer}
+// @Failure      403  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Security     Bearer
+// @Router       /events/{id}/transfers [get]
+func (h *transferHandlerImpl) ListEventTransfers(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	transfers, err := h.transferService.ListEventTransfers(r.Context(), chi.URLParam(r, "id"), userID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+		Data:      transfers,
+	})
+}
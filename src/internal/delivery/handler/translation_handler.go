@@ -0,0 +1,214 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/hafiztri123/src/internal/model"
+	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"github.com/hafiztri123/src/internal/pkg/response"
+	"github.com/hafiztri123/src/internal/service"
+)
+
+type TranslationHandler interface {
+	PutEventTranslation(w http.ResponseWriter, r *http.Request)
+	ListEventTranslations(w http.ResponseWriter, r *http.Request)
+	DeleteEventTranslation(w http.ResponseWriter, r *http.Request)
+	PutCategoryTranslation(w http.ResponseWriter, r *http.Request)
+	ListCategoryTranslations(w http.ResponseWriter, r *http.Request)
+	DeleteCategoryTranslation(w http.ResponseWriter, r *http.Request)
+}
+
+type translationHandlerImpl struct {
+	translationService service.TranslationService
+	validator          *validator.Validate
+}
+
+func NewTranslationHandler(translationService service.TranslationService) TranslationHandler {
+	return &translationHandlerImpl{
+		translationService: translationService,
+		validator:          validator.New(),
+	}
+}
+
+// PutEventTranslation godoc
+// @Summary      Translate event
+// @Description  Create or replace the title and description of an event in a supported locale other than the default one
+// @Tags         translations
+// @Accept       json
+// @Produce      json
+// @Param        id      path  string                       true  "Event ID"
+// @Param        locale  path  string                       true  "Locale, e.g. id"
+// @Param        input   body  model.EventTranslationInput  true  "Translated content"
+// @Success      200  {object}  response.Response{data=model.EventTranslation}
+// @Failure      400  {object}  response.Response
+// @Failure      403  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Security     Bearer
+// @Router       /events/{id}/translations/{locale} [put]
+func (h *translationHandlerImpl) PutEventTranslation(w http.ResponseWriter, r *http.Request) {
+	var input model.EventTranslationInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+		return
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+		return
+	}
+
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	translation, err := h.translationService.PutEventTranslation(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "locale"), &input, userID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+		Data:      translation,
+	})
+}
+
+// ListEventTranslations godoc
+// @Summary      List event translations
+// @Description  List the translations of an event
+// @Tags         translations
+// @Produce      json
+// @Param        id   path      string  true  "Event ID"
+// @Success      200  {object}  response.Response{data=[]model.EventTranslation}
+// @Failure      404  {object}  response.Response
+// @Router       /events/{id}/translations [get]
+func (h *translationHandlerImpl) ListEventTranslations(w http.ResponseWriter, r *http.Request) {
+	translations, err := h.translationService.ListEventTranslations(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+		Data:      translations,
+	})
+}
+
+// DeleteEventTranslation godoc
+// @Summary      Delete event translation
+// @Description  Remove an event's translation; the locale falls back to the default content
+// @Tags         translations
+// @Produce      json
+// @Param        id      path  string  true  "Event ID"
+// @Param        locale  path  string  true  "Locale, e.g. id"
+// @Success      200  {object}  response.Response
+// @Failure      400  {object}  response.Response
+// @Failure      403  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Security     Bearer
+// @Router       /events/{id}/translations/{locale} [delete]
+func (h *translationHandlerImpl) DeleteEventTranslation(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	if err := h.translationService.DeleteEventTranslation(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "locale"), userID); err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+	})
+}
+
+// PutCategoryTranslation godoc
+// @Summary      Translate category
+// @Description  Create or replace the name and description of a category in a supported locale other than the default one
+// @Tags         translations
+// @Accept       json
+// @Produce      json
+// @Param        id      path  string                          true  "Category ID"
+// @Param        locale  path  string                          true  "Locale, e.g. id"
+// @Param        input   body  model.CategoryTranslationInput  true  "Translated content"
+// @Success      200  {object}  response.Response{data=model.CategoryTranslation}
+// @Failure      400  {object}  response.Response
+// @Failure      403  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Security     Bearer
+// @Router       /categories/{id}/translations/{locale} [put]
+func (h *translationHandlerImpl) PutCategoryTranslation(w http.ResponseWriter, r *http.Request) {
+	var input model.CategoryTranslationInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+		return
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+		return
+	}
+
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	translation, err := h.translationService.PutCategoryTranslation(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "locale"), &input, userID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+		Data:      translation,
+	})
+}
+
+// ListCategoryTranslations godoc
+// @Summary      List category translations
+// @Description  List the translations of a category
+// @Tags         translations
+// @Produce      json
+// @Param        id   path      string  true  "Category ID"
+// @Success      200  {object}  response.Response{data=[]model.CategoryTranslation}
+// @Failure      404  {object}  response.Response
+// @Router       /categories/{id}/translations [get]
+func (h *translationHandlerImpl) ListCategoryTranslations(w http.ResponseWriter, r *http.Request) {
+	translations, err := h.translationService.ListCategoryTranslations(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+		Data:      translations,
+	})
+}
+
+// DeleteCategoryTranslation godoc
+// @Summary      Delete category translation
+// @Description  Remove a category's translation; the locale falls back to the default content
+// @Tags         translations
+// @Produce      json
+// @Param        id      path  string  true  "Category ID"
+// @Param        locale  path  string  true  "Locale, e.g. id"
+// @Success      200  {object}  response.Response
+// @Failure      400  {object}  response.Response
+// @Failure      403  {object}  response.Response
+// @Failure      404  {object}  response.Response
+// @Security     Bearer
+// @Router       /categories/{id}/translations/{locale} [delete]
+func (h *translationHandlerImpl) DeleteCategoryTranslation(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	if err := h.translationService.DeleteCategoryTranslation(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "locale"), userID); err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+	})
+}
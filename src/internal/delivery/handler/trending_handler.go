@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/hafiztri123/src/internal/model"
+	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"github.com/hafiztri123/src/internal/pkg/response"
+	"github.com/hafiztri123/src/internal/service"
+)
+
+type TrendingHandler interface {
+	GetTrending(w http.ResponseWriter, r *http.Request)
+}
+
+type trendingHandlerImpl struct {
+	trendingService    service.TrendingService
+	translationService service.TranslationService
+	validator          *validator.Validate
+}
+
+func NewTrendingHandler(trendingService service.TrendingService, translationService service.TranslationService) TrendingHandler {
+	return &trendingHandlerImpl{
+		trendingService:    trendingService,
+		translationService: translationService,
+		validator:          validator.New(),
+	}
+}
+
+// GetTrending godoc
+// @Summary      Trending events
+// @Description  Get the most popular upcoming events, ranked by recent views, bookmarks and registrations. Older activity counts for less.
+// @Tags         events
+// @Produce      json
+// @Param        category_id  query     string  false  "Only rank events in this category"
+// @Param        window       query     string  false  "Ranking window (day, week, month)"  default(week)
+// @Param        limit        query     int     false  "Number of events"  minimum(1)  maximum(50)  default(10)
+// @Param        lang         query     string  false  "Preferred locale, overrides Accept-Language"
+// @Success      200  {object}  response.Response{data=[]model.TrendingEvent}
+// @Failure      400  {object}  response.Response
+// @Failure      500  {object}  response.Response
+// @Router       /events/trending [get]
+func (h *trendingHandlerImpl) GetTrending(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+
+	input := &model.TrendingInput{
+		CategoryID: params.Get("category_id"),
+		Window:     params.Get("window"),
+		Limit:      10,
+	}
+
+	if input.Window == "" {
+		input.Window = model.TrendingWindowWeek
+	}
+
+	if value := params.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil {
+			HandleErrorResponse(w, errs.NewBadRequestError("limit must be a number"))
+			return
+		}
+		input.Limit = limit
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		HandleErrorResponse(w, errs.NewBadRequestError("Request is not valid"))
+		return
+	}
+
+	trending, err := h.trendingService.GetTrending(r.Context(), input)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	events := make([]*model.Event, 0, len(trending))
+	for _, entry := range trending {
+		events = append(events, entry.Event)
+	}
+
+	locale := requestLocale(r)
+	h.translationService.LocalizeEvents(r.Context(), locale, events...)
+	setContentLanguage(w, locale)
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+		Data:      trending,
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/hafiztri123/src/internal/model"
 	errs "github.com/hafiztri123/src/internal/pkg/error"
@@ -15,7 +16,9 @@ import (
 
 type UserHandler interface {
     UpdateProfile(w http.ResponseWriter, r *http.Request)
+    PatchProfile(w http.ResponseWriter, r *http.Request)
     GetProfile(w http.ResponseWriter, r *http.Request)
+    GetOrganizer(w http.ResponseWriter, r *http.Request)
     ChangePassword(w http.ResponseWriter, r *http.Request)
     UploadProfileImage(w http.ResponseWriter, r *http.Request)
 }
@@ -42,7 +45,13 @@ func (h userHandlerImpl) UpdateProfile(w http.ResponseWriter, r *http.Request) {
         return
 	}
 
-	err = h.userService.UpdateProfile(userID, &input)
+	version, err := parseIfMatch(r)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	err = h.userService.UpdateProfile(userID, &input, version)
 	if err != nil {
         HandleErrorResponse(w, err)
         return
@@ -54,6 +63,31 @@ func (h userHandlerImpl) UpdateProfile(w http.ResponseWriter, r *http.Request) {
 }
 
 
+func (h userHandlerImpl) PatchProfile(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
+
+	patch, err := readMergePatch(r)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	version, err := parseIfMatch(r)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	if err := h.userService.PatchProfile(userID, patch, version); err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	respondWithJSON(w, 200, response.Response{
+		Timestamp: time.Now(),
+	})
+}
+
 func (h userHandlerImpl) GetProfile(w http.ResponseWriter, r *http.Request){
 	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
 	if userID == "" {
@@ -67,6 +101,8 @@ func (h userHandlerImpl) GetProfile(w http.ResponseWriter, r *http.Request){
         return
 	}
 
+	setETag(w, profile.Version)
+
 	respondWithJSON(w, 200, response.Response{
 		Timestamp: time.Now(),
 		Data: profile,
@@ -74,6 +110,35 @@ func (h userHandlerImpl) GetProfile(w http.ResponseWriter, r *http.Request){
  
 }
 
+// GetOrganizer godoc
+// @Summary      Get organizer
+// @Description  Get the public profile of an organizer by ID or slug. Old slugs redirect to the current one.
+// @Tags         users
+// @Produce      json
+// @Param        id   path      string  true  "Organizer ID or slug"
+// @Success      200  {object}  response.Response{data=model.OrganizerProfile}
+// @Success      301  "Moved to the current slug"
+// @Failure      404  {object}  response.Response
+// @Router       /organizers/{id} [get]
+func (h userHandlerImpl) GetOrganizer(w http.ResponseWriter, r *http.Request) {
+	organizerID := chi.URLParam(r, "id")
+
+	organizer, err := h.userService.GetOrganizer(organizerID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	if redirectToSlug(w, r, "/api/v1/organizers/", organizerID, organizer.ID, organizer.Slug) {
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response.Response{
+		Timestamp: time.Now(),
+		Data:      organizer,
+	})
+}
+
 func (h userHandlerImpl) ChangePassword(w http.ResponseWriter, r *http.Request) {
 	userID := r.Context().Value("user").(jwt.MapClaims)["user_id"].(string)
 	if userID == "" {
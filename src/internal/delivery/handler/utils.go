@@ -3,10 +3,16 @@ package handler
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"github.com/hafiztri123/src/internal/pkg/mergepatch"
 )
 
 // Response defines the structure of the API response.
@@ -88,6 +94,26 @@ func HandleErrorResponse(w http.ResponseWriter, err error) {
         return
     }
 
+    var conflictErr  *errs.ConflictError
+    if errors.As(err, &conflictErr) {
+        respondWithJSON(w, conflictErr.Code, Response{
+            Timestamp: time.Now(),
+            Code: conflictErr.Code,
+            Message: conflictErr.Message,
+        })
+        return
+    }
+
+    var preconditionErr  *errs.PreconditionFailedError
+    if errors.As(err, &preconditionErr) {
+        respondWithJSON(w, preconditionErr.Code, Response{
+            Timestamp: time.Now(),
+            Code: preconditionErr.Code,
+            Message: preconditionErr.Message,
+        })
+        return
+    }
+
     var EntityTooLargeErr  *errs.EntityTooLargeError
     if errors.As(err, &EntityTooLargeErr) {
         respondWithJSON(w, unauthorizedErr.Code, Response{
@@ -111,4 +137,73 @@ func respondWithJSON(w http.ResponseWriter, statusCode int, payload interface{})
     w.Header().Set("Content-Type", "application/json")
     w.WriteHeader(statusCode)
     json.NewEncoder(w).Encode(payload)
-}
\ No newline at end of file
+}
+
+// redirectToSlug sends a permanent redirect to the canonical URL of a resource that
+// was requested by an outdated slug. It reports whether it did.
+func redirectToSlug(w http.ResponseWriter, r *http.Request, prefix, requested, id, slug string) bool {
+    if requested == id || requested == slug || slug == "" {
+        return false
+    }
+
+    location := prefix + slug
+    if r.URL.RawQuery != "" {
+        location += "?" + r.URL.RawQuery
+    }
+
+    http.Redirect(w, r, location, http.StatusMovedPermanently)
+    return true
+}
+
+// requestLocale returns the locale negotiated for the request by the locale
+// middleware, or "" when it did not run.
+func requestLocale(r *http.Request) string {
+    locale, _ := r.Context().Value("locale").(string)
+    return locale
+}
+
+// setContentLanguage tells the client which locale a localized response was
+// negotiated for.
+func setContentLanguage(w http.ResponseWriter, locale string) {
+    if locale != "" {
+        w.Header().Set("Content-Language", locale)
+    }
+}
+
+// setETag exposes the version of a resource as a strong entity tag.
+func setETag(w http.ResponseWriter, version int) {
+    w.Header().Set("ETag", fmt.Sprintf("\"%d\"", version))
+}
+
+// parseIfMatch returns the version expected by the If-Match header, or 0 when the
+// header is absent or "*". A tag that is not one of ours can never match.
+func parseIfMatch(r *http.Request) (int, error) {
+    header := strings.TrimSpace(r.Header.Get("If-Match"))
+    if header == "" || header == "*" {
+        return 0, nil
+    }
+
+    tag := strings.TrimPrefix(header, "W/")
+    version, err := strconv.Atoi(strings.Trim(tag, "\""))
+    if err != nil || version < 1 {
+        return 0, errs.NewPreconditionFailedError("If-Match does not match the current version")
+    }
+
+    return version, nil
+}
+
+// readMergePatch reads an RFC 7396 merge patch body. Plain application/json is
+// accepted as well for clients that cannot set the merge patch media type.
+func readMergePatch(r *http.Request) ([]byte, error) {
+    mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+    if err != nil || (mediaType != mergepatch.ContentType && mediaType != "application/json") {
+        return nil, errs.NewBadRequestError("Content-Type must be application/merge-patch+json")
+    }
+
+    patch, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+    if err != nil || len(patch) == 0 {
+        return nil, errs.NewBadRequestError("Request body is missing")
+    }
+
+    return patch, nil
+}
@@ -0,0 +1,57 @@
+package model
+
+import "time"
+
+const (
+	MetricViews 		= "views"
+	MetricVisitors 		= "visitors"
+	MetricRegistrations = "registrations"
+	MetricCheckIns 		= "checkins"
+)
+
+// EventDailyStat is one day of an event's analytics rolled up from Redis.
+// VisitorSketch is the raw HyperLogLog of that day's visitors so that unique
+// visitors can still be counted across days after the rollup. RollupID is the
+// last batch of counters added, so a batch retried after a failure is not added
+// twice.
+type EventDailyStat struct {
+	EventID 		string 		`gorm:"type:uuid;primary_key" json:"event_id"`
+	Day 			time.Time 	`gorm:"type:date;primary_key" json:"day"`
+	Views 			int64 		`gorm:"not null;default:0" json:"views"`
+	UniqueVisitors 	int64 		`gorm:"not null;default:0" json:"unique_visitors"`
+	Registrations 	int64 		`gorm:"not null;default:0" json:"registrations"`
+	CheckIns 		int64 		`gorm:"not null;default:0" json:"check_ins"`
+	VisitorSketch 	[]byte 		`gorm:"type:bytea" json:"-"`
+	RollupID 		string 		`gorm:"type:varchar(36)" json:"-"`
+	UpdatedAt 		time.Time 	`json:"-"`
+}
+
+type AnalyticsInput struct {
+	From 	time.Time
+	To 		time.Time
+}
+
+type AnalyticsDay struct {
+	Date 			string 	`json:"date"`
+	Views 			int64 	`json:"views"`
+	UniqueVisitors 	int64 	`json:"unique_visitors"`
+	Registrations 	int64 	`json:"registrations"`
+	CheckIns 		int64 	`json:"check_ins"`
+}
+
+type AnalyticsFunnel struct {
+	Views 					int64 	`json:"views"`
+	UniqueVisitors 			int64 	`json:"unique_visitors"`
+	Registrations 			int64 	`json:"registrations"`
+	CheckIns 				int64 	`json:"check_ins"`
+	VisitorToRegistration 	float64 `json:"visitor_to_registration"`
+	RegistrationToCheckIn 	float64 `json:"registration_to_check_in"`
+}
+
+type EventAnalytics struct {
+	EventID 	string 			`json:"event_id"`
+	From 		string 			`json:"from"`
+	To 			string 			`json:"to"`
+	Series 		[]AnalyticsDay 	`json:"series"`
+	Funnel 		AnalyticsFunnel `json:"funnel"`
+}
@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// Attendee is one ticket of a group booking. The booker can name each attendee
+// after paying; every attendee has a ticket code of its own and is checked in on
+// its own. Changing an attendee's details reissues the ticket under a new nonce.
+type Attendee struct {
+	ID 				string 		`gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	EventID 		string 		`gorm:"type:uuid;not null;index" json:"event_id"`
+	RegistrationID 	string 		`gorm:"type:uuid;not null;index" json:"registration_id"`
+	OrderID 		*string 	`gorm:"type:uuid;index" json:"order_id"`
+	TicketTypeID 	*string 	`gorm:"type:uuid" json:"ticket_type_id"`
+	SeatID 			*string 	`gorm:"type:uuid" json:"seat_id"`
+	Name 			string 		`gorm:"type:varchar(255);not null;default:''" json:"name"`
+	Email 			string 		`gorm:"type:varchar(255);not null;default:'';index" json:"email"`
+	TicketNonce 	string 		`gorm:"type:varchar(64);not null;default:''" json:"-"`
+	CheckedInAt 	*time.Time 	`json:"checked_in_at"`
+	CreatedAt 		time.Time 	`gorm:"not null" json:"created_at"`
+	UpdatedAt 		time.Time 	`gorm:"not null" json:"updated_at"`
+}
+
+type AttendeeInput struct {
+	ID 		string 	`json:"id" validate:"required"`
+	Name 	string 	`json:"name" validate:"max=255"`
+	Email 	string 	`json:"email" validate:"omitempty,email,max=255"`
+}
+
+type UpdateAttendeesInput struct {
+	Attendees 	[]AttendeeInput 	`json:"attendees" validate:"required,min=1,dive"`
+}
+
+// AttendeeTicket is an attendee with the code to put in their ticket's QR code.
+type AttendeeTicket struct {
+	Attendee 	*Attendee 	`json:"attendee"`
+	Code 		string 		`json:"code"`
+}
@@ -12,7 +12,38 @@ type LoginInput struct {
     Password string `json:"password" validate:"required,min=6" example:"password123"`
 }
 
-// LoginResponse represents the login response payload
+// LoginResponse represents the login response payload. Token is a short-lived
+// access token that expires after ExpiresIn seconds; RefreshToken obtains a new pair.
 type LoginResponse struct {
-    Token string `json:"token" example:"eyJhbGciOiJIUzI1NiIs..."`
+    Token        string `json:"token" example:"eyJhbGciOiJIUzI1NiIs..."`
+    ExpiresIn    int64  `json:"expires_in" example:"900"`
+    RefreshToken string `json:"refresh_token" example:"q3xS0m3OpaqueT0ken..."`
+}
+
+// RefreshInput represents the refresh request payload
+type RefreshInput struct {
+    RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// VerifyEmailInput represents the email verification request payload
+type VerifyEmailInput struct {
+    Token string `json:"token" validate:"required"`
+}
+
+// ForgotPasswordInput represents the forgot-password request payload
+type ForgotPasswordInput struct {
+    Email string `json:"email" validate:"required,email" example:"user@example.com"`
+}
+
+// ResetPasswordInput represents the password reset request payload
+type ResetPasswordInput struct {
+    Token       string `json:"token" validate:"required"`
+    NewPassword string `json:"new_password" validate:"required,min=6" example:"newpassword123"`
+}
+
+// LogoutInput represents the logout request payload. RefreshToken, when given, is
+// revoked along with the access token; All logs the user out of every session.
+type LogoutInput struct {
+    RefreshToken string `json:"refresh_token"`
+    All          bool   `json:"all"`
 }
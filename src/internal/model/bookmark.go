@@ -0,0 +1,10 @@
+package model
+
+import "time"
+
+type Bookmark struct {
+	UserID 		string 		`gorm:"type:uuid;primary_key" json:"user_id"`
+	EventID 	string 		`gorm:"type:uuid;primary_key;index" json:"event_id"`
+	Event 		*Event 		`gorm:"foreignKey:EventID" json:"event,omitempty"`
+	CreatedAt 	time.Time 	`gorm:"not null" json:"created_at"`
+}
@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+const (
+	CalendarDay 	= "day"
+	CalendarWeek 	= "week"
+	CalendarMonth 	= "month"
+)
+
+type CalendarInput struct {
+	From 		time.Time 			`json:"from" validate:"required"`
+	To 			time.Time 			`json:"to" validate:"required,gtfield=From"`
+	Granularity string 				`json:"granularity" validate:"oneof=day week month"`
+	Location 	*time.Location 		`json:"-"`
+	Filters 	SearchEventsInput 	`json:"filters" validate:"-"`
+}
+
+// CalendarBucket holds the events that overlap one day, week or month. An event
+// spanning several buckets appears in each of them.
+type CalendarBucket struct {
+	Label 	string 		`json:"label"`
+	Start 	time.Time 	`json:"start"`
+	End 	time.Time 	`json:"end"`
+	Count 	int 		`json:"count"`
+	Events 	[]*Event 	`json:"events"`
+}
+
+type CalendarOutput struct {
+	From 		time.Time 			`json:"from"`
+	To 			time.Time 			`json:"to"`
+	Granularity string 				`json:"granularity"`
+	TimeZone 	string 				`json:"tz"`
+	Buckets 	[]*CalendarBucket 	`json:"buckets"`
+}
@@ -1,13 +1,23 @@
 package model
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Category struct {
 	ID 			string 		`gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
-	Name 		string 		`gorm:"type:varchar(100);unique;not null" json:"name"`
+	OrganizationID string 	`gorm:"type:uuid;uniqueIndex:idx_categories_organization_name,priority:1,where:deleted_at IS NULL" json:"organization_id"`
+	Name 		string 		`gorm:"type:varchar(100);not null;uniqueIndex:idx_categories_organization_name,priority:2,where:deleted_at IS NULL" json:"name"`
+	Slug 		string 		`gorm:"type:varchar(255);uniqueIndex" json:"slug"`
 	Description string 		`gorm:"type:text" json:"description"`
+	CreatorID 	*string 	`gorm:"type:uuid;index" json:"creator_id,omitempty"`
 	CreatedAt 	time.Time 	`gorm:"not null" json:"created_at"`
 	UpdatedAt 	time.Time 	`gorm:"not null" json:"updated_at"`
+	Version 	int 		`gorm:"not null;default:1" json:"version"`
+	DeletedAt 	gorm.DeletedAt `gorm:"index" json:"deleted_at" swaggertype:"string"`
+	Locale 		string 		`gorm:"-" json:"locale,omitempty"`
 }
 
 type CreateCategoryInput struct {
@@ -18,4 +28,10 @@ type CreateCategoryInput struct {
 type UpdateCategoryInput struct {
     Name        string `json:"name"`
     Description string `json:"description"`
-}
\ No newline at end of file
+}
+
+// CategorySnapshot is the document that category merge patches are applied to.
+type CategorySnapshot struct {
+    Name        string `json:"name" validate:"required,max=100"`
+    Description string `json:"description"`
+}
@@ -1,19 +1,30 @@
 package model
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Event struct {
 	ID 				string 		`gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
 	Title 			string 		`gorm:"type:varchar(255);not null" json:"title"`
+	Slug 			string 		`gorm:"type:varchar(255);uniqueIndex" json:"slug"`
 	Description 	string 		`gorm:"type:text" json:"description"`
+	Venue 			string 		`gorm:"type:varchar(255);index" json:"venue"`
 	StartDate 		time.Time 	`gorm:"not null" json:"start_date"`
 	EndDate 		time.Time 	`gorm:"not null" json:"end_date"`
 	CreatorID 		string 		`gorm:"type:uuid;not null" json:"creator_id"`
+	OrganizationID 	string 		`gorm:"type:uuid;index" json:"organization_id"`
 	CategoryID 		string 		`gorm:"type:uuid" json:"category_id"`
 	Tags 			[]Tag		`gorm:"many2many:event_tags" json:"tags"`
 	Files 			[]File		`gorm:"foreignKey:EventID" json:"files"`
 	CreatedAt 		time.Time 	`gorm:"not null" json:"created_at"`
 	UpdatedAt 		time.Time 	`gorm:"not null" json:"updated_at"`
+	Version 		int 		`gorm:"not null;default:1" json:"version"`
+	CancelledAt 	*time.Time 	`json:"cancelled_at"`
+	DeletedAt 		gorm.DeletedAt `gorm:"index" json:"deleted_at" swaggertype:"string"`
+	Locale 			string 		`gorm:"-" json:"locale,omitempty"`
 }
 
 type Tag struct {
@@ -25,15 +36,28 @@ type Tag struct {
 type File struct {
 	ID 			string 		`gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
 	EventID 	string 		`gorm:"type:uuid;not null" json:"event_id"`
+	OrganizationID string 	`gorm:"type:uuid;index" json:"organization_id"`
 	FileName 	string 		`gorm:"type:varchar(255);not null" json:"file_name"`
 	FileType 	string 		`gorm:"type:varchar(100);not null" json:"file_type"`
 	FileURL 	string 		`gorm:"type:text;not null" json:"file_url"`
+	Position 	int 		`gorm:"not null;default:0" json:"position"`
 	CreatedAt 	time.Time 	`gorm:"not null" json:"created_at"`
+	UpdatedAt 	time.Time 	`json:"updated_at"`
+}
+
+// FileSnapshot is the document that file metadata merge patches are applied to.
+type FileSnapshot struct {
+	FileName 	string 		`json:"file_name" validate:"required,max=255"`
+}
+
+type ReorderFilesInput struct {
+	FileIDs 	[]string 	`json:"file_ids" validate:"required,min=1,dive,required"`
 }
 
 type CreateEventInput struct {
 	Title 		string 		`json:"title" validate:"required"`
 	Description string 		`json:"description"`
+	Venue 		string 		`json:"venue"`
 	CategoryID 	string 		`json:"category_id"`
 	StartDate 	time.Time 	`json:"start_date" validate:"required"`
 	EndDate 	time.Time 	`json:"end_date" validate:"required,gtfield=StartDate"`
@@ -42,6 +66,8 @@ type CreateEventInput struct {
 type UpdateEventInput struct {
 	Title 		string 		`json:"title" validate:"required"`
 	Description string 		`json:"description"`
+	Venue 		string 		`json:"venue"`
+	CategoryID 	string 		`json:"category_id"`
 	StartDate 	time.Time 	`json:"start_date" validate:"required"`
 	EndDate 	time.Time 	`json:"end_date" validate:"required,gtfield=StartDate"`
 }
@@ -72,6 +98,28 @@ type SearchEventsOutput struct {
 	TotalPages 	int 			`json:"total_pages"`
 }
 
+type ConflictsInput struct {
+	StartDate 	time.Time 	`json:"start_date" validate:"required"`
+	EndDate 	time.Time 	`json:"end_date" validate:"required,gtfield=StartDate"`
+	Venue 		string 		`json:"venue,omitempty"`
+	Creator 	string 		`json:"creator,omitempty"`
+}
+
+// ScheduleConflict describes two events whose time ranges overlap and that
+// share a venue, a creator or an attendee.
+type ScheduleConflict struct {
+	Type 		string 		`json:"type"`
+	Events 		[]*Event 	`json:"events"`
+	OverlapFrom time.Time 	`json:"overlap_from"`
+	OverlapTo 	time.Time 	`json:"overlap_to"`
+}
+
+const (
+	ConflictTypeVenue 		= "venue"
+	ConflictTypeCreator 	= "creator"
+	ConflictTypeAttendee 	= "attendee"
+)
+
 type UploadFile struct {
 	FileName 	string 		`gorm:"type:varchar(255);not null" json:"file_name"`
 	FileType 	string 		`gorm:"type:varchar(100);not null" json:"file_type"`
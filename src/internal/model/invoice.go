@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+const (
+	DocumentInvoice = "invoice"
+	DocumentReceipt = "receipt"
+)
+
+// Invoice is issued once per paid order. Number is sequential and gap-free per
+// organizer; the PDF files are filled in once the documents have been stored. The
+// PDFs are private, so only their storage IDs are kept and they are never exposed.
+type Invoice struct {
+	ID 				string 		`gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	OrderID 		string 		`gorm:"type:uuid;not null;uniqueIndex" json:"order_id"`
+	OrganizerID 	string 		`gorm:"type:uuid;not null;uniqueIndex:idx_invoice_organizer_number" json:"organizer_id"`
+	Number 			int 		`gorm:"not null;uniqueIndex:idx_invoice_organizer_number" json:"number"`
+	InvoiceNumber 	string 		`gorm:"type:varchar(50);not null" json:"invoice_number"`
+	Currency 		string 		`gorm:"type:varchar(3);not null" json:"currency"`
+	Subtotal 		int64 		`gorm:"not null" json:"subtotal"`
+	Discount 		int64 		`gorm:"not null" json:"discount"`
+	TaxRate 		float64 	`gorm:"not null" json:"tax_rate"`
+	Tax 			int64 		`gorm:"not null" json:"tax"`
+	Total 			int64 		`gorm:"not null" json:"total"`
+	InvoiceFile 	string 		`gorm:"column:invoice_url;type:text" json:"-"`
+	ReceiptFile 	string 		`gorm:"column:receipt_url;type:text" json:"-"`
+	IssuedAt 		time.Time 	`gorm:"not null" json:"issued_at"`
+	CreatedAt 		time.Time 	`gorm:"not null" json:"created_at"`
+	UpdatedAt 		time.Time 	`gorm:"not null" json:"updated_at"`
+}
+
+// InvoiceSequence holds the last invoice number handed out to an organizer.
+type InvoiceSequence struct {
+	OrganizerID 	string 	`gorm:"type:uuid;primary_key"`
+	LastNumber 		int 	`gorm:"not null;default:0"`
+}
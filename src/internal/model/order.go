@@ -0,0 +1,120 @@
+package model
+
+import "time"
+
+const (
+	OrderStatusPending 		= "pending"
+	OrderStatusPaid 		= "paid"
+	OrderStatusExpired 		= "expired"
+	OrderStatusFailed 		= "failed"
+	OrderStatusCancelled 	= "cancelled"
+	OrderStatusRefunded 	= "refunded"
+	OrderStatusPartiallyRefunded = "partially_refunded"
+)
+
+const (
+	PaymentStatusRequiresPayment 	= "requires_payment"
+	PaymentStatusSucceeded 			= "succeeded"
+	PaymentStatusFailed 			= "failed"
+	PaymentStatusRefunding 			= "refunding"
+	PaymentStatusRefunded 			= "refunded"
+)
+
+// TicketType is a purchasable kind of ticket for an event. Sold and Reserved are
+// maintained atomically so that Sold + Reserved never exceeds Quantity. Seated
+// ticket types are the price tiers of a seat map: their quantity is the number of
+// seats in the tier and they are only sold together with a seat.
+type TicketType struct {
+	ID 			string 		`gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	EventID 	string 		`gorm:"type:uuid;not null;index" json:"event_id"`
+	Name 		string 		`gorm:"type:varchar(100);not null" json:"name"`
+	Description string 		`gorm:"type:text" json:"description"`
+	Price 		int64 		`gorm:"not null;default:0" json:"price"`
+	Currency 	string 		`gorm:"type:varchar(3);not null" json:"currency"`
+	Quantity 	int 		`gorm:"not null" json:"quantity"`
+	Sold 		int 		`gorm:"not null;default:0" json:"sold"`
+	Reserved 	int 		`gorm:"not null;default:0" json:"reserved"`
+	Seated 		bool 		`gorm:"not null;default:false" json:"seated"`
+	CreatedAt 	time.Time 	`gorm:"not null" json:"created_at"`
+	UpdatedAt 	time.Time 	`gorm:"not null" json:"updated_at"`
+}
+
+type Order struct {
+	ID 			string 		`gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	UserID 		string 		`gorm:"type:uuid;not null;index" json:"user_id"`
+	EventID 	string 		`gorm:"type:uuid;not null;index" json:"event_id"`
+	Status 		string 		`gorm:"type:varchar(20);not null;index" json:"status"`
+	Currency 	string 		`gorm:"type:varchar(3);not null" json:"currency"`
+	Subtotal 	int64 		`gorm:"not null;default:0" json:"subtotal"`
+	Discount 	int64 		`gorm:"not null;default:0" json:"discount"`
+	TotalAmount int64 		`gorm:"not null" json:"total_amount"`
+	PromoCodeID *string 	`gorm:"type:uuid" json:"promo_code_id"`
+	ExpiresAt 	time.Time 	`gorm:"not null;index" json:"expires_at"`
+	PaidAt 		*time.Time 	`json:"paid_at"`
+	Answers 	map[string]interface{} `gorm:"type:jsonb;serializer:json" json:"answers,omitempty"`
+	Items 		[]OrderItem `gorm:"foreignKey:OrderID" json:"items"`
+	Payments 	[]Payment 	`gorm:"foreignKey:OrderID" json:"payments"`
+	Seats 		[]Seat 		`gorm:"foreignKey:OrderID" json:"seats,omitempty"`
+	CreatedAt 	time.Time 	`gorm:"not null" json:"created_at"`
+	UpdatedAt 	time.Time 	`gorm:"not null" json:"updated_at"`
+}
+
+type OrderItem struct {
+	ID 				string 	`gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	OrderID 		string 	`gorm:"type:uuid;not null;index" json:"order_id"`
+	TicketTypeID 	string 	`gorm:"type:uuid;not null" json:"ticket_type_id"`
+	Quantity 		int 	`gorm:"not null" json:"quantity"`
+	UnitPrice 		int64 	`gorm:"not null" json:"unit_price"`
+	Amount 			int64 	`gorm:"not null" json:"amount"`
+}
+
+type Payment struct {
+	ID 				string 		`gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	OrderID 		string 		`gorm:"type:uuid;not null;index" json:"order_id"`
+	Provider 		string 		`gorm:"type:varchar(50);not null;uniqueIndex:idx_payment_provider_ref" json:"provider"`
+	ProviderRef 	string 		`gorm:"type:varchar(255);not null;uniqueIndex:idx_payment_provider_ref" json:"provider_ref"`
+	Amount 			int64 		`gorm:"not null" json:"amount"`
+	Currency 		string 		`gorm:"type:varchar(3);not null" json:"currency"`
+	Status 			string 		`gorm:"type:varchar(30);not null" json:"status"`
+	ClientSecret 	string 		`gorm:"-" json:"client_secret,omitempty"`
+	CreatedAt 		time.Time 	`gorm:"not null" json:"created_at"`
+	UpdatedAt 		time.Time 	`gorm:"not null" json:"updated_at"`
+}
+
+// TicketLimit caps how many tickets one user may hold for an event, counting
+// pending orders and the user's booking. Events without a stored limit use the configured
+// default; MaxPerUser of zero means no cap.
+type TicketLimit struct {
+	EventID 	string 		`gorm:"type:uuid;primary_key" json:"event_id"`
+	MaxPerUser 	int 		`gorm:"not null" json:"max_tickets_per_user"`
+	UpdatedAt 	time.Time 	`json:"updated_at"`
+}
+
+type TicketLimitInput struct {
+	MaxPerUser 	*int 	`json:"max_tickets_per_user" validate:"required,min=0"`
+}
+
+type CreateTicketTypeInput struct {
+	Name 		string 	`json:"name" validate:"required,max=100"`
+	Description string 	`json:"description"`
+	Price 		int64 	`json:"price" validate:"min=0"`
+	Currency 	string 	`json:"currency" validate:"omitempty,len=3"`
+	Quantity 	int 	`json:"quantity" validate:"required,min=1"`
+}
+
+type CheckoutItemInput struct {
+	TicketTypeID 	string 	`json:"ticket_type_id" validate:"required"`
+	Quantity 		int 	`json:"quantity" validate:"required,min=1"`
+}
+
+type CheckoutInput struct {
+	EventID 	string 				`json:"event_id" validate:"required"`
+	PromoCode 	string 				`json:"promo_code"`
+	Items 		[]CheckoutItemInput `json:"items" validate:"required,min=1,dive"`
+	Answers 	map[string]interface{} `json:"answers"`
+}
+
+type CheckoutOutput struct {
+	Order 	*Order 		`json:"order"`
+	Payment *Payment 	`json:"payment,omitempty"`
+}
@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+const (
+	OrganizationRoleOwner 	= "owner"
+	OrganizationRoleAdmin 	= "admin"
+	OrganizationRoleMember 	= "member"
+)
+
+// Organization is a tenant. Events, categories and files belong to exactly one
+// organization and are only visible to requests made for it. Anyone signed in may
+// publish in an open organization; otherwise only members may.
+type Organization struct {
+	ID 			string 		`gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	Name 		string 		`gorm:"type:varchar(100);not null" json:"name"`
+	Slug 		string 		`gorm:"type:varchar(255);uniqueIndex" json:"slug"`
+	Open 		bool 		`gorm:"not null;default:false" json:"open"`
+	CreatedAt 	time.Time 	`gorm:"not null" json:"created_at"`
+	UpdatedAt 	time.Time 	`gorm:"not null" json:"updated_at"`
+}
+
+type OrganizationMember struct {
+	OrganizationID 	string 		`gorm:"type:uuid;primary_key" json:"organization_id"`
+	UserID 			string 		`gorm:"type:uuid;primary_key;index" json:"user_id"`
+	User 			*User 		`gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Role 			string 		`gorm:"type:varchar(10);not null" json:"role"`
+	CreatedAt 		time.Time 	`gorm:"not null" json:"created_at"`
+	UpdatedAt 		time.Time 	`gorm:"not null" json:"updated_at"`
+}
+
+// MembershipView is an organization together with the current user's role in it.
+type MembershipView struct {
+	Organization 	*Organization 	`json:"organization"`
+	Role 			string 			`json:"role"`
+}
+
+type CreateOrganizationInput struct {
+	Name 		string 		`json:"name" validate:"required,max=100"`
+}
+
+type SetMemberInput struct {
+	Role 		string 		`json:"role" validate:"required,oneof=owner admin member"`
+}
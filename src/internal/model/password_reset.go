@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// PasswordResetToken is a token mailed to a user who forgot their password. Only
+// the SHA-256 hash of the token is stored, and it can be used once.
+type PasswordResetToken struct {
+	ID 			string 		`gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	UserID 		string 		`gorm:"type:uuid;not null;index" json:"user_id"`
+	TokenHash 	string 		`gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
+	ExpiresAt 	time.Time 	`gorm:"not null;index" json:"expires_at"`
+	UsedAt 		*time.Time 	`json:"used_at"`
+	CreatedAt 	time.Time 	`gorm:"not null" json:"created_at"`
+}
@@ -0,0 +1,90 @@
+package model
+
+import "time"
+
+const (
+	DiscountPercentage 	= "percentage"
+	DiscountFixed 		= "fixed"
+)
+
+// PromoCode discounts an order. A code with an EventID applies to that event only;
+// without one it applies to every event of the organizer. Zero limits mean unlimited.
+type PromoCode struct {
+	ID 				string 		`gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	Code 			string 		`gorm:"type:varchar(50);not null;uniqueIndex:idx_promo_organizer_code" json:"code"`
+	OrganizerID 	string 		`gorm:"type:uuid;not null;uniqueIndex:idx_promo_organizer_code" json:"organizer_id"`
+	EventID 		*string 	`gorm:"type:uuid;index" json:"event_id"`
+	DiscountType 	string 		`gorm:"type:varchar(20);not null" json:"discount_type"`
+	DiscountValue 	int64 		`gorm:"not null" json:"discount_value"`
+	Currency 		string 		`gorm:"type:varchar(3)" json:"currency"`
+	MaxUses 		int 		`gorm:"not null;default:0" json:"max_uses"`
+	MaxUsesPerUser 	int 		`gorm:"not null;default:0" json:"max_uses_per_user"`
+	UsedCount 		int 		`gorm:"not null;default:0" json:"used_count"`
+	StartsAt 		*time.Time 	`json:"starts_at"`
+	EndsAt 			*time.Time 	`json:"ends_at"`
+	TicketTypeIDs 	[]string 	`gorm:"type:jsonb;serializer:json" json:"ticket_type_ids"`
+	CreatedAt 		time.Time 	`gorm:"not null" json:"created_at"`
+	UpdatedAt 		time.Time 	`gorm:"not null" json:"updated_at"`
+}
+
+// ActiveAt reports whether the code's validity window contains t.
+func (p *PromoCode) ActiveAt(t time.Time) bool {
+	if p.StartsAt != nil && t.Before(*p.StartsAt) {
+		return false
+	}
+	if p.EndsAt != nil && !t.Before(*p.EndsAt) {
+		return false
+	}
+	return true
+}
+
+// AppliesTo reports whether the code may discount the given ticket type.
+func (p *PromoCode) AppliesTo(ticketTypeID string) bool {
+	if len(p.TicketTypeIDs) == 0 {
+		return true
+	}
+	for _, id := range p.TicketTypeIDs {
+		if id == ticketTypeID {
+			return true
+		}
+	}
+	return false
+}
+
+// PromoRedemption records that a code was used by an order. It is removed again if
+// the order never gets paid.
+type PromoRedemption struct {
+	ID 				string 		`gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	PromoCodeID 	string 		`gorm:"type:uuid;not null;index" json:"promo_code_id"`
+	OrderID 		string 		`gorm:"type:uuid;not null;uniqueIndex" json:"order_id"`
+	UserID 			string 		`gorm:"type:uuid;not null;index" json:"user_id"`
+	Discount 		int64 		`gorm:"not null" json:"discount"`
+	CreatedAt 		time.Time 	`gorm:"not null" json:"created_at"`
+}
+
+type CreatePromoCodeInput struct {
+	Code 			string 		`json:"code" validate:"required,alphanum,max=50"`
+	EventID 		*string 	`json:"event_id"`
+	DiscountType 	string 		`json:"discount_type" validate:"required,oneof=percentage fixed"`
+	DiscountValue 	int64 		`json:"discount_value" validate:"required,min=1"`
+	Currency 		string 		`json:"currency" validate:"omitempty,len=3"`
+	MaxUses 		int 		`json:"max_uses" validate:"min=0"`
+	MaxUsesPerUser 	int 		`json:"max_uses_per_user" validate:"min=0"`
+	StartsAt 		*time.Time 	`json:"starts_at"`
+	EndsAt 			*time.Time 	`json:"ends_at"`
+	TicketTypeIDs 	[]string 	`json:"ticket_type_ids" validate:"dive,required"`
+}
+
+type PromoPreviewInput struct {
+	EventID 	string 				`json:"event_id" validate:"required"`
+	Code 		string 				`json:"code" validate:"required"`
+	Items 		[]CheckoutItemInput `json:"items" validate:"required,min=1,dive"`
+}
+
+type PromoPreviewOutput struct {
+	Code 		string 	`json:"code"`
+	Currency 	string 	`json:"currency"`
+	Subtotal 	int64 	`json:"subtotal"`
+	Discount 	int64 	`json:"discount"`
+	Total 		int64 	`json:"total"`
+}
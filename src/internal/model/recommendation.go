@@ -0,0 +1,46 @@
+package model
+
+import "time"
+
+const (
+	RecommendationForEvent 	= "event"
+	RecommendationForUser 	= "user"
+)
+
+// Reasons an event was recommended.
+const (
+	ReasonSharedTags 	= "shared_tags"
+	ReasonSameCategory 	= "same_category"
+	ReasonSameOrganizer = "same_organizer"
+	ReasonCoAttendance 	= "co_attendance"
+)
+
+// Recommendation is a precomputed suggestion of an event for a subject, which is
+// either another event or a user.
+type Recommendation struct {
+	SubjectType 	string 		`gorm:"type:varchar(10);primary_key" json:"-"`
+	SubjectID 		string 		`gorm:"type:uuid;primary_key" json:"-"`
+	EventID 		string 		`gorm:"type:uuid;primary_key" json:"event_id"`
+	Score 			float64 	`gorm:"not null" json:"score"`
+	Reasons 		[]string 	`gorm:"type:jsonb;serializer:json" json:"reasons"`
+	ComputedAt 		time.Time 	`gorm:"not null" json:"computed_at"`
+}
+
+// Attendance is a user's registration for an event.
+type Attendance struct {
+	UserID 	string
+	EventID string
+}
+
+// CoAttendance counts the users registered for both events.
+type CoAttendance struct {
+	SourceID 	string
+	TargetID 	string
+	Shared 		int
+}
+
+type RecommendedEvent struct {
+	Event 	*Event 		`json:"event"`
+	Score 	float64 	`json:"score"`
+	Reasons []string 	`json:"reasons"`
+}
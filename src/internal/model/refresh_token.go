@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// RefreshToken is an issued refresh token. Only the SHA-256 hash of the token is
+// stored. Each refresh rotates the token: the presented one is marked as used and
+// a new one is issued in the same family. A used token that is presented again
+// means the family has leaked, so the whole family is revoked.
+type RefreshToken struct {
+	ID 			string 		`gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	UserID 		string 		`gorm:"type:uuid;not null;index" json:"user_id"`
+	FamilyID 	string 		`gorm:"type:uuid;not null;index" json:"family_id"`
+	TokenHash 	string 		`gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
+	ExpiresAt 	time.Time 	`gorm:"not null;index" json:"expires_at"`
+	UsedAt 		*time.Time 	`json:"used_at"`
+	RevokedAt 	*time.Time 	`json:"revoked_at"`
+	CreatedAt 	time.Time 	`gorm:"not null" json:"created_at"`
+}
@@ -0,0 +1,95 @@
+package model
+
+import "time"
+
+const (
+	RefundStatusRequested 	= "requested"
+	RefundStatusProcessing 	= "processing"
+	RefundStatusSucceeded 	= "succeeded"
+	RefundStatusFailed 		= "failed"
+	RefundStatusRejected 	= "rejected"
+)
+
+const (
+	LedgerEntryRequested 	= "requested"
+	LedgerEntryRejected 	= "rejected"
+	LedgerEntryRefunded 	= "refunded"
+	LedgerEntryFailed 		= "failed"
+)
+
+// RefundPolicyTier refunds Percent of an order when the refund is requested at
+// least DaysBefore days before the event starts.
+type RefundPolicyTier struct {
+	DaysBefore 	int 	`json:"days_before" validate:"min=0"`
+	Percent 	int 	`json:"percent" validate:"min=0,max=100"`
+}
+
+// RefundPolicy holds the refund tiers of an event, ordered by DaysBefore descending.
+// An event without tiers is non-refundable unless it is cancelled.
+type RefundPolicy struct {
+	EventID 	string 				`gorm:"type:uuid;primary_key" json:"event_id"`
+	Tiers 		[]RefundPolicyTier 	`gorm:"type:jsonb;serializer:json;not null" json:"tiers"`
+	UpdatedAt 	time.Time 			`json:"updated_at"`
+}
+
+// PercentAt returns the share of an order that is refundable at the given time.
+func (p *RefundPolicy) PercentAt(start, now time.Time) int {
+	remaining := start.Sub(now)
+	for _, tier := range p.Tiers {
+		if remaining >= time.Duration(tier.DaysBefore)*24*time.Hour {
+			return tier.Percent
+		}
+	}
+	return 0
+}
+
+type Refund struct {
+	ID 			string 		`gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	OrderID 	string 		`gorm:"type:uuid;not null;index" json:"order_id"`
+	EventID 	string 		`gorm:"type:uuid;not null;index" json:"event_id"`
+	UserID 		string 		`gorm:"type:uuid;not null" json:"user_id"`
+	PaymentID 	*string 	`gorm:"type:uuid" json:"payment_id"`
+	Amount 		int64 		`gorm:"not null" json:"amount"`
+	Currency 	string 		`gorm:"type:varchar(3);not null" json:"currency"`
+	Percent 	int 		`gorm:"not null" json:"percent"`
+	Reason 		string 		`gorm:"type:text" json:"reason"`
+	Status 		string 		`gorm:"type:varchar(20);not null;index" json:"status"`
+	ProviderRef string 		`gorm:"type:varchar(255)" json:"provider_ref"`
+	ProcessedAt *time.Time 	`json:"processed_at"`
+	CreatedAt 	time.Time 	`gorm:"not null" json:"created_at"`
+	UpdatedAt 	time.Time 	`gorm:"not null" json:"updated_at"`
+}
+
+// RefundLedgerEntry is an append-only record of something that happened to a refund.
+type RefundLedgerEntry struct {
+	ID 			string 		`gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	RefundID 	string 		`gorm:"type:uuid;not null;index" json:"refund_id"`
+	OrderID 	string 		`gorm:"type:uuid;not null;index" json:"order_id"`
+	EventID 	string 		`gorm:"type:uuid;not null;index" json:"event_id"`
+	Type 		string 		`gorm:"type:varchar(20);not null" json:"type"`
+	Amount 		int64 		`gorm:"not null" json:"amount"`
+	Currency 	string 		`gorm:"type:varchar(3);not null" json:"currency"`
+	ActorID 	string 		`gorm:"type:uuid;not null" json:"actor_id"`
+	Note 		string 		`gorm:"type:text" json:"note"`
+	CreatedAt 	time.Time 	`gorm:"not null" json:"created_at"`
+}
+
+type RefundPolicyInput struct {
+	Tiers 	[]RefundPolicyTier 	`json:"tiers" validate:"dive"`
+}
+
+type RefundRequestInput struct {
+	Reason 	string 	`json:"reason" validate:"max=1000"`
+}
+
+type CancelEventInput struct {
+	Reason 	string 	`json:"reason" validate:"max=1000"`
+}
+
+type CancelEventOutput struct {
+	EventID 		string 		`json:"event_id"`
+	CancelledAt 	time.Time 	`json:"cancelled_at"`
+	Refunds 		[]*Refund 	`json:"refunds"`
+	FailedRefunds 	int 		`json:"failed_refunds"`
+	ReleasedOrders 	int 		`json:"released_orders"`
+}
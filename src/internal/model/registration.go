@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+// Registration is an attendee's ticket for an event. TicketNonce is changed when
+// the ticket is reissued to another attendee, which invalidates its old code. A
+// registration with Attendees is a group booking: its tickets are the attendees'.
+type Registration struct {
+	ID 			string 		`gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	EventID 	string 		`gorm:"type:uuid;not null;uniqueIndex:idx_registration_event_user" json:"event_id"`
+	UserID 		string 		`gorm:"type:uuid;not null;uniqueIndex:idx_registration_event_user;index" json:"user_id"`
+	Event 		*Event 		`gorm:"foreignKey:EventID" json:"event,omitempty"`
+	User 		*User 		`gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Answers 	map[string]interface{} `gorm:"type:jsonb;serializer:json" json:"answers,omitempty"`
+	Attendees 	[]Attendee 	`gorm:"foreignKey:RegistrationID" json:"attendees,omitempty"`
+	CheckedInAt *time.Time 	`json:"checked_in_at"`
+	TicketNonce string 		`gorm:"type:varchar(64);not null;default:''" json:"-"`
+	TransferCount int 		`gorm:"not null;default:0" json:"transfer_count"`
+	CreatedAt 	time.Time 	`gorm:"not null" json:"created_at"`
+}
+
+// CheckInInput names the attendee to check in, either directly or by the code
+// scanned from their ticket. Attendees of group bookings are named by AttendeeID.
+type CheckInInput struct {
+	UserID 		string 	`json:"user_id" validate:"required_without_all=Ticket AttendeeID"`
+	AttendeeID 	string 	`json:"attendee_id" validate:"required_without_all=UserID Ticket"`
+	Ticket 		string 	`json:"ticket" validate:"required_without_all=UserID AttendeeID"`
+}
+
+type RegistrationOutput struct {
+	Registration 	*Registration 	`json:"registration"`
+	Warnings 		[]string 		`json:"warnings,omitempty"`
+	Conflicts 		[]*Event 		`json:"conflicts,omitempty"`
+}
+
+// AttendeeExport is what an attendee export is written from.
+type AttendeeExport struct {
+	Event 			*Event
+	Form 			*RegistrationForm
+	Registrations 	[]*Registration
+}
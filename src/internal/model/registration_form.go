@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+const (
+	FormFieldText 		= "text"
+	FormFieldSelect 	= "select"
+	FormFieldCheckbox 	= "checkbox"
+)
+
+// RegistrationForm holds the questions an event asks its attendees when they
+// register. Answers are validated against it and stored on the registration.
+type RegistrationForm struct {
+	EventID 	string 		`gorm:"type:uuid;primary_key" json:"event_id"`
+	Fields 		[]FormField `gorm:"type:jsonb;serializer:json;not null" json:"fields"`
+	CreatedAt 	time.Time 	`gorm:"not null" json:"created_at"`
+	UpdatedAt 	time.Time 	`gorm:"not null" json:"updated_at"`
+}
+
+// FormField is one question of a registration form. Text answers may be limited
+// in length and matched against a pattern, select answers must be one of the
+// options and checkbox answers are true or false. A required checkbox must be
+// checked.
+type FormField struct {
+	Key 		string 		`json:"key" validate:"required,max=50"`
+	Label 		string 		`json:"label" validate:"required,max=255"`
+	Type 		string 		`json:"type" validate:"required,oneof=text select checkbox"`
+	Required 	bool 		`json:"required"`
+	Options 	[]string 	`json:"options,omitempty" validate:"omitempty,max=100,dive,required,max=255"`
+	MinLength 	*int 		`json:"min_length,omitempty" validate:"omitempty,min=0"`
+	MaxLength 	*int 		`json:"max_length,omitempty" validate:"omitempty,min=1,max=10000"`
+	Pattern 	string 		`json:"pattern,omitempty" validate:"omitempty,max=255"`
+}
+
+type PutRegistrationFormInput struct {
+	Fields 		[]FormField `json:"fields" validate:"max=50,dive"`
+}
+
+// EventRegistrationInput carries the answers to the event's registration form,
+// keyed by field key.
+type EventRegistrationInput struct {
+	Answers 	map[string]interface{} 	`json:"answers"`
+}
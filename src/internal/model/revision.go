@@ -0,0 +1,51 @@
+package model
+
+import "time"
+
+// EventRevision records who changed an event, when, and which fields changed.
+type EventRevision struct {
+	ID 			string 		`gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	EventID 	string 		`gorm:"type:uuid;not null;uniqueIndex:idx_event_revision_number" json:"event_id"`
+	Number 		int 		`gorm:"not null;uniqueIndex:idx_event_revision_number" json:"number"`
+	ActorID 	string 		`gorm:"type:uuid;not null" json:"actor_id"`
+	Changes 	JSON 		`gorm:"type:jsonb;not null" json:"changes" swaggertype:"object"`
+	Snapshot 	JSON 		`gorm:"type:jsonb;not null" json:"snapshot" swaggertype:"object"`
+	CreatedAt 	time.Time 	`gorm:"not null" json:"created_at"`
+}
+
+// EventSnapshot holds the revisioned fields of an event. It is also the document
+// that merge patches are applied to, so it carries the validation rules.
+type EventSnapshot struct {
+	Title 		string 		`json:"title" validate:"required"`
+	Description string 		`json:"description"`
+	Venue 		string 		`json:"venue"`
+	CategoryID 	string 		`json:"category_id" validate:"required"`
+	StartDate 	time.Time 	`json:"start_date" validate:"required"`
+	EndDate 	time.Time 	`json:"end_date" validate:"required,gtfield=StartDate"`
+}
+
+// FieldChange is the old and new value of a single field in a revision diff.
+type FieldChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+func NewEventSnapshot(event *Event) EventSnapshot {
+	return EventSnapshot{
+		Title:       event.Title,
+		Description: event.Description,
+		Venue:       event.Venue,
+		CategoryID:  event.CategoryID,
+		StartDate:   event.StartDate,
+		EndDate:     event.EndDate,
+	}
+}
+
+func (s EventSnapshot) ApplyTo(event *Event) {
+	event.Title = s.Title
+	event.Description = s.Description
+	event.Venue = s.Venue
+	event.CategoryID = s.CategoryID
+	event.StartDate = s.StartDate
+	event.EndDate = s.EndDate
+}
@@ -0,0 +1,84 @@
+package model
+
+import "time"
+
+const (
+	SeatStatusAvailable = "available"
+	SeatStatusHeld 		= "held"
+	SeatStatusTaken 	= "taken"
+)
+
+// SeatSection is a named area of an event's seat map, e.g. "Stalls" or "Balcony".
+type SeatSection struct {
+	ID 			string 		`gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	EventID 	string 		`gorm:"type:uuid;not null;index" json:"event_id"`
+	Name 		string 		`gorm:"type:varchar(100);not null" json:"name"`
+	Position 	int 		`gorm:"not null;default:0" json:"position"`
+	Rows 		[]SeatRow 	`gorm:"foreignKey:SectionID" json:"rows"`
+	CreatedAt 	time.Time 	`gorm:"not null" json:"created_at"`
+}
+
+type SeatRow struct {
+	ID 			string 	`gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	EventID 	string 	`gorm:"type:uuid;not null;index" json:"event_id"`
+	SectionID 	string 	`gorm:"type:uuid;not null;index" json:"section_id"`
+	Label 		string 	`gorm:"type:varchar(20);not null" json:"label"`
+	Position 	int 	`gorm:"not null;default:0" json:"position"`
+	Seats 		[]Seat 	`gorm:"foreignKey:RowID" json:"seats"`
+}
+
+// Seat is a single bookable seat. Its ticket type is the price tier it is sold at.
+// OrderID is set while an order holds the seat, from checkout until the order is
+// released or refunded; at most one order can ever hold it.
+type Seat struct {
+	ID 				string 	`gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	EventID 		string 	`gorm:"type:uuid;not null;index" json:"event_id"`
+	RowID 			string 	`gorm:"type:uuid;not null;index" json:"row_id"`
+	Label 			string 	`gorm:"type:varchar(20);not null" json:"label"`
+	Position 		int 	`gorm:"not null;default:0" json:"position"`
+	TicketTypeID 	string 	`gorm:"type:uuid;not null" json:"ticket_type_id"`
+	OrderID 		*string `gorm:"type:uuid;index" json:"-"`
+	Status 			string 	`gorm:"-" json:"status,omitempty"`
+}
+
+type SeatMap struct {
+	EventID 	string 			`json:"event_id"`
+	Sections 	[]*SeatSection 	`json:"sections"`
+}
+
+type PutSeatMapInput struct {
+	Sections 	[]SeatSectionInput 	`json:"sections" validate:"max=50,dive"`
+}
+
+type SeatSectionInput struct {
+	Name 	string 			`json:"name" validate:"required,max=100"`
+	Rows 	[]SeatRowInput 	`json:"rows" validate:"required,min=1,max=200,dive"`
+}
+
+type SeatRowInput struct {
+	Label 	string 		`json:"label" validate:"required,max=20"`
+	Seats 	[]SeatInput `json:"seats" validate:"required,min=1,max=500,dive"`
+}
+
+type SeatInput struct {
+	Label 			string 	`json:"label" validate:"required,max=20"`
+	TicketTypeID 	string 	`json:"ticket_type_id" validate:"required"`
+}
+
+// SeatHold locks seats for one user until it expires. Holds live in Redis only.
+type SeatHold struct {
+	ID 			string 		`json:"id"`
+	EventID 	string 		`json:"event_id"`
+	UserID 		string 		`json:"user_id"`
+	SeatIDs 	[]string 	`json:"seat_ids"`
+	ExpiresAt 	time.Time 	`json:"expires_at"`
+}
+
+type HoldSeatsInput struct {
+	SeatIDs 	[]string 	`json:"seat_ids" validate:"required,min=1,max=20,dive,required"`
+}
+
+type ConfirmSeatHoldInput struct {
+	PromoCode 	string 					`json:"promo_code"`
+	Answers 	map[string]interface{} 	`json:"answers"`
+}
@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+const (
+	SlugResourceEvent 		= "event"
+	SlugResourceCategory 	= "category"
+	SlugResourceUser 		= "user"
+	SlugResourceOrganization = "organization"
+)
+
+// SlugRedirect keeps an old slug pointing at the row that used to have it, so
+// links shared before a rename keep working.
+type SlugRedirect struct {
+	ResourceType 	string 		`gorm:"type:varchar(20);primary_key" json:"resource_type"`
+	OldSlug 		string 		`gorm:"type:varchar(255);primary_key" json:"old_slug"`
+	TargetID 		string 		`gorm:"type:uuid;not null;index" json:"target_id"`
+	CreatedAt 		time.Time 	`gorm:"not null" json:"created_at"`
+}
+
+// OrganizerProfile is the public view of a user who organizes events.
+type OrganizerProfile struct {
+	ID 				string 	`json:"id"`
+	Slug 			string 	`json:"slug"`
+	FullName 		string 	`json:"full_name"`
+	Organization 	string 	`json:"organization"`
+	Bio 			string 	`json:"bio"`
+	ProfileImage 	string 	`json:"profile_image"`
+}
@@ -0,0 +1,74 @@
+package model
+
+import "time"
+
+const (
+	TransferStatusPending 	= "pending"
+	TransferStatusAccepted 	= "accepted"
+	TransferStatusDeclined 	= "declined"
+	TransferStatusCancelled = "cancelled"
+)
+
+const (
+	TransferActionInitiated = "initiated"
+	TransferActionAccepted 	= "accepted"
+	TransferActionDeclined 	= "declined"
+	TransferActionCancelled = "cancelled"
+)
+
+// TransferPolicy decides whether an event's tickets may change hands. Events
+// without a stored policy allow unlimited transfers; MaxTransfers of zero means no
+// cap per ticket.
+type TransferPolicy struct {
+	EventID 		string 		`gorm:"type:uuid;primary_key" json:"event_id"`
+	Enabled 		bool 		`gorm:"not null" json:"enabled"`
+	MaxTransfers 	int 		`gorm:"not null" json:"max_transfers_per_ticket"`
+	UpdatedAt 		time.Time 	`json:"updated_at"`
+}
+
+// TicketTransfer is an offer to hand a registration over to whoever owns ToEmail.
+// A ticket has at most one pending transfer at a time.
+type TicketTransfer struct {
+	ID 				string 		`gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	EventID 		string 		`gorm:"type:uuid;not null;index" json:"event_id"`
+	RegistrationID 	string 		`gorm:"type:uuid;not null;index" json:"registration_id"`
+	FromUserID 		string 		`gorm:"type:uuid;not null;index" json:"from_user_id"`
+	ToEmail 		string 		`gorm:"type:varchar(255);not null;index" json:"to_email"`
+	ToUserID 		*string 	`gorm:"type:uuid" json:"to_user_id"`
+	Status 			string 		`gorm:"type:varchar(20);not null;index" json:"status"`
+	RespondedAt 	*time.Time 	`json:"responded_at"`
+	Event 			*Event 		`gorm:"foreignKey:EventID" json:"event,omitempty"`
+	Audit 			[]TicketTransferAudit `gorm:"foreignKey:TransferID" json:"audit,omitempty"`
+	CreatedAt 		time.Time 	`gorm:"not null" json:"created_at"`
+	UpdatedAt 		time.Time 	`gorm:"not null" json:"updated_at"`
+}
+
+// TicketTransferAudit is an append-only record of something that happened to a
+// transfer.
+type TicketTransferAudit struct {
+	ID 				string 		`gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	TransferID 		string 		`gorm:"type:uuid;not null;index" json:"transfer_id"`
+	EventID 		string 		`gorm:"type:uuid;not null;index" json:"event_id"`
+	RegistrationID 	string 		`gorm:"type:uuid;not null" json:"registration_id"`
+	Action 			string 		`gorm:"type:varchar(20);not null" json:"action"`
+	ActorID 		string 		`gorm:"type:uuid;not null" json:"actor_id"`
+	CreatedAt 		time.Time 	`gorm:"not null" json:"created_at"`
+}
+
+type TransferPolicyInput struct {
+	Enabled 		*bool 	`json:"enabled" validate:"required"`
+	MaxTransfers 	int 	`json:"max_transfers_per_ticket" validate:"min=0"`
+}
+
+type InitiateTransferInput struct {
+	Email 	string 	`json:"email" validate:"required,email"`
+}
+
+// Ticket is an attendee's registration with the code to put in its QR code. A
+// group booking has no code of its own; each of its attendees has one instead.
+// Attendees named in someone else's booking get only their own ticket.
+type Ticket struct {
+	Registration 	*Registration 		`json:"registration,omitempty"`
+	Code 			string 				`json:"code,omitempty"`
+	Attendees 		[]*AttendeeTicket 	`json:"attendees,omitempty"`
+}
@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+// EventTranslation holds the title and description of an event in a locale other
+// than the default one, which stays on the event itself.
+type EventTranslation struct {
+	EventID 		string 		`gorm:"type:uuid;primary_key" json:"event_id"`
+	Locale 			string 		`gorm:"type:varchar(35);primary_key" json:"locale"`
+	Title 			string 		`gorm:"type:varchar(255);not null" json:"title"`
+	Description 	string 		`gorm:"type:text" json:"description"`
+	CreatedAt 		time.Time 	`gorm:"not null" json:"created_at"`
+	UpdatedAt 		time.Time 	`gorm:"not null" json:"updated_at"`
+}
+
+// CategoryTranslation holds the name and description of a category in a locale
+// other than the default one.
+type CategoryTranslation struct {
+	CategoryID 		string 		`gorm:"type:uuid;primary_key" json:"category_id"`
+	Locale 			string 		`gorm:"type:varchar(35);primary_key" json:"locale"`
+	Name 			string 		`gorm:"type:varchar(100);not null" json:"name"`
+	Description 	string 		`gorm:"type:text" json:"description"`
+	CreatedAt 		time.Time 	`gorm:"not null" json:"created_at"`
+	UpdatedAt 		time.Time 	`gorm:"not null" json:"updated_at"`
+}
+
+type EventTranslationInput struct {
+	Title 			string 		`json:"title" validate:"required,max=255"`
+	Description 	string 		`json:"description"`
+}
+
+type CategoryTranslationInput struct {
+	Name 			string 		`json:"name" validate:"required,max=100"`
+	Description 	string 		`json:"description"`
+}
@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+const (
+	TrendingWindowDay 	= "day"
+	TrendingWindowWeek 	= "week"
+	TrendingWindowMonth = "month"
+)
+
+// Trending signals and how much each one adds to an event's score.
+const (
+	TrendingSignalView 			= "view"
+	TrendingSignalBookmark 		= "bookmark"
+	TrendingSignalRegistration 	= "registration"
+)
+
+// TrendingHalfLife is how long it takes for a signal to lose half its weight in
+// each window.
+var TrendingHalfLife = map[string]time.Duration{
+	TrendingWindowDay: 		6 * time.Hour,
+	TrendingWindowWeek: 	48 * time.Hour,
+	TrendingWindowMonth: 	7 * 24 * time.Hour,
+}
+
+var TrendingWeight = map[string]float64{
+	TrendingSignalView: 		1,
+	TrendingSignalBookmark: 	3,
+	TrendingSignalRegistration: 5,
+}
+
+type TrendingInput struct {
+	CategoryID 	string 	`json:"category_id,omitempty"`
+	Window 		string 	`json:"window" validate:"oneof=day week month"`
+	Limit 		int 	`json:"limit" validate:"min=1,max=50"`
+}
+
+type TrendingEvent struct {
+	Event 	*Event 	`json:"event"`
+	Score 	float64 `json:"score"`
+}
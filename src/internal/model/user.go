@@ -7,6 +7,7 @@ type User struct {
 	Email 			string 		`gorm:"type:varchar(255);unique;not null" json:"email"`
 	Password 		string 		`gorm:"type:varchar(255);not null" json:"-"`
 	FullName 		string 		`gorm:"type:varchar(255);not null" json:"full_name"`
+	Slug 			string 		`gorm:"type:varchar(255);uniqueIndex" json:"slug"`
 	Role 			string 		`gorm:"type:varchar(20);not null;default:'user'" json:"role"`
 	ProfileImage 	string 		`gorm:"type:text" json:"profile_image"`
 	PhoneNumber 	string 		`gorm:"type:varchar(20)" json:"phone_number"`
@@ -14,7 +15,10 @@ type User struct {
 	Bio 			string 		`gorm:"type:text" json:"bio"` 
 	CreatedAt 		time.Time 	`gorm:"not null" json:"created_at"`
 	UpdatedAt 		time.Time 	`gorm:"not null" json:"updated_at"`
+	Version 		int 		`gorm:"not null;default:1" json:"version"`
 	LastLoginAt		*time.Time 	`json:"last_login_at"`
+	EmailVerifiedAt	*time.Time 	`json:"email_verified_at"`
+	TokenGeneration	int 		`gorm:"not null;default:0" json:"-"`
 }
 
 
@@ -27,6 +31,14 @@ type UpdateProfileInput struct {
     Bio         string `json:"bio"`
 }
 
+// ProfileSnapshot is the document that profile merge patches are applied to.
+type ProfileSnapshot struct {
+    FullName     string `json:"full_name" validate:"required"`
+    PhoneNumber  string `json:"phone_number" validate:"max=20"`
+    Organization string `json:"organization"`
+    Bio          string `json:"bio"`
+}
+
 type ChangePasswordInput struct {
     CurrentPassword string `json:"current_password" validate:"required"`
     NewPassword     string `json:"new_password" validate:"required,min=6"`
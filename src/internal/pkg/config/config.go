@@ -5,8 +5,16 @@ import (
 )
 
 type ServerConfig struct {
-    Port    string `mapstructure:"server_port"`
-    Timeout int    `mapstructure:"server_timeout"`
+    Port            string      `mapstructure:"server_port"`
+    Timeout         int         `mapstructure:"server_timeout"`
+    Environment     string      `mapstructure:"environment"`
+    TrustedProxies  []string    `mapstructure:"trusted_proxies"`
+}
+
+// IsDevelopment reports whether the server runs in development, which unlocks
+// stand-ins such as the fake payment provider. Anything else counts as production.
+func (c *ServerConfig) IsDevelopment() bool {
+    return c.Environment == "development"
 }
 
 type DatabaseConfig struct {
@@ -19,8 +27,13 @@ type DatabaseConfig struct {
 }
 
 type AuthConfig struct {
-    JWTSecret      string `mapstructure:"jwt_secret"`
-    TokenExpiryHrs int    `mapstructure:"token_expiry_hrs"`
+    JWTSecret              string `mapstructure:"jwt_secret"`
+    AccessTokenMinutes     int    `mapstructure:"access_token_minutes"`
+    RefreshTokenDays       int    `mapstructure:"refresh_token_days"`
+    EmailVerificationHours int    `mapstructure:"email_verification_hours"`
+    RequireVerifiedEmail   bool   `mapstructure:"require_verified_email"`
+    PasswordResetMinutes   int    `mapstructure:"password_reset_minutes"`
+    FrontendURL            string `mapstructure:"frontend_url"`
 }
 
 type RedisConfig struct {
@@ -31,9 +44,11 @@ type RedisConfig struct {
 }
 
 type RateLimitConfig struct {
-    Enabled         bool    `mapstructure:"enabled"`
-    RequestLimit    int     `mapstructure:"request_limit"`
-    WindowSeconds   int     `mapstructure:"window_seconds"`
+    Enabled             bool    `mapstructure:"enabled"`
+    RequestLimit        int     `mapstructure:"request_limit"`
+    WindowSeconds       int     `mapstructure:"window_seconds"`
+    EmailRequestLimit   int     `mapstructure:"email_request_limit"`
+    EmailWindowSeconds  int     `mapstructure:"email_window_seconds"`
 }
 
 type CloudinaryConfig struct {
@@ -42,6 +57,65 @@ type CloudinaryConfig struct {
     ApiSecret   string          `mapstructure:"api_secret"`
 }
 
+type TrashConfig struct {
+    RetentionDays           int     `mapstructure:"retention_days"`
+    PurgeIntervalMinutes    int     `mapstructure:"purge_interval_minutes"`
+}
+
+type PaymentConfig struct {
+    Provider                string  `mapstructure:"provider"`
+    WebhookSecret           string  `mapstructure:"webhook_secret"`
+    Currency                string  `mapstructure:"currency"`
+    ReservationMinutes      int     `mapstructure:"reservation_minutes"`
+    ExpiryIntervalMinutes   int     `mapstructure:"expiry_interval_minutes"`
+}
+
+type SeatingConfig struct {
+    HoldMinutes             int     `mapstructure:"hold_minutes"`
+}
+
+type TicketConfig struct {
+    SigningSecret           string  `mapstructure:"signing_secret"`
+    MaxPerUser              int     `mapstructure:"max_per_user"`
+}
+
+type InvoiceConfig struct {
+    TaxRatePercent          float64 `mapstructure:"tax_rate_percent"`
+    TaxLabel                string  `mapstructure:"tax_label"`
+}
+
+type AnalyticsConfig struct {
+    RollupIntervalMinutes   int     `mapstructure:"rollup_interval_minutes"`
+}
+
+type TrendingConfig struct {
+    DecayIntervalMinutes    int     `mapstructure:"decay_interval_minutes"`
+}
+
+type RecommendationConfig struct {
+    IntervalMinutes         int     `mapstructure:"interval_minutes"`
+}
+
+type I18nConfig struct {
+    DefaultLocale           string      `mapstructure:"default_locale"`
+    Locales                 []string    `mapstructure:"locales"`
+}
+
+type MailConfig struct {
+    Provider                string      `mapstructure:"provider"`
+    From                    string      `mapstructure:"from"`
+    SMTPHost                string      `mapstructure:"smtp_host"`
+    SMTPPort                int         `mapstructure:"smtp_port"`
+    SMTPUsername            string      `mapstructure:"smtp_username"`
+    SMTPPassword            string      `mapstructure:"smtp_password"`
+    FilePath                string      `mapstructure:"file_path"`
+}
+
+type TenantConfig struct {
+    BaseDomain              string      `mapstructure:"base_domain"`
+    DefaultOrganization     string      `mapstructure:"default_organization"`
+}
+
 type Config struct {
     Server              ServerConfig        `mapstructure:"server"`
     Database            DatabaseConfig      `mapstructure:"database"`
@@ -49,6 +123,17 @@ type Config struct {
     Redis               RedisConfig         `mapstructure:"redis"`
     RateLimit           RateLimitConfig     `mapstructure:"rate_limit"`
     CloudinaryConfig    CloudinaryConfig    `mapstructure:"cloudinary"`
+    Trash               TrashConfig         `mapstructure:"trash"`
+    Payment             PaymentConfig       `mapstructure:"payment"`
+    Seating             SeatingConfig       `mapstructure:"seating"`
+    Ticket              TicketConfig        `mapstructure:"ticket"`
+    Invoice             InvoiceConfig       `mapstructure:"invoice"`
+    Analytics           AnalyticsConfig     `mapstructure:"analytics"`
+    Trending            TrendingConfig      `mapstructure:"trending"`
+    Recommendation      RecommendationConfig `mapstructure:"recommendation"`
+    I18n                I18nConfig          `mapstructure:"i18n"`
+    Tenant              TenantConfig        `mapstructure:"tenant"`
+    Mail                MailConfig          `mapstructure:"mail"`
 
 }
 
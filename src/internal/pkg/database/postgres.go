@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/hafiztri123/src/internal/pkg/config"
+	"github.com/hafiztri123/src/internal/pkg/tenant"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -17,6 +18,9 @@ func NewPostgresDB(cfg *config.DatabaseConfig) (*gorm.DB, error) {
 	logger := setLogger()
 	db := openConnection(dataSource, logger)
 	setConnectionPool(db)
+	if err := db.Use(tenant.Plugin{}); err != nil {
+		return nil, err
+	}
 	return db, nil
 }
 
@@ -180,3 +180,35 @@ func NewEntityTooLargeError(message string) *EntityTooLargeError {
         Message: message,
     }
 }
+
+type ConflictError struct {
+    Code    int
+    Message string
+}
+
+func (e *ConflictError) Error() string {
+    return fmt.Sprintf("[%d] %s", e.Code, e.Message)
+}
+
+func NewConflictError(message string) *ConflictError {
+    return &ConflictError{
+        Code: 409,
+        Message: message,
+    }
+}
+
+type PreconditionFailedError struct {
+    Code    int
+    Message string
+}
+
+func (e *PreconditionFailedError) Error() string {
+    return fmt.Sprintf("[%d] %s", e.Code, e.Message)
+}
+
+func NewPreconditionFailedError(message string) *PreconditionFailedError {
+    return &PreconditionFailedError{
+        Code: 412,
+        Message: message,
+    }
+}
@@ -0,0 +1,103 @@
+// Package i18n negotiates the language of responses and maps languages to their
+// Postgres text search configurations.
+package i18n
+
+import (
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// searchConfigs holds the text search configuration that stems each language.
+// Languages without a stemmer are indexed with the "simple" configuration.
+var searchConfigs = map[string]string{
+	"en": "english",
+	"id": "indonesian",
+}
+
+// SearchConfig returns the Postgres text search configuration for locale.
+func SearchConfig(locale string) string {
+	if config, ok := searchConfigs[locale]; ok {
+		return config
+	}
+	return "simple"
+}
+
+// Catalog lists the locales content can be written in. Base content is always in
+// the default locale; the other locales are served from translations.
+type Catalog struct {
+	defaultLocale string
+	locales       []string
+	matcher       language.Matcher
+}
+
+// NewCatalog builds a catalog from BCP 47 tags. Invalid tags are skipped and the
+// default locale is always supported.
+func NewCatalog(defaultLocale string, locales []string) *Catalog {
+	base, err := language.Parse(defaultLocale)
+	if err != nil {
+		base = language.English
+	}
+
+	tags := []language.Tag{base}
+	names := []string{base.String()}
+	seen := map[string]bool{base.String(): true}
+
+	for _, locale := range locales {
+		tag, err := language.Parse(strings.TrimSpace(locale))
+		if err != nil || seen[tag.String()] {
+			continue
+		}
+		seen[tag.String()] = true
+		tags = append(tags, tag)
+		names = append(names, tag.String())
+	}
+
+	return &Catalog{
+		defaultLocale: names[0],
+		locales:       names,
+		matcher:       language.NewMatcher(tags),
+	}
+}
+
+// Default returns the locale of base content.
+func (c *Catalog) Default() string {
+	return c.defaultLocale
+}
+
+// Translations returns the supported locales other than the default one.
+func (c *Catalog) Translations() []string {
+	return c.locales[1:]
+}
+
+// Lookup canonicalizes locale and reports whether it is supported.
+func (c *Catalog) Lookup(locale string) (string, bool) {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return "", false
+	}
+
+	for _, supported := range c.locales {
+		if supported == tag.String() {
+			return supported, true
+		}
+	}
+	return "", false
+}
+
+// Negotiate picks the supported locale closest to the lang parameter, or to the
+// Accept-Language header when lang is empty or invalid, falling back to the default.
+func (c *Catalog) Negotiate(lang, acceptLanguage string) string {
+	var preferred []language.Tag
+	if tag, err := language.Parse(lang); lang != "" && err == nil {
+		preferred = []language.Tag{tag}
+	} else if tags, _, err := language.ParseAcceptLanguage(acceptLanguage); err == nil {
+		preferred = tags
+	}
+
+	_, index, confidence := c.matcher.Match(preferred...)
+	if confidence == language.No {
+		return c.defaultLocale
+	}
+	return c.locales[index]
+}
@@ -0,0 +1,66 @@
+package mailer
+
+import (
+	"context"
+	"os"
+	"sync"
+
+	"github.com/hafiztri123/src/internal/pkg/logger"
+)
+
+// FileMailer appends every message to a file instead of delivering it, for
+// development and tests.
+type FileMailer struct {
+	path string
+	from string
+	mu   sync.Mutex
+}
+
+func NewFileMailer(path string, from string) *FileMailer {
+	return &FileMailer{
+		path: path,
+		from: from,
+	}
+}
+
+func (m *FileMailer) Send(ctx context.Context, msg Message) error {
+	body, err := format(m.from, msg)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	file, err := os.OpenFile(m.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(body, "\r\n"...)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// LogMailer writes every message to the application log instead of delivering it.
+type LogMailer struct {
+	log *logger.Logger
+}
+
+func NewLogMailer(log *logger.Logger) *LogMailer {
+	return &LogMailer{
+		log: log,
+	}
+}
+
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	m.log.Info(ctx, "Email not delivered, written to log", map[string]interface{}{
+		"to":      msg.To,
+		"subject": msg.Subject,
+		"body":    msg.Body,
+	})
+	return nil
+}
@@ -0,0 +1,48 @@
+package mergepatch
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ContentType is the media type of an RFC 7396 merge patch document.
+const ContentType = "application/merge-patch+json"
+
+// Apply applies an RFC 7396 JSON merge patch to document and returns the merged
+// document. Members set to null in the patch are removed, members missing from the
+// patch are kept, and nested objects are merged recursively.
+func Apply(document, patch []byte) ([]byte, error) {
+	var target interface{}
+	if err := json.Unmarshal(document, &target); err != nil {
+		return nil, fmt.Errorf("[FAIL] invalid target document: %w", err)
+	}
+
+	var changes interface{}
+	if err := json.Unmarshal(patch, &changes); err != nil {
+		return nil, fmt.Errorf("[FAIL] invalid merge patch: %w", err)
+	}
+
+	return json.Marshal(merge(target, changes))
+}
+
+func merge(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+		targetObject[key] = merge(targetObject[key], value)
+	}
+
+	return targetObject
+}
@@ -0,0 +1,82 @@
+package mergepatch
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	// The cases are the examples from RFC 7396, appendix A, plus the shapes event
+	// and category patches use.
+	tests := []struct {
+		name     string
+		document string
+		patch    string
+		want     string
+	}{
+		{"replace member", `{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{"add member", `{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{"null removes member", `{"a":"b"}`, `{"a":null}`, `{}`},
+		{"null removes only that member", `{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{"array replaces value", `{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{"value replaces array", `{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{"nested objects merge", `{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{"arrays are replaced whole", `{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{"array document is replaced", `["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{"object replaces array document", `{"a":"b"}`, `["c"]`, `["c"]`},
+		{"null patch replaces document", `{"a":"foo"}`, `null`, `null`},
+		{"string patch replaces document", `{"a":"foo"}`, `"bar"`, `"bar"`},
+		{"null inside new member is dropped", `{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{"patch creates nested object", `[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{"deep null is dropped", `{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+		{"empty patch keeps document", `{"title":"Launch","venue":"Main Hall"}`, `{}`, `{"title":"Launch","venue":"Main Hall"}`},
+		{"partial event update", `{"title":"Launch","venue":"Main Hall","description":"Old"}`, `{"venue":"Annex","description":null}`, `{"title":"Launch","venue":"Annex"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Apply([]byte(tt.document), []byte(tt.patch))
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			assertJSONEqual(t, got, tt.want)
+		})
+	}
+}
+
+func TestApplyInvalidInput(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		patch    string
+	}{
+		{"invalid document", `{"a":`, `{}`},
+		{"invalid patch", `{}`, `{"a":`},
+		{"empty patch", `{}`, ``},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Apply([]byte(tt.document), []byte(tt.patch)); err == nil {
+				t.Fatal("Apply() error = nil, want an error")
+			}
+		})
+	}
+}
+
+func assertJSONEqual(t *testing.T, got []byte, want string) {
+	t.Helper()
+
+	var gotValue, wantValue interface{}
+	if err := json.Unmarshal(got, &gotValue); err != nil {
+		t.Fatalf("result is not JSON: %v", err)
+	}
+	if err := json.Unmarshal([]byte(want), &wantValue); err != nil {
+		t.Fatalf("expected value is not JSON: %v", err)
+	}
+
+	if !reflect.DeepEqual(gotValue, wantValue) {
+		t.Errorf("Apply() = %s, want %s", got, want)
+	}
+}
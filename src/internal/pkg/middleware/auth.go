@@ -9,13 +9,21 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// TokenRevocationChecker reports whether an access token was logged out before
+// it expired.
+type TokenRevocationChecker interface {
+	IsTokenRevoked(ctx context.Context, jti string, userID string, generation int) (bool, error)
+}
+
 type AuthMiddleware struct {
-	jwtSecret string
+	jwtSecret   string
+	revocations TokenRevocationChecker
 }
 
-func NewAuthMiddleware(jwtSecret string) *AuthMiddleware {
+func NewAuthMiddleware(jwtSecret string, revocations TokenRevocationChecker) *AuthMiddleware {
 	return &AuthMiddleware{
-		jwtSecret: jwtSecret,
+		jwtSecret:   jwtSecret,
+		revocations: revocations,
 	}
 }
 
@@ -55,6 +63,25 @@ func (m *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
             return
         }
 
+        jti, _ := claims["jti"].(string)
+        userID, _ := claims["user_id"].(string)
+        generation, _ := claims["gen"].(float64)
+        if jti == "" || userID == "" {
+            http.Error(w, "[FAIL] Invalid token", http.StatusUnauthorized)
+            return
+        }
+
+        revoked, err := m.revocations.IsTokenRevoked(r.Context(), jti, userID, int(generation))
+        if err != nil {
+            http.Error(w, "[FAIL] Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        if revoked {
+            http.Error(w, "[FAIL] Token has been revoked", http.StatusUnauthorized)
+            return
+        }
+
         ctx := context.WithValue(r.Context(), "user", claims)
         next.ServeHTTP(w, r.WithContext(ctx))
     })
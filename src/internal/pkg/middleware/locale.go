@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/hafiztri123/src/internal/pkg/i18n"
+)
+
+type Locale struct {
+	catalog *i18n.Catalog
+}
+
+func NewLocale(catalog *i18n.Catalog) *Locale {
+	return &Locale{
+		catalog: catalog,
+	}
+}
+
+// Negotiate stores the locale picked from the lang parameter or the Accept-Language
+// header in the request context under "locale".
+func (l *Locale) Negotiate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		locale := l.catalog.Negotiate(r.URL.Query().Get("lang"), r.Header.Get("Accept-Language"))
+
+		w.Header().Add("Vary", "Accept-Language")
+
+		ctx := context.WithValue(r.Context(), "locale", locale)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -1,10 +1,16 @@
 package middleware
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"math"
+	"net"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -15,13 +21,17 @@ type RateLimiter struct {
 	redisClient *redis.Client
 	requests int
 	duration time.Duration
+	emailRequests int
+	emailDuration time.Duration
 }
 
-func NewRateLimiter(redisClient *redis.Client, requests int, duration time.Duration) *RateLimiter {
+func NewRateLimiter(redisClient *redis.Client, requests int, duration time.Duration, emailRequests int, emailDuration time.Duration) *RateLimiter {
 	return &RateLimiter{
 		redisClient: redisClient,
 		requests: requests,
 		duration: duration,
+		emailRequests: emailRequests,
+		emailDuration: emailDuration,
 	}
 }
 
@@ -29,7 +39,7 @@ func (rl *RateLimiter) RateLimit(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		identifier := rl.getClientIdentifier(r)
 
-		allowed, remaining, resetTime, err := rl.isAllowed(r.Context(), identifier)
+		allowed, remaining, resetTime, err := rl.isAllowed(r.Context(), identifier, rl.requests, rl.duration)
 		if err != nil {
 			http.Error(w, "Rate limit check failed", http.StatusInternalServerError)
 			return
@@ -40,7 +50,7 @@ func (rl *RateLimiter) RateLimit(next http.Handler) http.Handler {
 		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(resetTime.Unix(), 10))
 
 		if !allowed {
-			w.Header().Set("Retry-After", strconv.FormatInt(resetTime.Unix(), 10))
+			w.Header().Set("Retry-After", retryAfter(resetTime))
 			http.Error(w, "Rate Limit Exceeded", http.StatusTooManyRequests)
 			return 
 		}
@@ -49,6 +59,44 @@ func (rl *RateLimiter) RateLimit(next http.Handler) http.Handler {
 	})
 }
 
+// RateLimitEmail limits requests per email address in the JSON body, for routes
+// that send mail to it. It complements RateLimit, which limits per client, so that
+// one address cannot be flooded from many clients. Requests without an email pass
+// through and are left to the handler's validation.
+func (rl *RateLimiter) RateLimitEmail(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+		if err != nil {
+			http.Error(w, "Request is not valid", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		var input struct {
+			Email string `json:"email"`
+		}
+		if json.Unmarshal(body, &input) != nil || input.Email == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		identifier := fmt.Sprintf("email:%s", strings.ToLower(strings.TrimSpace(input.Email)))
+		allowed, _, resetTime, err := rl.isAllowed(r.Context(), identifier, rl.emailRequests, rl.emailDuration)
+		if err != nil {
+			http.Error(w, "Rate limit check failed", http.StatusInternalServerError)
+			return
+		}
+
+		if !allowed {
+			w.Header().Set("Retry-After", retryAfter(resetTime))
+			http.Error(w, "Rate Limit Exceeded", http.StatusTooManyRequests)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
 func (rl *RateLimiter) getClientIdentifier(r *http.Request) string {
 	if claims, ok := r.Context().Value("user").(jwt.MapClaims); ok {
 		if userID, ok := claims["user_id"].(string); ok {
@@ -56,16 +104,26 @@ func (rl *RateLimiter) getClientIdentifier(r *http.Request) string {
 		}
 	}
 
-	return fmt.Sprintf("ip:%s", r.RemoteAddr)
+	// RemoteAddr carries the client's port, which changes with every connection.
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	return fmt.Sprintf("ip:%s", host)
 }
 
-func (rl *RateLimiter) isAllowed(ctx context.Context, identifier string) (bool, int, time.Time, error) {
+// isAllowed counts a request against the identifier's window. The window starts
+// with the first request and is not extended by later ones, so a steady trickle of
+// requests cannot keep a client or an address locked out for good.
+func (rl *RateLimiter) isAllowed(ctx context.Context, identifier string, requests int, duration time.Duration) (bool, int, time.Time, error) {
 	key := fmt.Sprintf("rate_limit:%s", identifier)
 	now := time.Now()
 
-	pipe := rl.redisClient.Pipeline()
+	pipe := rl.redisClient.TxPipeline()
+	pipe.SetNX(ctx, key, 0, duration)
 	incrCmd := pipe.Incr(ctx, key)
-	pipe.Expire(ctx, key, rl.duration)
+	ttlCmd := pipe.TTL(ctx, key)
 
 	_, err := pipe.Exec(ctx)
 	if err != nil && err != redis.Nil {
@@ -77,17 +135,28 @@ func (rl *RateLimiter) isAllowed(ctx context.Context, identifier string) (bool,
 		return false, 0, now, err
 	}
 
-	remaining := rl.requests - int(count)
+	remaining := requests - int(count)
 	if remaining < 0 {
 		remaining = 0
 	}
 
-	ttl, err := rl.redisClient.TTL(ctx, key).Result()
+	ttl, err := ttlCmd.Result()
 	if err != nil {
 		return false, remaining, now, err
 	}
 
 	resetTime := now.Add(ttl)
 
-	return count <= int64(rl.requests), remaining, resetTime, nil
-}
\ No newline at end of file
+	return count <= int64(requests), remaining, resetTime, nil
+}
+
+// retryAfter returns the whole number of seconds until the window resets, as the
+// Retry-After header expects.
+func retryAfter(resetTime time.Time) string {
+	seconds := int64(math.Ceil(time.Until(resetTime).Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+
+	return strconv.FormatInt(seconds, 10)
+}
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// RealIP replaces the request's RemoteAddr with the client's address taken from the
+// forwarding headers, but only when the request comes through a trusted proxy.
+// Headers from anyone else are ignored, since clients can set them to anything.
+type RealIP struct {
+	trusted []*net.IPNet
+}
+
+// NewRealIP accepts the trusted proxies as IP addresses or CIDR ranges.
+func NewRealIP(trustedProxies []string) (*RealIP, error) {
+	var trusted []*net.IPNet
+	for _, proxy := range trustedProxies {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", proxy)
+			}
+			bits := 128
+			if v4 := ip.To4(); v4 != nil {
+				ip, bits = v4, 32
+			}
+			trusted = append(trusted, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+		}
+		trusted = append(trusted, network)
+	}
+
+	return &RealIP{
+		trusted: trusted,
+	}, nil
+}
+
+func (m *RealIP) Resolve(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ip := m.clientIP(r); ip != "" {
+			r.RemoteAddr = ip
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// clientIP returns the client's address, or "" to keep RemoteAddr. Each proxy
+// appends the address it received the request from to X-Forwarded-For, so the
+// list is read from the right and the first address that is not a trusted proxy
+// is the client. Entries left of it were written by the client and are ignored.
+func (m *RealIP) clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	if !m.isTrusted(net.ParseIP(host)) {
+		return ""
+	}
+
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		hops := strings.Split(forwarded, ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			ip := net.ParseIP(strings.TrimSpace(hops[i]))
+			if ip == nil {
+				return ""
+			}
+			if !m.isTrusted(ip) {
+				return ip.String()
+			}
+		}
+		return ""
+	}
+
+	if ip := net.ParseIP(strings.TrimSpace(r.Header.Get("X-Real-IP"))); ip != nil {
+		return ip.String()
+	}
+
+	return ""
+}
+
+func (m *RealIP) isTrusted(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+
+	for _, network := range m.trusted {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"github.com/hafiztri123/src/internal/pkg/tenant"
+)
+
+// OrganizationResolver finds the ID of an organization by ID or slug and checks
+// who may act in it.
+type OrganizationResolver interface {
+	ResolveOrganization(ctx context.Context, idOrSlug string) (string, error)
+	AuthorizeOrganization(ctx context.Context, organizationID string, userID string) error
+}
+
+type Tenant struct {
+	resolver    OrganizationResolver
+	baseDomain  string
+	defaultSlug string
+}
+
+func NewTenant(resolver OrganizationResolver, baseDomain string, defaultSlug string) *Tenant {
+	return &Tenant{
+		resolver:    resolver,
+		baseDomain:  strings.ToLower(strings.TrimPrefix(baseDomain, ".")),
+		defaultSlug: defaultSlug,
+	}
+}
+
+// Resolve scopes the request to the organization named by the X-Organization header,
+// or else by the subdomain of the base domain the request was sent to, or else to the
+// default organization. An unknown organization is answered with 404 so the request
+// never reaches a handler unscoped.
+func (t *Tenant) Resolve(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		idOrSlug := strings.TrimSpace(r.Header.Get("X-Organization"))
+		if idOrSlug == "" {
+			idOrSlug = t.subdomain(r.Host)
+		}
+		if idOrSlug == "" {
+			idOrSlug = t.defaultSlug
+		}
+
+		organizationID, err := t.resolver.ResolveOrganization(r.Context(), idOrSlug)
+		if err != nil {
+			http.Error(w, "Organization not found", http.StatusNotFound)
+			return
+		}
+
+		w.Header().Add("Vary", "X-Organization")
+
+		ctx := tenant.WithOrganization(r.Context(), organizationID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// Authorize checks that the signed-in user may act in the organization the request
+// was resolved to: anyone in an open organization, only its members otherwise.
+// Since the organization is named by the client, it has to run after Authenticate
+// on every authenticated route that touches the organization's data.
+func (t *Tenant) Authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		organizationID, ok := tenant.FromContext(r.Context())
+		if !ok {
+			http.Error(w, "Organization not found", http.StatusNotFound)
+			return
+		}
+
+		claims, ok := r.Context().Value("user").(jwt.MapClaims)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		userID, _ := claims["user_id"].(string)
+		err := t.resolver.AuthorizeOrganization(r.Context(), organizationID, userID)
+
+		var forbiddenErr *errs.ForbiddenError
+		var notFoundErr *errs.NotFoundError
+		switch {
+		case err == nil:
+			next.ServeHTTP(w, r)
+		case errors.As(err, &forbiddenErr):
+			http.Error(w, forbiddenErr.Message, http.StatusForbidden)
+		case errors.As(err, &notFoundErr):
+			http.Error(w, "Organization not found", http.StatusNotFound)
+		default:
+			http.Error(w, "Organization check failed", http.StatusInternalServerError)
+		}
+	})
+}
+
+// subdomain returns the label in front of the base domain, e.g. "acme" for
+// acme.events.example.com under events.example.com.
+func (t *Tenant) subdomain(host string) string {
+	if t.baseDomain == "" {
+		return ""
+	}
+
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(host)
+
+	label, ok := strings.CutSuffix(host, "."+t.baseDomain)
+	if !ok || label == "" || strings.Contains(label, ".") {
+		return ""
+	}
+
+	return label
+}
@@ -0,0 +1,138 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// FakeSignatureHeader carries the HMAC-SHA256 of a fake webhook payload.
+const FakeSignatureHeader = "X-Fake-Signature"
+
+// FakeProvider is an in-process Provider for development and offline testing. It
+// keeps intents in memory and signs its webhooks like a real gateway would.
+type FakeProvider struct {
+	secret  []byte
+	mu      sync.Mutex
+	intents map[string]*fakeIntent
+}
+
+type fakeIntent struct {
+	*Intent
+	amount   int64
+	refunded int64
+}
+
+func NewFakeProvider(secret string) *FakeProvider {
+	return &FakeProvider{
+		secret:  []byte(secret),
+		intents: map[string]*fakeIntent{},
+	}
+}
+
+var _ Provider = (*FakeProvider)(nil)
+
+func (p *FakeProvider) Name() string {
+	return "fake"
+}
+
+func (p *FakeProvider) CreateIntent(ctx context.Context, req IntentRequest) (*Intent, error) {
+	if req.Amount <= 0 {
+		return nil, fmt.Errorf("[FAIL] intent amount must be positive")
+	}
+
+	intent := &Intent{
+		ID:           "fake_pi_" + uuid.NewString(),
+		ClientSecret: "fake_secret_" + uuid.NewString(),
+		Status:       IntentRequiresPayment,
+	}
+
+	p.mu.Lock()
+	p.intents[intent.ID] = &fakeIntent{Intent: intent, amount: req.Amount}
+	p.mu.Unlock()
+
+	return intent, nil
+}
+
+func (p *FakeProvider) ParseWebhook(payload []byte, header http.Header) (*WebhookEvent, error) {
+	expected := p.sign(payload)
+	if !hmac.Equal([]byte(expected), []byte(header.Get(FakeSignatureHeader))) {
+		return nil, fmt.Errorf("[FAIL] invalid webhook signature")
+	}
+
+	var event WebhookEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("[FAIL] invalid webhook payload: %w", err)
+	}
+
+	return &event, nil
+}
+
+func (p *FakeProvider) Refund(ctx context.Context, req RefundRequest) (*RefundResult, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	intent, ok := p.intents[req.IntentID]
+	if !ok {
+		return nil, fmt.Errorf("[FAIL] unknown intent %s", req.IntentID)
+	}
+
+	if intent.Status != IntentSucceeded {
+		return nil, fmt.Errorf("[FAIL] intent %s has not been paid", req.IntentID)
+	}
+
+	if req.Amount <= 0 || intent.refunded+req.Amount > intent.amount {
+		return nil, fmt.Errorf("[FAIL] refund of %d exceeds the refundable amount", req.Amount)
+	}
+
+	intent.refunded += req.Amount
+	return &RefundResult{
+		ID:     "fake_re_" + uuid.NewString(),
+		Status: RefundSucceeded,
+	}, nil
+}
+
+// Simulate settles an intent and returns the signed webhook the provider would send,
+// ready to be fed into the webhook endpoint.
+func (p *FakeProvider) Simulate(intentID string, succeed bool) ([]byte, http.Header, error) {
+	p.mu.Lock()
+	intent, ok := p.intents[intentID]
+	if ok {
+		intent.Status = IntentFailed
+		if succeed {
+			intent.Status = IntentSucceeded
+		}
+	}
+	p.mu.Unlock()
+
+	if !ok {
+		return nil, nil, fmt.Errorf("[FAIL] unknown intent %s", intentID)
+	}
+
+	eventType := EventPaymentFailed
+	if succeed {
+		eventType = EventPaymentSucceeded
+	}
+
+	payload, err := json.Marshal(WebhookEvent{Type: eventType, IntentID: intentID})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	header := http.Header{}
+	header.Set(FakeSignatureHeader, p.sign(payload))
+	return payload, header, nil
+}
+
+func (p *FakeProvider) sign(payload []byte) string {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,60 @@
+package payment
+
+import (
+	"context"
+	"net/http"
+)
+
+const (
+	IntentRequiresPayment 	= "requires_payment"
+	IntentSucceeded 		= "succeeded"
+	IntentFailed 			= "failed"
+)
+
+const RefundSucceeded = "succeeded"
+
+const (
+	EventPaymentSucceeded 	= "payment.succeeded"
+	EventPaymentFailed 		= "payment.failed"
+)
+
+type IntentRequest struct {
+	OrderID 	string
+	Amount 		int64
+	Currency 	string
+	Description string
+}
+
+// Intent is a provider-side request to collect a payment. ClientSecret is handed to
+// the client to complete the payment with the provider.
+type Intent struct {
+	ID 				string
+	ClientSecret 	string
+	Status 			string
+}
+
+type RefundRequest struct {
+	IntentID 	string
+	Amount 		int64
+	Currency 	string
+	Reason 		string
+}
+
+type RefundResult struct {
+	ID 		string
+	Status 	string
+}
+
+// WebhookEvent is a verified notification from a provider about an intent.
+type WebhookEvent struct {
+	Type 		string 	`json:"type"`
+	IntentID 	string 	`json:"intent_id"`
+}
+
+// Provider abstracts a payment gateway so checkout does not depend on a vendor SDK.
+type Provider interface {
+	Name() string
+	CreateIntent(ctx context.Context, req IntentRequest) (*Intent, error)
+	ParseWebhook(payload []byte, header http.Header) (*WebhookEvent, error)
+	Refund(ctx context.Context, req RefundRequest) (*RefundResult, error)
+}
@@ -0,0 +1,147 @@
+// Package pdf writes simple text-only PDF documents using the standard base-14
+// fonts, so no font files need to be embedded.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+type Font string
+
+const (
+	Helvetica     Font = "Helvetica"
+	HelveticaBold Font = "Helvetica-Bold"
+	Courier       Font = "Courier"
+	CourierBold   Font = "Courier-Bold"
+)
+
+var fontResources = map[Font]string{
+	Helvetica:     "F1",
+	HelveticaBold: "F2",
+	Courier:       "F3",
+	CourierBold:   "F4",
+}
+
+var fontOrder = []Font{Helvetica, HelveticaBold, Courier, CourierBold}
+
+const (
+	pageWidth  = 595.0 // A4 in points
+	pageHeight = 842.0
+	margin     = 50.0
+)
+
+// Document lays out lines of text top to bottom and starts a new page when the
+// current one is full.
+type Document struct {
+	pages []*bytes.Buffer
+	y     float64
+}
+
+func New() *Document {
+	d := &Document{}
+	d.newPage()
+	return d
+}
+
+// Line writes one line of text and moves the cursor below it.
+func (d *Document) Line(font Font, size float64, text string) {
+	leading := size * 1.4
+	if d.y-leading < margin {
+		d.newPage()
+	}
+	d.y -= leading
+
+	page := d.pages[len(d.pages)-1]
+	fmt.Fprintf(page, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n",
+		fontResources[font], size, margin, d.y, escape(text))
+}
+
+// Space moves the cursor down without writing anything.
+func (d *Document) Space(height float64) {
+	d.y -= height
+	if d.y < margin {
+		d.newPage()
+	}
+}
+
+// Rule draws a horizontal line across the page.
+func (d *Document) Rule() {
+	d.Space(6)
+	page := d.pages[len(d.pages)-1]
+	fmt.Fprintf(page, "%.2f %.2f m %.2f %.2f l 0.5 w S\n", margin, d.y, pageWidth-margin, d.y)
+	d.Space(4)
+}
+
+// Bytes serializes the document.
+func (d *Document) Bytes() []byte {
+	var out bytes.Buffer
+	var offsets []int
+
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n")
+
+	// Objects 1 and 2 are the catalog and page tree, followed by the fonts and then
+	// a page and content stream pair per page.
+	fontStart := 3
+	pageStart := fontStart + len(fontOrder)
+
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", pageStart+i*2)
+	}
+
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+
+	fonts := make([]string, len(fontOrder))
+	for i, font := range fontOrder {
+		object(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", font))
+		fonts[i] = fmt.Sprintf("/%s %d 0 R", fontResources[font], fontStart+i)
+	}
+
+	for i, page := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << %s >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, strings.Join(fonts, " "), pageStart+i*2+1))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return out.Bytes()
+}
+
+func (d *Document) newPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+	d.y = pageHeight - margin
+}
+
+// escape makes text safe for a PDF string literal. Characters outside Latin-1 have
+// no glyph in the standard fonts and are replaced.
+func escape(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\n' || r == '\r' || r == '\t':
+			b.WriteByte(' ')
+		case r < 32 || r > 255:
+			b.WriteByte('?')
+		default:
+			b.WriteByte(byte(r))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,91 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hafiztri123/src/internal/pkg/logger"
+)
+
+// Job is a unit of background work that runs on a fixed interval.
+type Job interface {
+	Name() string
+	Run(ctx context.Context) error
+}
+
+type entry struct {
+	job      Job
+	interval time.Duration
+}
+
+type Scheduler struct {
+	log     *logger.Logger
+	entries []entry
+	mu      sync.Mutex
+	wg      sync.WaitGroup
+}
+
+func New(log *logger.Logger) *Scheduler {
+	return &Scheduler{
+		log: log,
+	}
+}
+
+func (s *Scheduler) Add(job Job, interval time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, entry{job: job, interval: interval})
+}
+
+// Start runs every registered job in its own goroutine until ctx is cancelled.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	entries := make([]entry, len(s.entries))
+	copy(entries, s.entries)
+	s.mu.Unlock()
+
+	for _, e := range entries {
+		s.wg.Add(1)
+		go func(e entry) {
+			defer s.wg.Done()
+			s.loop(ctx, e)
+		}(e)
+	}
+}
+
+// Wait blocks until every job loop has returned.
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, e entry) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.run(ctx, e.job)
+		}
+	}
+}
+
+func (s *Scheduler) run(ctx context.Context, job Job) {
+	start := time.Now()
+	err := job.Run(ctx)
+
+	data := map[string]interface{}{
+		"job":         job.Name(),
+		"duration_ms": time.Since(start).Milliseconds(),
+	}
+
+	if err != nil {
+		s.log.Error(ctx, "Background job failed", err, data)
+		return
+	}
+
+	s.log.Info(ctx, "Background job completed", data)
+}
@@ -0,0 +1,59 @@
+// Package slug turns names into URL-safe identifiers.
+package slug
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxLength leaves room for a collision suffix within a varchar(255) column.
+const MaxLength = 80
+
+// letters that do not decompose into an ASCII letter and a combining mark.
+var letters = strings.NewReplacer("ß", "ss", "æ", "ae", "œ", "oe", "ø", "o", "đ", "d", "ł", "l", "þ", "th")
+
+// Make lowercases text, strips accents and joins the remaining letters and digits
+// with hyphens, e.g. "Café Meetup 2025!" becomes "cafe-meetup-2025". Text without
+// any ASCII letters or digits falls back to fallback.
+func Make(text string, fallback string) string {
+	var b strings.Builder
+	pendingHyphen := false
+
+	for _, r := range norm.NFKD.String(letters.Replace(strings.ToLower(text))) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			continue
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			if pendingHyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			pendingHyphen = false
+			b.WriteRune(r)
+		default:
+			pendingHyphen = true
+		}
+
+		if b.Len() >= MaxLength {
+			break
+		}
+	}
+
+	s := strings.Trim(b.String(), "-")
+	if len(s) > MaxLength {
+		s = strings.TrimRight(s[:MaxLength], "-")
+	}
+
+	if s == "" {
+		return fallback
+	}
+	return s
+}
+
+// IsID reports whether value is a UUID rather than a slug.
+func IsID(value string) bool {
+	_, err := uuid.Parse(value)
+	return err == nil
+}
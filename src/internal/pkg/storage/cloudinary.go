@@ -4,6 +4,7 @@ import (
 	"context"
 	"mime/multipart"
 	"strings"
+	"time"
 
 	"github.com/cloudinary/cloudinary-go/v2"
 	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
@@ -12,6 +13,8 @@ import (
 
 type StorageService interface {
 	UploadFile(ctx context.Context, file multipart.File, filename string) (string, error)
+	UploadPrivateFile(ctx context.Context, file multipart.File, filename string) (string, error)
+	PrivateFileURL(ctx context.Context, publicID string, expiresAt time.Time) (string, error)
 	DeleteFile(ctx context.Context, publicID string) error
 }
 
@@ -44,6 +47,33 @@ func (s *cloudinaryService) UploadFile(ctx context.Context, file multipart.File,
 }
 
 
+// UploadPrivateFile stores a file that can only be fetched through a signed URL
+// and returns its public ID. The filename should carry the file's extension.
+func (s *cloudinaryService) UploadPrivateFile(ctx context.Context, file multipart.File, filename string) (string, error) {
+	uploadResult, err := s.cld.Upload.Upload(ctx, file, uploader.UploadParams{
+		PublicID:     filename,
+		ResourceType: "raw",
+		Type:         "authenticated",
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	return uploadResult.PublicID, nil
+}
+
+// PrivateFileURL returns a signed URL for a file stored with UploadPrivateFile
+// that stops working at expiresAt.
+func (s *cloudinaryService) PrivateFileURL(ctx context.Context, publicID string, expiresAt time.Time) (string, error) {
+	return s.cld.Upload.PrivateDownloadURL(uploader.PrivateDownloadURLParams{
+		PublicID:     publicID,
+		DeliveryType: "authenticated",
+		ExpiresAt:    &expiresAt,
+		ResourceType: "raw",
+	})
+}
+
 func (s *cloudinaryService) DeleteFile(ctx context.Context, publicID string) error{
 	_, err := s.cld.Upload.Destroy(ctx, uploader.DestroyParams{
 		PublicID: publicID,
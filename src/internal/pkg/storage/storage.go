@@ -0,0 +1,20 @@
+package storage
+
+import (
+	"bytes"
+	"mime/multipart"
+)
+
+type bytesFile struct {
+	*bytes.Reader
+}
+
+func (f bytesFile) Close() error {
+	return nil
+}
+
+// NewBytesFile wraps generated content so it can be passed to UploadFile like an
+// uploaded form file.
+func NewBytesFile(data []byte) multipart.File {
+	return bytesFile{Reader: bytes.NewReader(data)}
+}
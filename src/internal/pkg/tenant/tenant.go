@@ -0,0 +1,168 @@
+// Package tenant carries the organization a request acts for and confines every
+// GORM query on tenant-owned tables to it.
+package tenant
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Column is the column that ties a row to its organization.
+const Column = "organization_id"
+
+// tables are the tenant-owned tables. Queries on them without an organization in
+// their context fail instead of reading across tenants.
+//
+// Only the roots of the data are listed. Everything hanging off an event (ticket
+// types, seats, orders, registrations, attendees, transfers, refunds, invoices,
+// forms, translations, revisions, analytics) carries the event ID instead of an
+// organization, and is scoped through it: every organizer-facing service loads the
+// event first, through this plugin, and an event of another organization is not
+// found. What is read without an event is keyed on the signed-in user, such as
+// their own orders, tickets and transfers, and is theirs whichever organization
+// it was made in. A new table belongs here if it can be read by anything other
+// than its event or its owner.
+var tables = map[string]bool{
+	"events":     true,
+	"categories": true,
+	"files":      true,
+}
+
+var (
+	ErrMissing     = errors.New("tenant: no organization in context")
+	ErrCrossTenant = errors.New("tenant: row belongs to another organization")
+)
+
+type contextKey int
+
+const (
+	organizationKey contextKey = iota
+	systemKey
+)
+
+// WithOrganization returns a context scoped to the organization.
+func WithOrganization(ctx context.Context, organizationID string) context.Context {
+	return context.WithValue(ctx, organizationKey, organizationID)
+}
+
+// FromContext returns the organization the context is scoped to.
+func FromContext(ctx context.Context) (string, bool) {
+	organizationID, ok := ctx.Value(organizationKey).(string)
+	return organizationID, ok && organizationID != ""
+}
+
+// WithSystem returns a context that may read and write every organization's rows.
+// It is meant for migrations, background jobs and provider callbacks, never for
+// code acting on behalf of a user.
+func WithSystem(ctx context.Context) context.Context {
+	return context.WithValue(ctx, systemKey, true)
+}
+
+// IsSystem reports whether the context was made by WithSystem.
+func IsSystem(ctx context.Context) bool {
+	system, _ := ctx.Value(systemKey).(bool)
+	return system
+}
+
+// Allows reports whether the context may see a row of the organization, e.g. one
+// read from a cache shared by all tenants.
+func Allows(ctx context.Context, organizationID string) bool {
+	if IsSystem(ctx) {
+		return true
+	}
+	current, ok := FromContext(ctx)
+	return ok && current == organizationID
+}
+
+// Plugin adds the organization of the statement context to every query, update
+// and delete on a tenant-owned table and stamps it on created rows. Raw SQL is
+// left alone and must filter by organization itself.
+type Plugin struct{}
+
+func (Plugin) Name() string {
+	return "tenant"
+}
+
+func (Plugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	if err := callbacks.Query().Before("gorm:query").Register("tenant:query", scope); err != nil {
+		return err
+	}
+	if err := callbacks.Row().Before("gorm:row").Register("tenant:row", scope); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:update").Register("tenant:update", scope); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().Before("gorm:delete").Register("tenant:delete", scope); err != nil {
+		return err
+	}
+	return callbacks.Create().Before("gorm:create").Register("tenant:create", stamp)
+}
+
+func scope(db *gorm.DB) {
+	if db.Error != nil || db.Statement.SQL.Len() > 0 || !tables[db.Statement.Table] {
+		return
+	}
+
+	ctx := db.Statement.Context
+	if IsSystem(ctx) {
+		return
+	}
+
+	organizationID, ok := FromContext(ctx)
+	if !ok {
+		db.AddError(ErrMissing)
+		return
+	}
+
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: db.Statement.Table, Name: Column}, Value: organizationID},
+	}})
+}
+
+func stamp(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil || !tables[db.Statement.Table] {
+		return
+	}
+
+	field := db.Statement.Schema.LookUpField(Column)
+	if field == nil {
+		return
+	}
+
+	ctx := db.Statement.Context
+	organizationID, ok := FromContext(ctx)
+	if !ok {
+		if !IsSystem(ctx) {
+			db.AddError(ErrMissing)
+		}
+		return
+	}
+
+	stampRow := func(row reflect.Value) {
+		value, zero := field.ValueOf(ctx, row)
+		if zero {
+			if err := field.Set(ctx, row, organizationID); err != nil {
+				db.AddError(err)
+			}
+			return
+		}
+		if value != organizationID {
+			db.AddError(ErrCrossTenant)
+		}
+	}
+
+	switch rows := db.Statement.ReflectValue; rows.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rows.Len(); i++ {
+			stampRow(reflect.Indirect(rows.Index(i)))
+		}
+	case reflect.Struct:
+		stampRow(rows)
+	}
+}
@@ -0,0 +1,63 @@
+// Package ticket signs the codes printed in attendees' ticket QR codes.
+package ticket
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+var ErrInvalid = errors.New("ticket: invalid code")
+
+// Signer signs ticket codes. A code names a ticket, either a registration or an
+// attendee of a group booking, and the nonce it was issued with; reissuing a
+// ticket changes the nonce, which invalidates every code signed before.
+type Signer struct {
+	secret []byte
+}
+
+func NewSigner(secret string) *Signer {
+	return &Signer{
+		secret: []byte(secret),
+	}
+}
+
+// NewNonce returns a fresh random nonce for a reissued ticket.
+func NewNonce() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// Sign returns the code of a ticket.
+func (s *Signer) Sign(ticketID string, nonce string) string {
+	payload := ticketID + "." + nonce
+	return payload + "." + s.mac(payload)
+}
+
+// Parse verifies a code and returns the ticket and nonce it was signed for. The
+// caller still has to check the nonce against the ticket's current one.
+func (s *Signer) Parse(code string) (ticketID string, nonce string, err error) {
+	parts := strings.Split(code, ".")
+	if len(parts) != 3 {
+		return "", "", ErrInvalid
+	}
+
+	expected := s.mac(parts[0] + "." + parts[1])
+	if !hmac.Equal([]byte(expected), []byte(parts[2])) {
+		return "", "", ErrInvalid
+	}
+
+	return parts[0], parts[1], nil
+}
+
+func (s *Signer) mac(payload string) string {
+	h := hmac.New(sha256.New, s.secret)
+	h.Write([]byte(payload))
+	return hex.EncodeToString(h.Sum(nil))
+}
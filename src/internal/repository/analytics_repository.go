@@ -0,0 +1,396 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hafiztri123/src/internal/model"
+	"github.com/hafiztri123/src/internal/pkg/cache"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	analyticsDayFormat = "20060102"
+	// analyticsKeyTTL is a safety net so counters are not kept forever if the
+	// rollup job stops running.
+	analyticsKeyTTL = 8 * 24 * time.Hour
+)
+
+// AnalyticsDayKey identifies the live counters of one event on one day.
+type AnalyticsDayKey struct {
+	EventID string
+	Day     time.Time
+}
+
+type AnalyticsRepository interface {
+	RecordView(ctx context.Context, eventID string, visitorID string, at time.Time) error
+	Increment(ctx context.Context, eventID string, metric string, at time.Time) error
+	ListLiveDays(ctx context.Context, before time.Time) ([]AnalyticsDayKey, error)
+	Rollup(ctx context.Context, key AnalyticsDayKey) error
+	DailyStats(ctx context.Context, eventID string, from, to time.Time) ([]*model.EventDailyStat, int64, error)
+}
+
+type analyticsRepositoryImpl struct {
+	db    *gorm.DB
+	cache *cache.RedisCache
+}
+
+func NewAnalyticsRepository(db *gorm.DB, cache *cache.RedisCache) AnalyticsRepository {
+	return &analyticsRepositoryImpl{
+		db:    db,
+		cache: cache,
+	}
+}
+
+func analyticsKey(eventID string, day time.Time, metric string) string {
+	return fmt.Sprintf("analytics:%s:%s:%s", eventID, day.UTC().Format(analyticsDayFormat), metric)
+}
+
+// analyticsRollupKey holds a counter while it is being rolled up.
+func analyticsRollupKey(eventID string, day time.Time, metric string) string {
+	return fmt.Sprintf("analytics-rollup:%s:%s:%s", eventID, day.UTC().Format(analyticsDayFormat), metric)
+}
+
+// analyticsRollupIDKey holds the ID of the batch being rolled up for an event day.
+func analyticsRollupIDKey(eventID string, day time.Time) string {
+	return analyticsRollupKey(eventID, day, "id")
+}
+
+var analyticsMetrics = []string{
+	model.MetricViews, model.MetricVisitors, model.MetricRegistrations, model.MetricCheckIns,
+}
+
+type analyticsBatch struct {
+	id     string
+	live   []string
+	rollup []string
+}
+
+func analyticsBatchKeys(key AnalyticsDayKey) analyticsBatch {
+	batch := analyticsBatch{id: analyticsRollupIDKey(key.EventID, key.Day)}
+	for _, metric := range analyticsMetrics {
+		batch.live = append(batch.live, analyticsKey(key.EventID, key.Day, metric))
+		batch.rollup = append(batch.rollup, analyticsRollupKey(key.EventID, key.Day, metric))
+	}
+	return batch
+}
+
+// startRollupScript moves an event day's live counters aside for a rollup and
+// returns the batch ID. If a batch is still there from an earlier attempt, it is
+// returned unchanged and the live counters wait for the next rollup. KEYS are the
+// batch ID, the live counters and their rollup keys in the same order; it returns
+// nil if there is nothing to roll up.
+var startRollupScript = redis.NewScript(`
+local id = redis.call('GET', KEYS[1])
+if id then
+	return id
+end
+local n = (#KEYS - 1) / 2
+local moved = false
+for i = 2, n + 1 do
+	if redis.call('EXISTS', KEYS[i]) == 1 then
+		redis.call('RENAME', KEYS[i], KEYS[i + n])
+		moved = true
+	end
+end
+if not moved then
+	return false
+end
+redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+return ARGV[1]
+`)
+
+// RecordView counts a view and adds the visitor to the day's HyperLogLog.
+func (r *analyticsRepositoryImpl) RecordView(ctx context.Context, eventID string, visitorID string, at time.Time) error {
+	viewsKey := analyticsKey(eventID, at, model.MetricViews)
+	visitorsKey := analyticsKey(eventID, at, model.MetricVisitors)
+
+	pipe := r.cache.Client.TxPipeline()
+	pipe.Incr(ctx, viewsKey)
+	pipe.PFAdd(ctx, visitorsKey, visitorID)
+	pipe.Expire(ctx, viewsKey, analyticsKeyTTL)
+	pipe.Expire(ctx, visitorsKey, analyticsKeyTTL)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (r *analyticsRepositoryImpl) Increment(ctx context.Context, eventID string, metric string, at time.Time) error {
+	key := analyticsKey(eventID, at, metric)
+
+	pipe := r.cache.Client.TxPipeline()
+	pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, analyticsKeyTTL)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// ListLiveDays returns the event days that still have counters in Redis for days
+// strictly before the given day, including days whose rollup was interrupted.
+func (r *analyticsRepositoryImpl) ListLiveDays(ctx context.Context, before time.Time) ([]AnalyticsDayKey, error) {
+	seen := map[AnalyticsDayKey]bool{}
+	var keys []AnalyticsDayKey
+
+	for _, pattern := range []string{"analytics:*", "analytics-rollup:*"} {
+		iter := r.cache.Client.Scan(ctx, 0, pattern, 500).Iterator()
+		for iter.Next(ctx) {
+			parts := strings.Split(iter.Val(), ":")
+			if len(parts) != 4 {
+				continue
+			}
+
+			day, err := time.Parse(analyticsDayFormat, parts[2])
+			if err != nil || !day.Before(before) {
+				continue
+			}
+
+			key := AnalyticsDayKey{EventID: parts[1], Day: day}
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+
+		if err := iter.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	return keys, nil
+}
+
+// Rollup adds the live counters of one event day to Postgres and removes them from
+// Redis. The day's visitor sketch is merged with any sketch already stored.
+//
+// The counters are first moved aside into a batch with its own ID, so increments
+// arriving during the rollup are kept for the next one. The batch is only deleted
+// after the row is written, and the row records the batch ID, so a rollup that
+// fails at any point can be retried without losing or double counting anything.
+func (r *analyticsRepositoryImpl) Rollup(ctx context.Context, key AnalyticsDayKey) error {
+	client := r.cache.Client
+	batch := analyticsBatchKeys(key)
+
+	keys := append([]string{batch.id}, batch.live...)
+	keys = append(keys, batch.rollup...)
+	rollupID, err := startRollupScript.Run(ctx, client, keys, uuid.NewString(), analyticsKeyTTL.Milliseconds()).Text()
+	if err == redis.Nil {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	viewsKey := analyticsRollupKey(key.EventID, key.Day, model.MetricViews)
+	visitorsKey := analyticsRollupKey(key.EventID, key.Day, model.MetricVisitors)
+	registrationsKey := analyticsRollupKey(key.EventID, key.Day, model.MetricRegistrations)
+	checkInsKey := analyticsRollupKey(key.EventID, key.Day, model.MetricCheckIns)
+
+	views, err := readCounter(ctx, client, viewsKey)
+	if err != nil {
+		return err
+	}
+	registrations, err := readCounter(ctx, client, registrationsKey)
+	if err != nil {
+		return err
+	}
+	checkIns, err := readCounter(ctx, client, checkInsKey)
+	if err != nil {
+		return err
+	}
+
+	var existing model.EventDailyStat
+	found := true
+	err = r.db.WithContext(ctx).
+		Where("event_id = ? AND day = ?", key.EventID, key.Day).
+		First(&existing).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		found = false
+	} else if err != nil {
+		return DBError(err)
+	}
+
+	sketchKeys := []string{visitorsKey}
+	tempKey := ""
+	if found && len(existing.VisitorSketch) > 0 {
+		tempKey = "analytics-tmp:" + uuid.NewString()
+		if err := client.Set(ctx, tempKey, existing.VisitorSketch, time.Minute).Err(); err != nil {
+			return err
+		}
+		defer client.Del(ctx, tempKey)
+
+		if err := client.PFMerge(ctx, tempKey, tempKey, visitorsKey).Err(); err != nil {
+			return err
+		}
+		sketchKeys = []string{tempKey}
+	}
+
+	sketch, err := client.Get(ctx, sketchKeys[0]).Bytes()
+	if err != nil && err != redis.Nil {
+		return err
+	}
+
+	unique, err := client.PFCount(ctx, sketchKeys...).Result()
+	if err != nil {
+		return err
+	}
+
+	stat := &model.EventDailyStat{
+		EventID:        key.EventID,
+		Day:            key.Day,
+		Views:          views,
+		UniqueVisitors: unique,
+		Registrations:  registrations,
+		CheckIns:       checkIns,
+		VisitorSketch:  sketch,
+		RollupID:       rollupID,
+		UpdatedAt:      time.Now(),
+	}
+
+	// A row that already carries this batch's ID was written by an earlier attempt
+	// that failed before it could clean up, so it is left as it is.
+	err = r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "event_id"}, {Name: "day"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"views":           gorm.Expr("event_daily_stats.views + excluded.views"),
+				"registrations":   gorm.Expr("event_daily_stats.registrations + excluded.registrations"),
+				"check_ins":       gorm.Expr("event_daily_stats.check_ins + excluded.check_ins"),
+				"unique_visitors": gorm.Expr("excluded.unique_visitors"),
+				"visitor_sketch":  gorm.Expr("excluded.visitor_sketch"),
+				"rollup_id":       gorm.Expr("excluded.rollup_id"),
+				"updated_at":      gorm.Expr("excluded.updated_at"),
+			}),
+			Where: clause.Where{Exprs: []clause.Expression{
+				clause.Expr{SQL: "event_daily_stats.rollup_id IS DISTINCT FROM excluded.rollup_id"},
+			}},
+		}).
+		Create(stat).Error
+	if err != nil {
+		return DBError(err)
+	}
+
+	return client.Del(ctx, append([]string{batch.id}, batch.rollup...)...).Err()
+}
+
+// DailyStats returns one row per day in [from, to], combining rolled up rows with
+// live counters and any batch still being rolled up, plus the number of unique
+// visitors over the whole range.
+func (r *analyticsRepositoryImpl) DailyStats(ctx context.Context, eventID string, from, to time.Time) ([]*model.EventDailyStat, int64, error) {
+	client := r.cache.Client
+
+	var rows []*model.EventDailyStat
+	err := r.db.WithContext(ctx).
+		Where("event_id = ? AND day BETWEEN ? AND ?", eventID, from, to).
+		Find(&rows).Error
+	if err != nil {
+		return nil, 0, DBError(err)
+	}
+
+	stored := map[string]*model.EventDailyStat{}
+	for _, row := range rows {
+		stored[row.Day.UTC().Format(analyticsDayFormat)] = row
+	}
+
+	var stats []*model.EventDailyStat
+	var allSketches []string
+	var tempKeys []string
+	defer func() {
+		if len(tempKeys) > 0 {
+			client.Del(ctx, tempKeys...)
+		}
+	}()
+
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		stat := &model.EventDailyStat{EventID: eventID, Day: day}
+		var sketches []string
+
+		row, found := stored[day.Format(analyticsDayFormat)]
+		if found {
+			stat.Views = row.Views
+			stat.Registrations = row.Registrations
+			stat.CheckIns = row.CheckIns
+			stat.UniqueVisitors = row.UniqueVisitors
+
+			if len(row.VisitorSketch) > 0 {
+				tempKey := "analytics-tmp:" + uuid.NewString()
+				if err := client.Set(ctx, tempKey, row.VisitorSketch, time.Minute).Err(); err != nil {
+					return nil, 0, err
+				}
+				tempKeys = append(tempKeys, tempKey)
+				sketches = append(sketches, tempKey)
+			}
+		}
+
+		counterKeys := []func(string, time.Time, string) string{analyticsKey}
+
+		rollupID, err := client.Get(ctx, analyticsRollupIDKey(eventID, day)).Result()
+		if err != nil && err != redis.Nil {
+			return nil, 0, err
+		}
+		if err == nil && (!found || row.RollupID != rollupID) {
+			counterKeys = append(counterKeys, analyticsRollupKey)
+		}
+
+		for _, counterKey := range counterKeys {
+			views, err := readCounter(ctx, client, counterKey(eventID, day, model.MetricViews))
+			if err != nil {
+				return nil, 0, err
+			}
+			registrations, err := readCounter(ctx, client, counterKey(eventID, day, model.MetricRegistrations))
+			if err != nil {
+				return nil, 0, err
+			}
+			checkIns, err := readCounter(ctx, client, counterKey(eventID, day, model.MetricCheckIns))
+			if err != nil {
+				return nil, 0, err
+			}
+
+			stat.Views += views
+			stat.Registrations += registrations
+			stat.CheckIns += checkIns
+
+			visitorsKey := counterKey(eventID, day, model.MetricVisitors)
+			exists, err := client.Exists(ctx, visitorsKey).Result()
+			if err != nil {
+				return nil, 0, err
+			}
+			if exists > 0 {
+				sketches = append(sketches, visitorsKey)
+			}
+		}
+
+		if len(sketches) > 0 {
+			unique, err := client.PFCount(ctx, sketches...).Result()
+			if err != nil {
+				return nil, 0, err
+			}
+			stat.UniqueVisitors = unique
+			allSketches = append(allSketches, sketches...)
+		}
+
+		stats = append(stats, stat)
+	}
+
+	var unique int64
+	if len(allSketches) > 0 {
+		unique, err = client.PFCount(ctx, allSketches...).Result()
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return stats, unique, nil
+}
+
+func readCounter(ctx context.Context, client *redis.Client, key string) (int64, error) {
+	value, err := client.Get(ctx, key).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return value, err
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/hafiztri123/src/internal/model"
+	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"gorm.io/gorm"
+)
+
+type AttendeeRepository interface {
+	GetByID(ctx context.Context, id string) (*model.Attendee, error)
+	ListByEventAndEmail(ctx context.Context, eventID, email string) ([]*model.Attendee, error)
+	UpdateDetails(ctx context.Context, attendees []*model.Attendee) error
+	CheckIn(ctx context.Context, attendee *model.Attendee, at time.Time) error
+}
+
+type attendeeRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewAttendeeRepository(db *gorm.DB) AttendeeRepository {
+	return &attendeeRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *attendeeRepositoryImpl) GetByID(ctx context.Context, id string) (*model.Attendee, error) {
+	var attendee model.Attendee
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&attendee).Error
+	if err != nil {
+		return nil, errs.NewNotFoundError("Attendee not found")
+	}
+
+	return &attendee, nil
+}
+
+// ListByEventAndEmail returns the attendees of the event named with the email.
+func (r *attendeeRepositoryImpl) ListByEventAndEmail(ctx context.Context, eventID, email string) ([]*model.Attendee, error) {
+	var attendees []*model.Attendee
+	err := r.db.WithContext(ctx).
+		Where("event_id = ? AND LOWER(email) = LOWER(?)", eventID, email).
+		Order("created_at ASC, id ASC").
+		Find(&attendees).Error
+	if err != nil {
+		return nil, DBError(err)
+	}
+
+	return attendees, nil
+}
+
+// UpdateDetails stores the names, emails and ticket nonces of attendees in one
+// transaction. Attendees who have checked in in the meantime are left untouched
+// and fail the whole update with a ConflictError.
+func (r *attendeeRepositoryImpl) UpdateDetails(ctx context.Context, attendees []*model.Attendee) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		for _, attendee := range attendees {
+			result := tx.Model(&model.Attendee{}).
+				Where("id = ? AND checked_in_at IS NULL", attendee.ID).
+				Updates(map[string]interface{}{
+					"name":         attendee.Name,
+					"email":        attendee.Email,
+					"ticket_nonce": attendee.TicketNonce,
+					"updated_at":   now,
+				})
+			if result.Error != nil {
+				return result.Error
+			}
+
+			if result.RowsAffected == 0 {
+				return errs.NewConflictError("Attendee " + attendee.ID + " has already checked in")
+			}
+
+			attendee.UpdatedAt = now
+		}
+
+		return nil
+	})
+
+	return DBError(err)
+}
+
+// CheckIn marks an attendee as checked in. Checking in twice is a ConflictError.
+func (r *attendeeRepositoryImpl) CheckIn(ctx context.Context, attendee *model.Attendee, at time.Time) error {
+	result := r.db.WithContext(ctx).
+		Model(&model.Attendee{}).
+		Where("id = ? AND checked_in_at IS NULL", attendee.ID).
+		Updates(map[string]interface{}{
+			"checked_in_at": at,
+			"updated_at":    at,
+		})
+	if result.Error != nil {
+		return DBError(result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return errs.NewConflictError("Attendee has already checked in")
+	}
+
+	attendee.CheckedInAt = &at
+	attendee.UpdatedAt = at
+	return nil
+}
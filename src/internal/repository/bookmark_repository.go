@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/hafiztri123/src/internal/model"
+	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"github.com/hafiztri123/src/internal/pkg/tenant"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BookmarkRepository interface {
+	Create(ctx context.Context, bookmark *model.Bookmark) (bool, error)
+	Delete(ctx context.Context, userID, eventID string) error
+	ListByUser(ctx context.Context, userID string) ([]*model.Bookmark, error)
+}
+
+type bookmarkRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewBookmarkRepository(db *gorm.DB) BookmarkRepository {
+	return &bookmarkRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create saves a bookmark and reports whether it is new. Bookmarking the same event
+// twice is not an error.
+func (r *bookmarkRepositoryImpl) Create(ctx context.Context, bookmark *model.Bookmark) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(bookmark)
+	if result.Error != nil {
+		return false, DBError(result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (r *bookmarkRepositoryImpl) Delete(ctx context.Context, userID, eventID string) error {
+	result := r.db.WithContext(ctx).
+		Where("user_id = ? AND event_id = ?", userID, eventID).
+		Delete(&model.Bookmark{})
+	if result.Error != nil {
+		return DBError(result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return errs.NewNotFoundError("Bookmark not found")
+	}
+
+	return nil
+}
+
+// ListByUser returns the user's bookmarks of events in the organization of the
+// context. The tenant plugin only scopes the bookmarks table, so the joined events
+// are filtered here.
+func (r *bookmarkRepositoryImpl) ListByUser(ctx context.Context, userID string) ([]*model.Bookmark, error) {
+	query := r.db.WithContext(ctx).
+		Joins("Event").
+		Where("bookmarks.user_id = ?", userID)
+
+	if !tenant.IsSystem(ctx) {
+		organizationID, ok := tenant.FromContext(ctx)
+		if !ok {
+			return nil, DBError(tenant.ErrMissing)
+		}
+		query = query.Where(clause.Eq{Column: clause.Column{Table: "Event", Name: tenant.Column}, Value: organizationID})
+	}
+
+	var bookmarks []*model.Bookmark
+	err := query.
+		Order("bookmarks.created_at DESC").
+		Find(&bookmarks).Error
+	if err != nil {
+		return nil, DBError(err)
+	}
+
+	return bookmarks, nil
+}
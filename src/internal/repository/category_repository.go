@@ -16,10 +16,16 @@ import (
 type CategoryRepository interface {
 	Create(ctx context.Context, category *model.Category) error
 	Update(ctx context.Context, category *model.Category) error
-	Delete(ctx context.Context, id string) error
+	Delete(ctx context.Context, id string, version int) error
 	GetByID(ctx context.Context, id string) (*model.Category, error)
 	List(ctx context.Context) ([]*model.Category, error)
-	IsIDExists(id string) (bool)
+	IsIDExists(ctx context.Context, id string) (bool)
+	ListDeleted(ctx context.Context, creatorID string) ([]*model.Category, error)
+	GetDeletedByID(ctx context.Context, id string) (*model.Category, error)
+	Restore(ctx context.Context, id string) error
+	ListPurgeable(ctx context.Context, deletedBefore time.Time) ([]*model.Category, error)
+	Purge(ctx context.Context, id string) error
+	ResolveSlug(ctx context.Context, slug string) (string, error)
 }
 
 type categoryRepositoryImpl struct {
@@ -35,9 +41,17 @@ func NewCategoryRepository(db *gorm.DB,  redis *cache.RedisCache) CategoryReposi
 }
 
 func(r *categoryRepositoryImpl) Create(ctx context.Context, category *model.Category) error {
-	model := r.db.Model(&model.Category{})
-	
-	err := model.Create(category).Error
+	err := withSlugRetry(func() error {
+		return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			slug, err := allocateSlug(tx, model.SlugResourceCategory, "", category.Name)
+			if err != nil {
+				return err
+			}
+			category.Slug = slug
+
+			return tx.Create(category).Error
+		})
+	})
 	if err != nil {
 		return DBError(err)
 	}
@@ -58,41 +72,54 @@ func(r *categoryRepositoryImpl) Create(ctx context.Context, category *model.Cate
 	return nil
 }
 
+// Update overwrites the name and description of a category. A non-zero version makes
+// the write conditional on the stored version. A new name moves the category to a
+// new slug and the old one redirects to it.
 func (r *categoryRepositoryImpl) Update(ctx context.Context, category *model.Category) error {
 	if category == nil {
 		return errs.NewBadRequestError("")
 	}
 
 	var existingCategory model.Category
-	model := r.db.Model(&model.Category{})
-	err := model.Where("id = ?", category.ID).First(&existingCategory).Error
+	query := r.db.WithContext(ctx).Model(&model.Category{})
+	err := query.Where("id = ?", category.ID).First(&existingCategory).Error
 	if err != nil {
 		return DBError(err)
 	}
 
-	if category.Name != "" {
-		existingCategory.Name = category.Name
-	}
-
-	if category.Description != "" {
-		existingCategory.Description = category.Description
+	if category.Version != 0 && category.Version != existingCategory.Version {
+		return errs.NewPreconditionFailedError("Category was modified by another request")
 	}
 
-	if category.Name != "" || category.Description != "" {
-		existingCategory.UpdatedAt = time.Now()
-	} 
-
-	err = r.db.Transaction(func(tx *gorm.DB) error {
-		err := tx.Save(existingCategory).Error
-		if err != nil {
-			return err
-		}
-		return nil
+	renamed := existingCategory.Name != category.Name || existingCategory.Slug == ""
+	existingCategory.Name = category.Name
+	existingCategory.Description = category.Description
+	existingCategory.UpdatedAt = time.Now()
+
+	err = withSlugRetry(func() error {
+		return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			slug := existingCategory.Slug
+			if renamed {
+				var err error
+				slug, err = renameSlug(tx, model.SlugResourceCategory, existingCategory.ID, existingCategory.Slug, existingCategory.Name)
+				if err != nil {
+					return err
+				}
+			}
+
+			if err := updateCategory(tx, &existingCategory); err != nil {
+				return err
+			}
+
+			existingCategory.Slug = slug
+			return nil
+		})
 	})
 
 	if err != nil {
 		return DBError(err)
 	}
+	existingCategory.Version++
 
 	cacheKey := fmt.Sprintf("categories:%s", existingCategory.ID)
 	err = r.cache.Set(ctx, cacheKey, existingCategory, 30*time.Minute)
@@ -116,11 +143,39 @@ func (r *categoryRepositoryImpl) Update(ctx context.Context, category *model.Cat
 	return nil
 }
 
-func (r *categoryRepositoryImpl) Delete(ctx context.Context, categoryID string) error {
-	err := r.db.Transaction(func(tx *gorm.DB) error {
-		err := tx.Delete(&model.Category{}, "id = ?", categoryID).Error
-		if err != nil {
-			return err
+func updateCategory(tx *gorm.DB, existingCategory *model.Category) error {
+	result := tx.Model(&model.Category{}).
+		Where("id = ? AND version = ?", existingCategory.ID, existingCategory.Version).
+		Updates(map[string]interface{}{
+			"name":        existingCategory.Name,
+			"description": existingCategory.Description,
+			"updated_at":  existingCategory.UpdatedAt,
+			"version":     gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errs.NewPreconditionFailedError("Category was modified by another request")
+	}
+	return nil
+}
+
+// Delete soft-deletes a category. A non-zero version makes the delete conditional on
+// the stored version.
+func (r *categoryRepositoryImpl) Delete(ctx context.Context, categoryID string, version int) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx
+		if version != 0 {
+			query = query.Where("version = ?", version)
+		}
+
+		result := query.Delete(&model.Category{}, "id = ?", categoryID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errs.NewPreconditionFailedError("Category was modified by another request")
 		}
 		return nil
 	})
@@ -152,9 +207,15 @@ func (r *categoryRepositoryImpl) Delete(ctx context.Context, categoryID string)
 
 }
  
+// ResolveSlug returns the ID of the category with the slug, following the redirects
+// left by renames.
+func (r *categoryRepositoryImpl) ResolveSlug(ctx context.Context, slug string) (string, error) {
+	return resolveSlug(r.db.WithContext(ctx), model.SlugResourceCategory, slug)
+}
+
 func (r *categoryRepositoryImpl) GetByID(ctx context.Context, id string) (*model.Category, error){
 	var existingCategory model.Category
-	category := r.db.Model(&model.Category{})
+	category := r.db.WithContext(ctx).Model(&model.Category{})
 	err := category.Where("id = ?", id).First(&existingCategory).Error
 	if err != nil {
 		return nil,  DBError(err)
@@ -171,13 +232,13 @@ func (r *categoryRepositoryImpl) GetByID(ctx context.Context, id string) (*model
 
 func (r *categoryRepositoryImpl) List(ctx context.Context) ([]*model.Category, error){
 	var existingCategories []*model.Category
-	category := r.db.Model(&model.Category{})
+	category := r.db.WithContext(ctx).Model(&model.Category{})
 	err := category.Find(&existingCategories).Error
 	if err != nil{
 		return nil, DBError(err)
 	}
 
-	listKeysPattern := "categories:list"
+	listKeysPattern := fmt.Sprintf("categories:list:%s", cacheScope(ctx))
 	err = r.cache.Set(ctx, listKeysPattern, existingCategories, 30*time.Minute)
 
 	if err != nil {
@@ -187,16 +248,88 @@ func (r *categoryRepositoryImpl) List(ctx context.Context) ([]*model.Category, e
 	return existingCategories, nil
 } 
 
-func (r *categoryRepositoryImpl) IsIDExists(id string) (bool) {
+func (r *categoryRepositoryImpl) IsIDExists(ctx context.Context, id string) (bool) {
 	var idCount int64
-	model := r.db.Model(&model.Category{})
+	model := r.db.WithContext(ctx).Model(&model.Category{})
 	model.Where("id = ?", id).Count(&idCount)
 	return idCount > 0
 }
 
+func (r *categoryRepositoryImpl) ListDeleted(ctx context.Context, creatorID string) ([]*model.Category, error) {
+	var categories []*model.Category
+	err := r.db.WithContext(ctx).Unscoped().
+		Where("creator_id = ? AND deleted_at IS NOT NULL", creatorID).
+		Order("deleted_at DESC").
+		Find(&categories).Error
+	if err != nil {
+		return nil, DBError(err)
+	}
 
+	return categories, nil
+}
 
+func (r *categoryRepositoryImpl) GetDeletedByID(ctx context.Context, id string) (*model.Category, error) {
+	var category model.Category
+	err := r.db.WithContext(ctx).Unscoped().
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		First(&category).Error
+	if err != nil {
+		return nil, DBError(err)
+	}
 
+	return &category, nil
+}
 
+func (r *categoryRepositoryImpl) Restore(ctx context.Context, id string) error {
+	err := r.db.WithContext(ctx).Unscoped().
+		Model(&model.Category{}).
+		Where("id = ?", id).
+		Update("deleted_at", nil).Error
+	if err != nil {
+		return DBError(err)
+	}
 
+	keys, err := r.cache.Client.Keys(ctx, "categories:list*").Result()
+	if err != nil {
+		log.Printf("%s: %v", CACHE_KEYS_FAIL, err)
+	}
 
+	for _, key := range keys {
+		if err := r.cache.Delete(ctx, key); err != nil {
+			log.Printf("%s: %v", CACHE_DELETE_FAIL, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *categoryRepositoryImpl) ListPurgeable(ctx context.Context, deletedBefore time.Time) ([]*model.Category, error) {
+	var categories []*model.Category
+	err := r.db.WithContext(ctx).Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore).
+		Find(&categories).Error
+	if err != nil {
+		return nil, DBError(err)
+	}
+
+	return categories, nil
+}
+
+func (r *categoryRepositoryImpl) Purge(ctx context.Context, id string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("resource_type = ? AND target_id = ?", model.SlugResourceCategory, id).Delete(&model.SlugRedirect{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("category_id = ?", id).Delete(&model.CategoryTranslation{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().
+			Where("deleted_at IS NOT NULL").
+			Delete(&model.Category{}, "id = ?", id).Error
+	})
+	if err != nil {
+		return DBError(err)
+	}
+
+	return nil
+}
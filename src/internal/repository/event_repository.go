@@ -4,36 +4,59 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/hafiztri123/src/internal/model"
 	"github.com/hafiztri123/src/internal/pkg/cache"
 	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"github.com/hafiztri123/src/internal/pkg/i18n"
+	"github.com/hafiztri123/src/internal/pkg/tenant"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type EventRepository interface {
     GetByID(ctx context.Context, id string) (*model.Event, error)
     List(ctx context.Context, limit, offset int, sortBy, sortDir string) ([]*model.Event, error)
-    Create(ctx context.Context, event *model.Event) error
-    Update(ctx context.Context, event *model.Event) error
-    Delete(ctx context.Context, id string) error
+    Create(ctx context.Context, event *model.Event, revision *model.EventRevision) error
+    Update(ctx context.Context, event *model.Event, revision *model.EventRevision) error
+    Delete(ctx context.Context, id string, version int) error
     Search(ctx context.Context, params *model.SearchEventsInput) ([]*model.Event, int64, error)
     UploadFile(ctx context.Context, file *model.File) error
+    ListFiles(ctx context.Context, eventID string) ([]*model.File, error)
+    GetFile(ctx context.Context, eventID, fileID string) (*model.File, error)
+    UpdateFile(ctx context.Context, file *model.File) error
+    DeleteFile(ctx context.Context, eventID, fileID string) error
+    ReorderFiles(ctx context.Context, eventID string, fileIDs []string) error
+    ListInRange(ctx context.Context, params *model.ConflictsInput) ([]*model.Event, error)
+    ListForCalendar(ctx context.Context, params *model.SearchEventsInput, from, to time.Time) ([]*model.Event, error)
+    ListDeleted(ctx context.Context, creatorID string) ([]*model.Event, error)
+    GetDeletedByID(ctx context.Context, id string) (*model.Event, error)
+    Restore(ctx context.Context, id string) error
+    ListPurgeable(ctx context.Context, deletedBefore time.Time) ([]*model.Event, error)
+    Purge(ctx context.Context, id string) error
+    Cancel(ctx context.Context, id string, cancelledAt time.Time) error
+    ResolveSlug(ctx context.Context, slug string) (string, error)
 }
 
 type eventRepository struct {
-    db    *gorm.DB
-    cache *cache.RedisCache
+    db      *gorm.DB
+    cache   *cache.RedisCache
+    catalog *i18n.Catalog
 }
 
-func NewEventRepository(db *gorm.DB, cache *cache.RedisCache) EventRepository {
+func NewEventRepository(db *gorm.DB, cache *cache.RedisCache, catalog *i18n.Catalog) EventRepository {
     return &eventRepository{
-        db:    db,
-        cache: cache,
+        db:      db,
+        cache:   cache,
+        catalog: catalog,
     }
 }
 
+// unpaidOrderStatuses are the final states of orders that never took any money.
+var unpaidOrderStatuses = []string{model.OrderStatusExpired, model.OrderStatusFailed, model.OrderStatusCancelled}
+
 const (
     CACHE_SET_FAIL string = "[FAIL] Setting cached failed"
     CACHE_KEYS_FAIL string = "[FAIL] Getting cached keys failed"
@@ -45,11 +68,16 @@ func (r *eventRepository) GetByID(ctx context.Context, id string) (*model.Event,
     cacheKey := fmt.Sprintf("event:%s", id)
     err := r.cache.Get(ctx, cacheKey, &event)
 
-    if err == nil && event.ID != "" {
+    if err == nil && event.ID != "" && tenant.Allows(ctx, event.OrganizationID) {
         return &event, nil
     }
 
-    err = r.db.Where("id = ?", id).First(&event).Error
+    event = model.Event{}
+    err = r.db.WithContext(ctx).Where("id = ?", id).
+        Preload("Files", func(db *gorm.DB) *gorm.DB {
+            return db.Order("position ASC, created_at ASC")
+        }).
+        First(&event).Error
     if err != nil {
         return nil, errs.NewNotFoundError("Event not found")
     }
@@ -65,7 +93,7 @@ func (r *eventRepository) GetByID(ctx context.Context, id string) (*model.Event,
 // List retrieves a paginated list of events, using cache if available.
 func (r *eventRepository) List(ctx context.Context, limit, offset int, sortBy, sortDir string) ([]*model.Event, error) {
     var events []*model.Event
-    cacheKey := fmt.Sprintf("events:list:%d:%d:%s:%s", limit, offset, sortBy, sortDir)
+    cacheKey := fmt.Sprintf("events:list:%s:%d:%d:%s:%s", cacheScope(ctx), limit, offset, sortBy, sortDir)
     err := r.cache.Get(ctx, cacheKey, &events)
 
     if err == nil && len(events) > 0 {
@@ -93,7 +121,7 @@ func (r *eventRepository) List(ctx context.Context, limit, offset int, sortBy, s
         sortDir = "ASC"
     }
 
-    query := r.db.
+    query := r.db.WithContext(ctx).
         Limit(limit).
         Offset(offset).
         Order(fmt.Sprintf("%s %s", sortBy, sortDir))
@@ -111,9 +139,28 @@ func (r *eventRepository) List(ctx context.Context, limit, offset int, sortBy, s
     return events, nil
 }
 
-// Create inserts a new event into the database and invalidates relevant cache keys.
-func (r *eventRepository) Create(ctx context.Context, event *model.Event) error {
-    err := r.db.Create(event).Error
+// Create inserts a new event together with its initial revision and invalidates
+// relevant cache keys. An event that overlaps another event at the same venue or of
+// the same creator is refused with a ConflictError.
+func (r *eventRepository) Create(ctx context.Context, event *model.Event, revision *model.EventRevision) error {
+    err := withSlugRetry(func() error {
+        return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+            if err := checkSchedule(tx, event); err != nil {
+                return err
+            }
+
+            slug, err := allocateSlug(tx, model.SlugResourceEvent, "", event.Title)
+            if err != nil {
+                return err
+            }
+            event.Slug = slug
+
+            if err := tx.Create(event).Error; err != nil {
+                return err
+            }
+            return createRevision(tx, event.ID, revision)
+        })
+    })
     if err != nil {
         return DBError(err)
     }
@@ -134,20 +181,22 @@ func (r *eventRepository) Create(ctx context.Context, event *model.Event) error
     return nil
 }
 
-func (r *eventRepository) Update(ctx context.Context, event *model.Event) error {
-    err := r.db.Transaction(func(tx *gorm.DB) error {
-        err := tx.Save(event).Error
-        if err != nil {
-            return err
-        }
-        return nil
+// Update saves the event and records the revision in the same transaction. The
+// write only succeeds if the stored version still equals event.Version, which is
+// then incremented. A new title moves the event to a new slug and the old one
+// redirects to it. Schedule conflicts are refused as in Create.
+func (r *eventRepository) Update(ctx context.Context, event *model.Event, revision *model.EventRevision) error {
+    err := withSlugRetry(func() error {
+        return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+            return r.update(tx, event, revision)
+        })
     })
 
     if err != nil {
         return DBError(err)
     }
 
-    cacheKey := fmt.Sprintf("events:%s", event.ID)
+    cacheKey := fmt.Sprintf("event:%s", event.ID)
     err = r.cache.Set(ctx, cacheKey, event, 30*time.Minute)
     if err != nil {
         log.Printf("%s: %v", CACHE_SET_FAIL, err)
@@ -169,13 +218,87 @@ func (r *eventRepository) Update(ctx context.Context, event *model.Event) error
     return nil
 }
 
-// Delete removes an event from the database and invalidates relevant cache keys.
-func (r *eventRepository) Delete(ctx context.Context, id string) error {
-    err := r.db.Transaction(func(tx *gorm.DB) error {
-        err := tx.Delete(&model.Event{}, "id = ?", id).Error
+// update runs inside the transaction of Update. The slug is settled before the
+// version is bumped so that a retry after a slug conflict starts from the same
+// event.
+func (r *eventRepository) update(tx *gorm.DB, event *model.Event, revision *model.EventRevision) error {
+    if err := checkSchedule(tx, event); err != nil {
+        return err
+    }
+
+    var current model.Event
+    if err := tx.Select("title", "slug").Where("id = ?", event.ID).Take(&current).Error; err != nil {
+        return err
+    }
+
+    slug := current.Slug
+    if current.Title != event.Title || current.Slug == "" {
+        var err error
+        slug, err = renameSlug(tx, model.SlugResourceEvent, event.ID, current.Slug, event.Title)
         if err != nil {
             return err
         }
+    }
+
+    result := tx.Model(&model.Event{}).
+        Where("id = ? AND version = ?", event.ID, event.Version).
+        Updates(map[string]interface{}{
+            "title":       event.Title,
+            "description": event.Description,
+            "venue":       event.Venue,
+            "category_id": event.CategoryID,
+            "start_date":  event.StartDate,
+            "end_date":    event.EndDate,
+            "updated_at":  event.UpdatedAt,
+            "version":     gorm.Expr("version + 1"),
+        })
+    if result.Error != nil {
+        return result.Error
+    }
+    if result.RowsAffected == 0 {
+        return errs.NewPreconditionFailedError("Event was modified by another request")
+    }
+
+    if err := createRevision(tx, event.ID, revision); err != nil {
+        return err
+    }
+
+    event.Slug = slug
+    event.Version++
+    return nil
+}
+
+// Delete soft-deletes an event if it is still at the given version and invalidates
+// relevant cache keys. The row stays in the trash until it is restored or purged.
+// Delete moves an event to the trash. Events with orders that are still being paid
+// or hold buyers' money are refused with a ConflictError; they have to be cancelled,
+// which refunds them, first.
+func (r *eventRepository) Delete(ctx context.Context, id string, version int) error {
+    err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+        var locked model.Event
+        err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ?", id).First(&locked).Error
+        if err != nil {
+            return err
+        }
+
+        var open int64
+        err = tx.Model(&model.Order{}).
+            Where("event_id = ? AND status IN ?", id, []string{model.OrderStatusPending, model.OrderStatusPaid, model.OrderStatusPartiallyRefunded}).
+            Count(&open).Error
+        if err != nil {
+            return err
+        }
+        if open > 0 {
+            return errs.NewConflictError("Event has paid or pending orders; cancel it to refund them before deleting it")
+        }
+
+        result := tx.Where("version = ?", version).Delete(&model.Event{}, "id = ?", id)
+        if result.Error != nil {
+            return result.Error
+        }
+        if result.RowsAffected == 0 {
+            return errs.NewPreconditionFailedError("Event was modified by another request")
+        }
         return nil
     })
 
@@ -209,12 +332,7 @@ func (r *eventRepository) Search(ctx context.Context, params *model.SearchEvents
     var events []*model.Event
     var totalCount int64
 
-    query := r.db.Model(&model.Event{})
-
-    if params.Query != "" {
-        query = query.Where("title ILIKE ? OR description ILIKE ?",
-            "%"+params.Query+"%", "%"+params.Query+"%")
-    }
+    query := r.applySearchFilters(r.db.WithContext(ctx).Model(&model.Event{}), params)
 
     if params.StartDate != nil {
         query = query.Where("start_date >= ?", params.StartDate)
@@ -224,10 +342,6 @@ func (r *eventRepository) Search(ctx context.Context, params *model.SearchEvents
         query = query.Where("end_date <= ?", params.EndDate)
     }
 
-    if params.Creator != "" {
-        query = query.Where("creator_id = ?", params.Creator)
-    }
-
     if err := query.Count(&totalCount).Error; err != nil {
         return nil, 0, DBError(err)
     }
@@ -258,41 +372,465 @@ func (r *eventRepository) Search(ctx context.Context, params *model.SearchEvents
     return events, totalCount, nil
 }
 
+// UploadFile stores the metadata of an uploaded file after the event's existing files.
 func (r *eventRepository) UploadFile(ctx context.Context, file *model.File) error{
-    err := r.db.Transaction(func(tx *gorm.DB) error {
-        return r.db.Model(&model.File{}).Save(file).Error
+    err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+        var position int
+        err := tx.Model(&model.File{}).
+            Where("event_id = ?", file.EventID).
+            Select("COALESCE(MAX(position) + 1, 0)").
+            Scan(&position).Error
+        if err != nil {
+            return err
+        }
+
+        file.Position = position
+        return tx.Create(file).Error
     })
 
     if err != nil {
         return DBError(err)
     }
 
-    cacheKey := fmt.Sprintf("events:%s", file.EventID)
-    event, err := r.GetByID(ctx, file.EventID)
+    r.invalidateEvent(ctx, file.EventID)
+    return nil
+}
+
+func (r *eventRepository) ListFiles(ctx context.Context, eventID string) ([]*model.File, error) {
+    var files []*model.File
+    err := r.db.WithContext(ctx).
+        Where("event_id = ?", eventID).
+        Order("position ASC, created_at ASC").
+        Find(&files).Error
+    if err != nil {
+        return nil, DBError(err)
+    }
+
+    return files, nil
+}
 
+func (r *eventRepository) GetFile(ctx context.Context, eventID, fileID string) (*model.File, error) {
+    var file model.File
+    err := r.db.WithContext(ctx).
+        Where("event_id = ? AND id = ?", eventID, fileID).
+        First(&file).Error
     if err != nil {
+        return nil, DBError(err)
+    }
+
+    return &file, nil
+}
+
+func (r *eventRepository) UpdateFile(ctx context.Context, file *model.File) error {
+    err := r.db.WithContext(ctx).
+        Model(&model.File{}).
+        Where("event_id = ? AND id = ?", file.EventID, file.ID).
+        Updates(map[string]interface{}{
+            "file_name":  file.FileName,
+            "updated_at": file.UpdatedAt,
+        }).Error
+    if err != nil {
+        return DBError(err)
+    }
+
+    r.invalidateEvent(ctx, file.EventID)
+    return nil
+}
+
+func (r *eventRepository) DeleteFile(ctx context.Context, eventID, fileID string) error {
+    err := r.db.WithContext(ctx).
+        Where("event_id = ? AND id = ?", eventID, fileID).
+        Delete(&model.File{}).Error
+    if err != nil {
+        return DBError(err)
+    }
+
+    r.invalidateEvent(ctx, eventID)
+    return nil
+}
+
+// ReorderFiles sets the position of each file to its index in fileIDs.
+func (r *eventRepository) ReorderFiles(ctx context.Context, eventID string, fileIDs []string) error {
+    err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+        for position, fileID := range fileIDs {
+            err := tx.Model(&model.File{}).
+                Where("event_id = ? AND id = ?", eventID, fileID).
+                Updates(map[string]interface{}{
+                    "position":   position,
+                    "updated_at": time.Now(),
+                }).Error
+            if err != nil {
+                return err
+            }
+        }
+        return nil
+    })
+    if err != nil {
+        return DBError(err)
+    }
+
+    r.invalidateEvent(ctx, eventID)
+    return nil
+}
+
+
+// checkSchedule refuses an event that overlaps another event at the same venue or
+// another event of the same creator. The check and the write that follows it have
+// to see the same schedule, so the creator and the venue are locked with
+// transaction-level advisory locks first: a concurrent write for either waits until
+// this transaction ends and then sees its event. The creator is always locked before
+// the venue so two writes cannot wait on each other.
+func checkSchedule(tx *gorm.DB, event *model.Event) error {
+    keys := []string{"event-schedule:creator:" + event.CreatorID}
+    if event.Venue != "" {
+        keys = append(keys, "event-schedule:venue:"+strings.ToLower(event.Venue))
+    }
+    for _, key := range keys {
+        if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", key).Error; err != nil {
+            return err
+        }
+    }
+
+    query := tx.Where("start_date < ? AND end_date > ?", event.EndDate, event.StartDate)
+    if event.ID != "" {
+        query = query.Where("id <> ?", event.ID)
+    }
+
+    owner := tx.Where("creator_id = ?", event.CreatorID)
+    if event.Venue != "" {
+        owner = owner.Or("LOWER(venue) = LOWER(?)", event.Venue)
+    }
+
+    var overlapping []*model.Event
+    if err := query.Where(owner).Order("start_date ASC").Find(&overlapping).Error; err != nil {
         return err
     }
-    
-    err = r.cache.Set(ctx, cacheKey, event, 30*time.Minute)
+
+    for _, other := range overlapping {
+        if event.Venue != "" && strings.EqualFold(other.Venue, event.Venue) {
+            return errs.NewConflictError(fmt.Sprintf("Venue is already booked by event %q", other.Title))
+        }
+        if other.CreatorID == event.CreatorID {
+            return errs.NewConflictError(fmt.Sprintf("Schedule overlaps with your event %q", other.Title))
+        }
+    }
+
+    return nil
+}
+
+// ListInRange returns every event overlapping the requested range, optionally
+// narrowed down to a venue or a creator.
+func (r *eventRepository) ListInRange(ctx context.Context, params *model.ConflictsInput) ([]*model.Event, error) {
+    var events []*model.Event
+
+    query := r.db.WithContext(ctx).
+        Where("start_date < ? AND end_date > ?", params.EndDate, params.StartDate)
+
+    if params.Venue != "" {
+        query = query.Where("LOWER(venue) = LOWER(?)", params.Venue)
+    }
+
+    if params.Creator != "" {
+        query = query.Where("creator_id = ?", params.Creator)
+    }
+
+    err := query.Order("start_date ASC").Find(&events).Error
+    if err != nil {
+        return nil, DBError(err)
+    }
+
+    return events, nil
+}
+
+// ListForCalendar returns every event overlapping [from, to) that matches the search
+// filters. Results are cached under the list prefix so writes invalidate them.
+func (r *eventRepository) ListForCalendar(ctx context.Context, params *model.SearchEventsInput, from, to time.Time) ([]*model.Event, error) {
+    var events []*model.Event
+    cacheKey := fmt.Sprintf("events:list:calendar:%s:%d:%d:%s:%s",
+        cacheScope(ctx), from.Unix(), to.Unix(), params.Query, params.Creator)
+
+    err := r.cache.Get(ctx, cacheKey, &events)
+    if err == nil && events != nil {
+        return events, nil
+    }
+
+    err = r.applySearchFilters(r.db.WithContext(ctx).Model(&model.Event{}), params).
+        Where("start_date < ? AND end_date > ?", to, from).
+        Order("start_date ASC").
+        Find(&events).Error
+    if err != nil {
+        return nil, DBError(err)
+    }
+
+    if events == nil {
+        events = []*model.Event{}
+    }
+
+    err = r.cache.Set(ctx, cacheKey, events, 5*time.Minute)
     if err != nil {
         log.Printf("%s: %v", CACHE_SET_FAIL, err)
-     }
+    }
 
-     listCacheKeys := "event:list:*"
-     keys, err := r.cache.Client.Keys(ctx, listCacheKeys).Result()
+    return events, nil
+}
 
-     if err != nil {
-        log.Printf("%s: %v", CACHE_KEYS_FAIL, err)
-     }
+// ListDeleted returns the creator's events that are currently in the trash.
+func (r *eventRepository) ListDeleted(ctx context.Context, creatorID string) ([]*model.Event, error) {
+    var events []*model.Event
+    err := r.db.WithContext(ctx).Unscoped().
+        Where("creator_id = ? AND deleted_at IS NOT NULL", creatorID).
+        Order("deleted_at DESC").
+        Find(&events).Error
+    if err != nil {
+        return nil, DBError(err)
+    }
+
+    return events, nil
+}
+
+func (r *eventRepository) GetDeletedByID(ctx context.Context, id string) (*model.Event, error) {
+    var event model.Event
+    err := r.db.WithContext(ctx).Unscoped().
+        Where("id = ? AND deleted_at IS NOT NULL", id).
+        First(&event).Error
+    if err != nil {
+        return nil, DBError(err)
+    }
 
-     for _, key := range keys {
-        err := r.cache.Delete(ctx, key)
+    return &event, nil
+}
+
+// Restore moves an event out of the trash and invalidates the list cache.
+func (r *eventRepository) Restore(ctx context.Context, id string) error {
+    err := r.db.WithContext(ctx).Unscoped().
+        Model(&model.Event{}).
+        Where("id = ?", id).
+        Update("deleted_at", nil).Error
+    if err != nil {
+        return DBError(err)
+    }
+
+    r.invalidateList(ctx)
+    return nil
+}
+
+// ListPurgeable returns trashed events deleted before the cutoff, with their files.
+// Events that ever sold a ticket are left out: they stay in the trash for good as
+// the record their orders, payments, invoices and refunds refer to.
+func (r *eventRepository) ListPurgeable(ctx context.Context, deletedBefore time.Time) ([]*model.Event, error) {
+    var events []*model.Event
+    err := r.db.WithContext(ctx).Unscoped().
+        Preload("Files").
+        Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore).
+        Where("NOT EXISTS (SELECT 1 FROM orders WHERE orders.event_id = events.id AND orders.status NOT IN ?)", unpaidOrderStatuses).
+        Find(&events).Error
+    if err != nil {
+        return nil, DBError(err)
+    }
+
+    return events, nil
+}
+
+// Purge permanently removes a trashed event together with its dependent rows.
+func (r *eventRepository) Purge(ctx context.Context, id string) error {
+    err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+        var sold int64
+        err := tx.Model(&model.Order{}).
+            Where("event_id = ? AND status NOT IN ?", id, unpaidOrderStatuses).
+            Count(&sold).Error
         if err != nil {
+            return err
+        }
+        if sold > 0 {
+            return errs.NewConflictError("Events that sold tickets are kept for their orders' records")
+        }
+
+        if err := tx.Where("event_id = ?", id).Delete(&model.File{}).Error; err != nil {
+            return err
+        }
+        if err := tx.Where("event_id = ?", id).Delete(&model.Bookmark{}).Error; err != nil {
+            return err
+        }
+        if err := tx.Where("event_id = ?", id).Delete(&model.TicketTransferAudit{}).Error; err != nil {
+            return err
+        }
+        if err := tx.Where("event_id = ?", id).Delete(&model.TicketTransfer{}).Error; err != nil {
+            return err
+        }
+        if err := tx.Where("event_id = ?", id).Delete(&model.Attendee{}).Error; err != nil {
+            return err
+        }
+        if err := tx.Where("event_id = ?", id).Delete(&model.Registration{}).Error; err != nil {
+            return err
+        }
+        if err := tx.Where("event_id = ?", id).Delete(&model.EventRevision{}).Error; err != nil {
+            return err
+        }
+        if err := tx.Where("event_id = ?", id).Delete(&model.EventTranslation{}).Error; err != nil {
+            return err
+        }
+        if err := tx.Where("event_id = ?", id).Delete(&model.RegistrationForm{}).Error; err != nil {
+            return err
+        }
+        if err := tx.Exec("DELETE FROM event_tags WHERE event_id = ?", id).Error; err != nil {
+            return err
+        }
+        if err := tx.Where("resource_type = ? AND target_id = ?", model.SlugResourceEvent, id).Delete(&model.SlugRedirect{}).Error; err != nil {
+            return err
+        }
+
+        // Only orders that were never paid are left, so there are no invoices,
+        // refunds or ledger entries for them; those still keyed on the event are
+        // removed for completeness.
+        orders := tx.Model(&model.Order{}).Select("id").Where("event_id = ?", id)
+        if err := tx.Where("order_id IN (?)", orders).Delete(&model.PromoRedemption{}).Error; err != nil {
+            return err
+        }
+        if err := tx.Where("order_id IN (?)", orders).Delete(&model.Invoice{}).Error; err != nil {
+            return err
+        }
+        if err := tx.Where("event_id = ?", id).Delete(&model.RefundLedgerEntry{}).Error; err != nil {
+            return err
+        }
+        if err := tx.Where("event_id = ?", id).Delete(&model.Refund{}).Error; err != nil {
+            return err
+        }
+        if err := tx.Where("order_id IN (?)", orders).Delete(&model.Payment{}).Error; err != nil {
+            return err
+        }
+        if err := tx.Where("order_id IN (?)", orders).Delete(&model.OrderItem{}).Error; err != nil {
+            return err
+        }
+        if err := tx.Where("event_id = ?", id).Delete(&model.Seat{}).Error; err != nil {
+            return err
+        }
+        if err := tx.Where("event_id = ?", id).Delete(&model.SeatRow{}).Error; err != nil {
+            return err
+        }
+        if err := tx.Where("event_id = ?", id).Delete(&model.SeatSection{}).Error; err != nil {
+            return err
+        }
+        if err := tx.Where("event_id = ?", id).Delete(&model.Order{}).Error; err != nil {
+            return err
+        }
+        if err := tx.Where("event_id = ?", id).Delete(&model.TicketType{}).Error; err != nil {
+            return err
+        }
+        if err := tx.Where("event_id = ?", id).Delete(&model.TicketLimit{}).Error; err != nil {
+            return err
+        }
+        if err := tx.Where("event_id = ?", id).Delete(&model.RefundPolicy{}).Error; err != nil {
+            return err
+        }
+        if err := tx.Where("event_id = ?", id).Delete(&model.TransferPolicy{}).Error; err != nil {
+            return err
+        }
+        if err := tx.Where("event_id = ?", id).Delete(&model.PromoCode{}).Error; err != nil {
+            return err
+        }
+        if err := tx.Where("event_id = ?", id).Delete(&model.EventDailyStat{}).Error; err != nil {
+            return err
+        }
+        if err := tx.Where("event_id = ? OR (subject_type = ? AND subject_id = ?)", id, model.RecommendationForEvent, id).Delete(&model.Recommendation{}).Error; err != nil {
+            return err
+        }
+        return tx.Unscoped().Where("deleted_at IS NOT NULL").Delete(&model.Event{}, "id = ?", id).Error
+    })
+    if err != nil {
+        return DBError(err)
+    }
+
+    return nil
+}
+
+// ResolveSlug returns the ID of the event with the slug, following the redirects
+// left by renames.
+func (r *eventRepository) ResolveSlug(ctx context.Context, slug string) (string, error) {
+    return resolveSlug(r.db.WithContext(ctx), model.SlugResourceEvent, slug)
+}
+
+// Cancel marks an event as cancelled. Cancelling an already cancelled event keeps
+// the original cancellation time.
+func (r *eventRepository) Cancel(ctx context.Context, id string, cancelledAt time.Time) error {
+    err := r.db.WithContext(ctx).
+        Model(&model.Event{}).
+        Where("id = ? AND cancelled_at IS NULL", id).
+        Updates(map[string]interface{}{
+            "cancelled_at": cancelledAt,
+            "updated_at":   cancelledAt,
+        }).Error
+    if err != nil {
+        return DBError(err)
+    }
+
+    r.invalidateEvent(ctx, id)
+    return nil
+}
+
+func (r *eventRepository) invalidateEvent(ctx context.Context, id string) {
+    if err := r.cache.Delete(ctx, fmt.Sprintf("event:%s", id)); err != nil {
+        log.Printf("%s: %v", CACHE_DELETE_FAIL, err)
+    }
+
+    r.invalidateList(ctx)
+}
+
+func (r *eventRepository) invalidateList(ctx context.Context) {
+    keys, err := r.cache.Client.Keys(ctx, "events:list:*").Result()
+    if err != nil {
+        log.Printf("%s: %v", CACHE_KEYS_FAIL, err)
+    }
+
+    for _, key := range keys {
+        if err := r.cache.Delete(ctx, key); err != nil {
             log.Printf("%s: %v", CACHE_DELETE_FAIL, err)
         }
-     }
+    }
+}
+
+// applySearchFilters narrows an event query by the free-text and creator filters of
+// a search. The text is matched against the content in every supported locale.
+func (r *eventRepository) applySearchFilters(query *gorm.DB, params *model.SearchEventsInput) *gorm.DB {
+    if params.Query != "" {
+        query = query.Where(matchSearch(r.db, r.catalog, params.Query))
+    }
+
+    if params.Creator != "" {
+        query = query.Where("creator_id = ?", params.Creator)
+    }
+
+    return query
+}
 
-     return nil
+// cacheScope names the organization whose events a cached list holds.
+func cacheScope(ctx context.Context) string {
+    if organizationID, ok := tenant.FromContext(ctx); ok {
+        return organizationID
+    }
+    return "system"
+}
+
+// createRevision numbers the revision after the latest one of the event and inserts it.
+// The event row is locked so concurrent updates cannot pick the same number.
+func createRevision(tx *gorm.DB, eventID string, revision *model.EventRevision) error {
+    if revision == nil {
+        return nil
+    }
+
+    if err := tx.Exec("SELECT 1 FROM events WHERE id = ? FOR UPDATE", eventID).Error; err != nil {
+        return err
+    }
+
+    var latest int
+    err := tx.Model(&model.EventRevision{}).
+        Where("event_id = ?", eventID).
+        Select("COALESCE(MAX(number), 0)").
+        Scan(&latest).Error
+    if err != nil {
+        return err
+    }
 
+    revision.EventID = eventID
+    revision.Number = latest + 1
+    return tx.Create(revision).Error
 }
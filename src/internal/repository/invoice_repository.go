@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hafiztri123/src/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type InvoiceRepository interface {
+	Create(ctx context.Context, invoice *model.Invoice) error
+	GetByOrder(ctx context.Context, orderID string) (*model.Invoice, error)
+	UpdateDocuments(ctx context.Context, id string, invoiceFile, receiptFile string) error
+}
+
+type invoiceRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewInvoiceRepository(db *gorm.DB) InvoiceRepository {
+	return &invoiceRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create allocates the organizer's next invoice number and stores the invoice in the
+// same transaction. The sequence row is locked by the increment, so concurrent
+// invoices queue up and a rolled back invoice gives its number back.
+func (r *invoiceRepositoryImpl) Create(ctx context.Context, invoice *model.Invoice) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&model.InvoiceSequence{OrganizerID: invoice.OrganizerID}).Error
+		if err != nil {
+			return err
+		}
+
+		var sequence model.InvoiceSequence
+		err = tx.Model(&sequence).
+			Clauses(clause.Returning{Columns: []clause.Column{{Name: "last_number"}}}).
+			Where("organizer_id = ?", invoice.OrganizerID).
+			Update("last_number", gorm.Expr("last_number + 1")).Error
+		if err != nil {
+			return err
+		}
+
+		invoice.Number = sequence.LastNumber
+		invoice.InvoiceNumber = fmt.Sprintf("INV-%06d", sequence.LastNumber)
+		return tx.Create(invoice).Error
+	})
+
+	return DBError(err)
+}
+
+func (r *invoiceRepositoryImpl) GetByOrder(ctx context.Context, orderID string) (*model.Invoice, error) {
+	var invoice model.Invoice
+	err := r.db.WithContext(ctx).Where("order_id = ?", orderID).First(&invoice).Error
+	if err != nil {
+		return nil, DBError(err)
+	}
+
+	return &invoice, nil
+}
+
+func (r *invoiceRepositoryImpl) UpdateDocuments(ctx context.Context, id string, invoiceFile, receiptFile string) error {
+	err := r.db.WithContext(ctx).
+		Model(&model.Invoice{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"invoice_url": invoiceFile,
+			"receipt_url": receiptFile,
+			"updated_at":  time.Now(),
+		}).Error
+	if err != nil {
+		return DBError(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,590 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hafiztri123/src/internal/model"
+	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OrderRepository interface {
+	CreateTicketType(ctx context.Context, ticketType *model.TicketType) error
+	ListTicketTypes(ctx context.Context, eventID string) ([]*model.TicketType, error)
+	GetTicketType(ctx context.Context, id string) (*model.TicketType, error)
+	GetTicketLimit(ctx context.Context, eventID string) (*model.TicketLimit, error)
+	SaveTicketLimit(ctx context.Context, limit *model.TicketLimit) error
+	CreatePending(ctx context.Context, order *model.Order, redemption *model.PromoRedemption, seatIDs []string, maxTickets int) error
+	GetByID(ctx context.Context, id string) (*model.Order, error)
+	ListByUser(ctx context.Context, userID string) ([]*model.Order, error)
+	ListByEvent(ctx context.Context, eventID string, status string) ([]*model.Order, error)
+	ListExpired(ctx context.Context, now time.Time) ([]*model.Order, error)
+	CreatePayment(ctx context.Context, payment *model.Payment) error
+	GetPaymentByProviderRef(ctx context.Context, provider, ref string) (*model.Payment, error)
+	MarkPaid(ctx context.Context, orderID string, paymentID string) (bool, error)
+	Release(ctx context.Context, orderID string, status string, paymentID string) error
+	ClaimPaymentRefund(ctx context.Context, paymentID string) (bool, error)
+	SetPaymentStatus(ctx context.Context, paymentID string, status string) error
+}
+
+type orderRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewOrderRepository(db *gorm.DB) OrderRepository {
+	return &orderRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *orderRepositoryImpl) CreateTicketType(ctx context.Context, ticketType *model.TicketType) error {
+	err := r.db.WithContext(ctx).Create(ticketType).Error
+	if err != nil {
+		return DBError(err)
+	}
+
+	return nil
+}
+
+func (r *orderRepositoryImpl) ListTicketTypes(ctx context.Context, eventID string) ([]*model.TicketType, error) {
+	var ticketTypes []*model.TicketType
+	err := r.db.WithContext(ctx).
+		Where("event_id = ?", eventID).
+		Order("price ASC, created_at ASC").
+		Find(&ticketTypes).Error
+	if err != nil {
+		return nil, DBError(err)
+	}
+
+	return ticketTypes, nil
+}
+
+func (r *orderRepositoryImpl) GetTicketType(ctx context.Context, id string) (*model.TicketType, error) {
+	var ticketType model.TicketType
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&ticketType).Error
+	if err != nil {
+		return nil, DBError(err)
+	}
+
+	return &ticketType, nil
+}
+
+func (r *orderRepositoryImpl) GetTicketLimit(ctx context.Context, eventID string) (*model.TicketLimit, error) {
+	var limit model.TicketLimit
+	err := r.db.WithContext(ctx).Where("event_id = ?", eventID).First(&limit).Error
+	if err != nil {
+		return nil, DBError(err)
+	}
+
+	return &limit, nil
+}
+
+func (r *orderRepositoryImpl) SaveTicketLimit(ctx context.Context, limit *model.TicketLimit) error {
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "event_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"max_per_user", "updated_at"}),
+		}).
+		Create(limit).Error
+	if err != nil {
+		return DBError(err)
+	}
+
+	return nil
+}
+
+// CreatePending reserves inventory for every item and stores the order in one
+// transaction. The reservation is a conditional increment, so concurrent checkouts
+// can never oversell a ticket type. A promo code redemption is checked against its
+// limits and counted in the same transaction, and so are the seats of a seated
+// order, which are only claimed if no other order has them. When maxTickets is
+// positive the buyer's pending and paid tickets for the event may not exceed it.
+func (r *orderRepositoryImpl) CreatePending(ctx context.Context, order *model.Order, redemption *model.PromoRedemption, seatIDs []string, maxTickets int) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if maxTickets > 0 {
+			if err := checkTicketLimit(tx, order, maxTickets); err != nil {
+				return err
+			}
+		}
+
+		if redemption != nil {
+			if err := redeemPromoCode(tx, redemption); err != nil {
+				return err
+			}
+		}
+
+		for _, item := range order.Items {
+			result := tx.Model(&model.TicketType{}).
+				Where("id = ? AND event_id = ?", item.TicketTypeID, order.EventID).
+				Where("quantity - sold - reserved >= ?", item.Quantity).
+				Updates(map[string]interface{}{
+					"reserved":   gorm.Expr("reserved + ?", item.Quantity),
+					"updated_at": time.Now(),
+				})
+			if result.Error != nil {
+				return result.Error
+			}
+
+			if result.RowsAffected == 0 {
+				return errs.NewConflictError("Not enough tickets left for ticket type " + item.TicketTypeID)
+			}
+		}
+
+		if err := tx.Create(order).Error; err != nil {
+			return err
+		}
+
+		if len(seatIDs) > 0 {
+			result := tx.Model(&model.Seat{}).
+				Where("id IN ? AND event_id = ? AND order_id IS NULL", seatIDs, order.EventID).
+				Update("order_id", order.ID)
+			if result.Error != nil {
+				return result.Error
+			}
+
+			if result.RowsAffected != int64(len(seatIDs)) {
+				return errs.NewConflictError("Some of the seats have already been booked")
+			}
+		}
+
+		if redemption != nil {
+			redemption.OrderID = order.ID
+			return tx.Create(redemption).Error
+		}
+
+		return nil
+	})
+
+	return DBError(err)
+}
+
+func (r *orderRepositoryImpl) GetByID(ctx context.Context, id string) (*model.Order, error) {
+	var order model.Order
+	err := r.db.WithContext(ctx).
+		Preload("Items").
+		Preload("Payments", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		Preload("Seats").
+		Where("id = ?", id).
+		First(&order).Error
+	if err != nil {
+		return nil, DBError(err)
+	}
+
+	return &order, nil
+}
+
+func (r *orderRepositoryImpl) ListByUser(ctx context.Context, userID string) ([]*model.Order, error) {
+	var orders []*model.Order
+	err := r.db.WithContext(ctx).
+		Preload("Items").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&orders).Error
+	if err != nil {
+		return nil, DBError(err)
+	}
+
+	return orders, nil
+}
+
+func (r *orderRepositoryImpl) ListByEvent(ctx context.Context, eventID string, status string) ([]*model.Order, error) {
+	var orders []*model.Order
+	err := r.db.WithContext(ctx).
+		Preload("Payments").
+		Where("event_id = ? AND status = ?", eventID, status).
+		Order("created_at ASC").
+		Find(&orders).Error
+	if err != nil {
+		return nil, DBError(err)
+	}
+
+	return orders, nil
+}
+
+// ListExpired returns pending orders whose reservation has run out.
+func (r *orderRepositoryImpl) ListExpired(ctx context.Context, now time.Time) ([]*model.Order, error) {
+	var orders []*model.Order
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND expires_at < ?", model.OrderStatusPending, now).
+		Find(&orders).Error
+	if err != nil {
+		return nil, DBError(err)
+	}
+
+	return orders, nil
+}
+
+func (r *orderRepositoryImpl) CreatePayment(ctx context.Context, payment *model.Payment) error {
+	err := r.db.WithContext(ctx).Create(payment).Error
+	if err != nil {
+		return DBError(err)
+	}
+
+	return nil
+}
+
+func (r *orderRepositoryImpl) GetPaymentByProviderRef(ctx context.Context, provider, ref string) (*model.Payment, error) {
+	var payment model.Payment
+	err := r.db.WithContext(ctx).
+		Where("provider = ? AND provider_ref = ?", provider, ref).
+		First(&payment).Error
+	if err != nil {
+		return nil, DBError(err)
+	}
+
+	return &payment, nil
+}
+
+// MarkPaid confirms a pending order: the payment is marked as succeeded, reserved
+// tickets become sold and the buyer is registered for the event. Orders for more
+// than one ticket make the registration a group booking with an attendee per
+// ticket. It reports whether a new registration was created and returns a
+// ConflictError if the order is no longer pending.
+func (r *orderRepositoryImpl) MarkPaid(ctx context.Context, orderID string, paymentID string) (bool, error) {
+	registered := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		if paymentID != "" {
+			err := tx.Model(&model.Payment{}).
+				Where("id = ?", paymentID).
+				Updates(map[string]interface{}{
+					"status":     model.PaymentStatusSucceeded,
+					"updated_at": now,
+				}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		result := tx.Model(&model.Order{}).
+			Where("id = ? AND status = ?", orderID, model.OrderStatusPending).
+			Updates(map[string]interface{}{
+				"status":     model.OrderStatusPaid,
+				"paid_at":    now,
+				"updated_at": now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return errs.NewConflictError("Order is no longer pending")
+		}
+
+		var order model.Order
+		if err := tx.Preload("Items").Preload("Seats").Where("id = ?", orderID).First(&order).Error; err != nil {
+			return err
+		}
+
+		for _, item := range order.Items {
+			err := tx.Model(&model.TicketType{}).
+				Where("id = ?", item.TicketTypeID).
+				Updates(map[string]interface{}{
+					"reserved":   gorm.Expr("reserved - ?", item.Quantity),
+					"sold":       gorm.Expr("sold + ?", item.Quantity),
+					"updated_at": now,
+				}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		registration := &model.Registration{
+			EventID:   order.EventID,
+			UserID:    order.UserID,
+			Answers:   order.Answers,
+			CreatedAt: now,
+		}
+
+		result = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(registration)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		registered = result.RowsAffected > 0
+		if !registered {
+			err := tx.Where("event_id = ? AND user_id = ?", order.EventID, order.UserID).
+				First(registration).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		return addAttendees(tx, registration, &order, registered, now)
+	})
+
+	return registered, DBError(err)
+}
+
+// Release moves a pending order to a terminal status and returns its reserved
+// tickets and seats to the pool. Orders that are no longer pending are left untouched.
+func (r *orderRepositoryImpl) Release(ctx context.Context, orderID string, status string, paymentID string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		if paymentID != "" {
+			err := tx.Model(&model.Payment{}).
+				Where("id = ?", paymentID).
+				Updates(map[string]interface{}{
+					"status":     model.PaymentStatusFailed,
+					"updated_at": now,
+				}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		result := tx.Model(&model.Order{}).
+			Where("id = ? AND status = ?", orderID, model.OrderStatusPending).
+			Updates(map[string]interface{}{
+				"status":     status,
+				"updated_at": now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		var items []model.OrderItem
+		if err := tx.Where("order_id = ?", orderID).Find(&items).Error; err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			err := tx.Model(&model.TicketType{}).
+				Where("id = ?", item.TicketTypeID).
+				Updates(map[string]interface{}{
+					"reserved":   gorm.Expr("reserved - ?", item.Quantity),
+					"updated_at": now,
+				}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := releaseSeats(tx, orderID); err != nil {
+			return err
+		}
+
+		return restorePromoCode(tx, orderID)
+	})
+
+	return DBError(err)
+}
+
+// ClaimPaymentRefund marks a payment as being refunded unless a refund of it has
+// already started, and reports whether this call claimed it.
+func (r *orderRepositoryImpl) ClaimPaymentRefund(ctx context.Context, paymentID string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&model.Payment{}).
+		Where("id = ? AND status NOT IN ?", paymentID, []string{model.PaymentStatusRefunding, model.PaymentStatusRefunded}).
+		Updates(map[string]interface{}{
+			"status":     model.PaymentStatusRefunding,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return false, DBError(result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (r *orderRepositoryImpl) SetPaymentStatus(ctx context.Context, paymentID string, status string) error {
+	err := r.db.WithContext(ctx).
+		Model(&model.Payment{}).
+		Where("id = ?", paymentID).
+		Updates(map[string]interface{}{
+			"status":     status,
+			"updated_at": time.Now(),
+		}).Error
+	if err != nil {
+		return DBError(err)
+	}
+
+	return nil
+}
+
+// checkTicketLimit locks the buyer so that their concurrent checkouts are counted
+// one after another, then checks the order against the event's per-user cap. It
+// counts the tickets the buyer actually holds, whatever order status they came
+// from: those reserved by pending orders plus those of their registration, one per
+// attendee of a group booking and one for a single ticket.
+func checkTicketLimit(tx *gorm.DB, order *model.Order, maxTickets int) error {
+	if err := tx.Exec("SELECT 1 FROM users WHERE id = ? FOR UPDATE", order.UserID).Error; err != nil {
+		return err
+	}
+
+	var reserved int64
+	err := tx.Model(&model.OrderItem{}).
+		Joins("JOIN orders ON orders.id = order_items.order_id").
+		Where("orders.event_id = ? AND orders.user_id = ?", order.EventID, order.UserID).
+		Where("orders.status = ?", model.OrderStatusPending).
+		Select("COALESCE(SUM(order_items.quantity), 0)").
+		Scan(&reserved).Error
+	if err != nil {
+		return err
+	}
+
+	var registered int64
+	err = tx.Model(&model.Registration{}).
+		Where("event_id = ? AND user_id = ?", order.EventID, order.UserID).
+		Select("COALESCE(SUM(GREATEST((SELECT COUNT(*) FROM attendees WHERE attendees.registration_id = registrations.id), 1)), 0)").
+		Scan(&registered).Error
+	if err != nil {
+		return err
+	}
+
+	held := reserved + registered
+
+	requested := 0
+	for _, item := range order.Items {
+		requested += item.Quantity
+	}
+
+	if held+int64(requested) > int64(maxTickets) {
+		return errs.NewConflictError(fmt.Sprintf("You can hold at most %d tickets for this event", maxTickets))
+	}
+
+	return nil
+}
+
+// addAttendees gives every ticket of a group order an attendee of its own, seated
+// orders one per seat. A registration that grows into a group keeps its earlier
+// ticket as an attendee, so a booking is either a single ticket or only attendees.
+func addAttendees(tx *gorm.DB, registration *model.Registration, order *model.Order, registered bool, now time.Time) error {
+	tickets := 0
+	for _, item := range order.Items {
+		tickets += item.Quantity
+	}
+
+	if registered && tickets < 2 {
+		return nil
+	}
+
+	var attendees []model.Attendee
+	if !registered {
+		var existing int64
+		err := tx.Model(&model.Attendee{}).
+			Where("registration_id = ?", registration.ID).
+			Count(&existing).Error
+		if err != nil {
+			return err
+		}
+
+		if existing == 0 {
+			attendees = append(attendees, model.Attendee{
+				EventID:        registration.EventID,
+				RegistrationID: registration.ID,
+				CheckedInAt:    registration.CheckedInAt,
+				CreatedAt:      now,
+				UpdatedAt:      now,
+			})
+		}
+	}
+
+	if len(order.Seats) > 0 {
+		for _, seat := range order.Seats {
+			attendees = append(attendees, model.Attendee{
+				EventID:        order.EventID,
+				RegistrationID: registration.ID,
+				OrderID:        &order.ID,
+				TicketTypeID:   &seat.TicketTypeID,
+				SeatID:         &seat.ID,
+				CreatedAt:      now,
+				UpdatedAt:      now,
+			})
+		}
+	} else {
+		for _, item := range order.Items {
+			for i := 0; i < item.Quantity; i++ {
+				attendees = append(attendees, model.Attendee{
+					EventID:        order.EventID,
+					RegistrationID: registration.ID,
+					OrderID:        &order.ID,
+					TicketTypeID:   &item.TicketTypeID,
+					CreatedAt:      now,
+					UpdatedAt:      now,
+				})
+			}
+		}
+	}
+
+	return tx.Create(&attendees).Error
+}
+
+// redeemPromoCode locks the promo code row so that the overall and per-user limits
+// are checked and counted without racing other checkouts.
+func redeemPromoCode(tx *gorm.DB, redemption *model.PromoRedemption) error {
+	var promo model.PromoCode
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", redemption.PromoCodeID).
+		First(&promo).Error
+	if err != nil {
+		return err
+	}
+
+	if promo.MaxUses > 0 && promo.UsedCount >= promo.MaxUses {
+		return errs.NewConflictError("Promo code has reached its usage limit")
+	}
+
+	if promo.MaxUsesPerUser > 0 {
+		var used int64
+		err := tx.Model(&model.PromoRedemption{}).
+			Where("promo_code_id = ? AND user_id = ?", promo.ID, redemption.UserID).
+			Count(&used).Error
+		if err != nil {
+			return err
+		}
+
+		if used >= int64(promo.MaxUsesPerUser) {
+			return errs.NewConflictError("You have already used this promo code")
+		}
+	}
+
+	return tx.Model(&model.PromoCode{}).
+		Where("id = ?", promo.ID).
+		Updates(map[string]interface{}{
+			"used_count": gorm.Expr("used_count + 1"),
+			"updated_at": time.Now(),
+		}).Error
+}
+
+// restorePromoCode gives back the promo code use of an order that was never paid.
+func restorePromoCode(tx *gorm.DB, orderID string) error {
+	var redemption model.PromoRedemption
+	result := tx.Where("order_id = ?", orderID).Limit(1).Find(&redemption)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil
+	}
+
+	if err := tx.Delete(&redemption).Error; err != nil {
+		return err
+	}
+
+	return tx.Model(&model.PromoCode{}).
+		Where("id = ? AND used_count > 0", redemption.PromoCodeID).
+		Updates(map[string]interface{}{
+			"used_count": gorm.Expr("used_count - 1"),
+			"updated_at": time.Now(),
+		}).Error
+}
+
+// releaseSeats frees the seats an order was holding.
+func releaseSeats(tx *gorm.DB, orderID string) error {
+	return tx.Model(&model.Seat{}).
+		Where("order_id = ?", orderID).
+		Update("order_id", nil).Error
+}
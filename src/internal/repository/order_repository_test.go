@@ -0,0 +1,169 @@
+package repository_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hafiztri123/src/internal/model"
+	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"github.com/hafiztri123/src/internal/repository"
+)
+
+func TestOrderRepositoryMarkPaid(t *testing.T) {
+	db := openTestDB(t)
+	orders := repository.NewOrderRepository(db)
+
+	tests := []struct {
+		name          string
+		tickets       int
+		wantAttendees int64
+	}{
+		{name: "single ticket", tickets: 1, wantAttendees: 0},
+		{name: "group booking", tickets: 3, wantAttendees: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newOrderFixture(t, db, tt.tickets)
+
+			registered, err := orders.MarkPaid(systemContext(), f.order.ID, f.payment.ID)
+			if err != nil {
+				t.Fatalf("MarkPaid() error = %v", err)
+			}
+			if !registered {
+				t.Error("MarkPaid() registered = false, want true")
+			}
+
+			order := f.reloadOrder(t)
+			if order.Status != model.OrderStatusPaid || order.PaidAt == nil {
+				t.Errorf("order status = %q, paid at = %v, want paid with a time", order.Status, order.PaidAt)
+			}
+			if payment := f.reloadPayment(t); payment.Status != model.PaymentStatusSucceeded {
+				t.Errorf("payment status = %q, want %q", payment.Status, model.PaymentStatusSucceeded)
+			}
+			f.assertTickets(t, tt.tickets, 0)
+
+			if got := f.count(t, &model.Registration{}, "event_id = ? AND user_id = ?", f.event.ID, f.user.ID); got != 1 {
+				t.Errorf("registrations = %d, want 1", got)
+			}
+			if got := f.count(t, &model.Attendee{}, "order_id = ?", f.order.ID); got != tt.wantAttendees {
+				t.Errorf("attendees = %d, want %d", got, tt.wantAttendees)
+			}
+		})
+	}
+}
+
+func TestOrderRepositoryMarkPaidTwice(t *testing.T) {
+	db := openTestDB(t)
+	orders := repository.NewOrderRepository(db)
+	f := newOrderFixture(t, db, 2)
+
+	if _, err := orders.MarkPaid(systemContext(), f.order.ID, f.payment.ID); err != nil {
+		t.Fatalf("MarkPaid() error = %v", err)
+	}
+
+	_, err := orders.MarkPaid(systemContext(), f.order.ID, f.payment.ID)
+	var conflictErr *errs.ConflictError
+	if !errors.As(err, &conflictErr) {
+		t.Fatalf("second MarkPaid() error = %v, want a ConflictError", err)
+	}
+
+	f.assertTickets(t, 2, 0)
+	if got := f.count(t, &model.Attendee{}, "order_id = ?", f.order.ID); got != 2 {
+		t.Errorf("attendees = %d, want 2", got)
+	}
+}
+
+func TestOrderRepositoryRelease(t *testing.T) {
+	db := openTestDB(t)
+	orders := repository.NewOrderRepository(db)
+
+	tests := []struct {
+		name        string
+		status      string
+		withPayment bool
+		wantPayment string
+	}{
+		{name: "expired without payment", status: model.OrderStatusExpired, wantPayment: model.PaymentStatusRequiresPayment},
+		{name: "failed payment", status: model.OrderStatusFailed, withPayment: true, wantPayment: model.PaymentStatusFailed},
+		{name: "cancelled", status: model.OrderStatusCancelled, wantPayment: model.PaymentStatusRequiresPayment},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newOrderFixture(t, db, 2)
+
+			paymentID := ""
+			if tt.withPayment {
+				paymentID = f.payment.ID
+			}
+
+			if err := orders.Release(systemContext(), f.order.ID, tt.status, paymentID); err != nil {
+				t.Fatalf("Release() error = %v", err)
+			}
+
+			if order := f.reloadOrder(t); order.Status != tt.status {
+				t.Errorf("order status = %q, want %q", order.Status, tt.status)
+			}
+			if payment := f.reloadPayment(t); payment.Status != tt.wantPayment {
+				t.Errorf("payment status = %q, want %q", payment.Status, tt.wantPayment)
+			}
+			f.assertTickets(t, 0, 0)
+
+			// Releasing again, as a repeated webhook or a second sweep would, must not
+			// hand the tickets back twice.
+			if err := orders.Release(systemContext(), f.order.ID, model.OrderStatusExpired, ""); err != nil {
+				t.Fatalf("second Release() error = %v", err)
+			}
+			if order := f.reloadOrder(t); order.Status != tt.status {
+				t.Errorf("order status after second release = %q, want %q", order.Status, tt.status)
+			}
+			f.assertTickets(t, 0, 0)
+		})
+	}
+}
+
+func TestOrderRepositoryReleaseLeavesPaidOrders(t *testing.T) {
+	db := openTestDB(t)
+	orders := repository.NewOrderRepository(db)
+	f := newOrderFixture(t, db, 2)
+
+	if _, err := orders.MarkPaid(systemContext(), f.order.ID, f.payment.ID); err != nil {
+		t.Fatalf("MarkPaid() error = %v", err)
+	}
+
+	if err := orders.Release(systemContext(), f.order.ID, model.OrderStatusExpired, f.payment.ID); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+
+	if order := f.reloadOrder(t); order.Status != model.OrderStatusPaid {
+		t.Errorf("order status = %q, want %q", order.Status, model.OrderStatusPaid)
+	}
+	f.assertTickets(t, 2, 0)
+}
+
+func TestOrderRepositoryClaimPaymentRefund(t *testing.T) {
+	db := openTestDB(t)
+	orders := repository.NewOrderRepository(db)
+	f := newOrderFixture(t, db, 1)
+
+	claimed, err := orders.ClaimPaymentRefund(systemContext(), f.payment.ID)
+	if err != nil {
+		t.Fatalf("ClaimPaymentRefund() error = %v", err)
+	}
+	if !claimed {
+		t.Fatal("ClaimPaymentRefund() = false, want true")
+	}
+
+	claimed, err = orders.ClaimPaymentRefund(systemContext(), f.payment.ID)
+	if err != nil {
+		t.Fatalf("second ClaimPaymentRefund() error = %v", err)
+	}
+	if claimed {
+		t.Error("second ClaimPaymentRefund() = true, want false")
+	}
+
+	if payment := f.reloadPayment(t); payment.Status != model.PaymentStatusRefunding {
+		t.Errorf("payment status = %q, want %q", payment.Status, model.PaymentStatusRefunding)
+	}
+}
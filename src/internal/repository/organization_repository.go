@@ -0,0 +1,258 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/hafiztri123/src/internal/model"
+	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"github.com/hafiztri123/src/internal/pkg/slug"
+	"github.com/hafiztri123/src/internal/pkg/tenant"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OrganizationRepository interface {
+	Create(ctx context.Context, organization *model.Organization, ownerID string) error
+	GetByID(ctx context.Context, id string) (*model.Organization, error)
+	Resolve(ctx context.Context, idOrSlug string) (*model.Organization, error)
+	ListByUser(ctx context.Context, userID string) ([]*model.MembershipView, error)
+	GetMember(ctx context.Context, organizationID, userID string) (*model.OrganizationMember, error)
+	ListMembers(ctx context.Context, organizationID string) ([]*model.OrganizationMember, error)
+	SaveMember(ctx context.Context, member *model.OrganizationMember) error
+	DeleteMember(ctx context.Context, organizationID, userID string) error
+}
+
+type organizationRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewOrganizationRepository(db *gorm.DB) OrganizationRepository {
+	return &organizationRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create inserts the organization with a slug made from its name and makes the user
+// its first owner.
+func (r *organizationRepositoryImpl) Create(ctx context.Context, organization *model.Organization, ownerID string) error {
+	err := withSlugRetry(func() error {
+		return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			slug, err := allocateSlug(tx, model.SlugResourceOrganization, "", organization.Name)
+			if err != nil {
+				return err
+			}
+			organization.Slug = slug
+
+			if err := tx.Create(organization).Error; err != nil {
+				return err
+			}
+
+			return tx.Create(&model.OrganizationMember{
+				OrganizationID: organization.ID,
+				UserID:         ownerID,
+				Role:           model.OrganizationRoleOwner,
+				CreatedAt:      organization.CreatedAt,
+				UpdatedAt:      organization.CreatedAt,
+			}).Error
+		})
+	})
+	if err != nil {
+		return DBError(err)
+	}
+
+	return nil
+}
+
+func (r *organizationRepositoryImpl) GetByID(ctx context.Context, id string) (*model.Organization, error) {
+	var organization model.Organization
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&organization).Error
+	if err != nil {
+		return nil, errs.NewNotFoundError("Organization not found")
+	}
+
+	return &organization, nil
+}
+
+// Resolve finds an organization by ID or slug.
+func (r *organizationRepositoryImpl) Resolve(ctx context.Context, idOrSlug string) (*model.Organization, error) {
+	query := r.db.WithContext(ctx).Where("slug = ?", idOrSlug)
+	if slug.IsID(idOrSlug) {
+		query = r.db.WithContext(ctx).Where("id = ?", idOrSlug)
+	}
+
+	var organization model.Organization
+	if err := query.First(&organization).Error; err != nil {
+		return nil, errs.NewNotFoundError("Organization not found")
+	}
+
+	return &organization, nil
+}
+
+func (r *organizationRepositoryImpl) ListByUser(ctx context.Context, userID string) ([]*model.MembershipView, error) {
+	var members []*model.OrganizationMember
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Find(&members).Error
+	if err != nil {
+		return nil, DBError(err)
+	}
+
+	ids := make([]string, 0, len(members))
+	for _, member := range members {
+		ids = append(ids, member.OrganizationID)
+	}
+
+	var organizations []*model.Organization
+	if len(ids) > 0 {
+		if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&organizations).Error; err != nil {
+			return nil, DBError(err)
+		}
+	}
+
+	byID := make(map[string]*model.Organization, len(organizations))
+	for _, organization := range organizations {
+		byID[organization.ID] = organization
+	}
+
+	views := make([]*model.MembershipView, 0, len(members))
+	for _, member := range members {
+		if organization, ok := byID[member.OrganizationID]; ok {
+			views = append(views, &model.MembershipView{Organization: organization, Role: member.Role})
+		}
+	}
+
+	return views, nil
+}
+
+func (r *organizationRepositoryImpl) GetMember(ctx context.Context, organizationID, userID string) (*model.OrganizationMember, error) {
+	var member model.OrganizationMember
+	err := r.db.WithContext(ctx).
+		Where("organization_id = ? AND user_id = ?", organizationID, userID).
+		First(&member).Error
+	if err != nil {
+		return nil, errs.NewNotFoundError("Member not found")
+	}
+
+	return &member, nil
+}
+
+func (r *organizationRepositoryImpl) ListMembers(ctx context.Context, organizationID string) ([]*model.OrganizationMember, error) {
+	var members []*model.OrganizationMember
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Where("organization_id = ?", organizationID).
+		Order("created_at ASC").
+		Find(&members).Error
+	if err != nil {
+		return nil, DBError(err)
+	}
+
+	return members, nil
+}
+
+// SaveMember adds the user to the organization or changes their role. A change that
+// would leave the organization without an owner is refused with a ConflictError.
+func (r *organizationRepositoryImpl) SaveMember(ctx context.Context, member *model.OrganizationMember) error {
+	err := r.withOwnerKept(ctx, member.OrganizationID, func(tx *gorm.DB) error {
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "organization_id"}, {Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"role", "updated_at"}),
+		}).Create(member).Error
+	})
+
+	return DBError(err)
+}
+
+// DeleteMember removes the user from the organization. Removing its last owner is
+// refused with a ConflictError.
+func (r *organizationRepositoryImpl) DeleteMember(ctx context.Context, organizationID, userID string) error {
+	err := r.withOwnerKept(ctx, organizationID, func(tx *gorm.DB) error {
+		result := tx.Where("organization_id = ? AND user_id = ?", organizationID, userID).
+			Delete(&model.OrganizationMember{})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return errs.NewNotFoundError("Member not found")
+		}
+
+		return nil
+	})
+
+	return DBError(err)
+}
+
+// withOwnerKept runs a change to the members of an organization and rolls it back
+// if no owner is left afterwards. The organization row stays locked until the end,
+// so concurrent changes run one after another and cannot each leave the other to
+// be the last owner.
+func (r *organizationRepositoryImpl) withOwnerKept(ctx context.Context, organizationID string, change func(tx *gorm.DB) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var organization model.Organization
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			Where("id = ?", organizationID).
+			First(&organization).Error
+		if err != nil {
+			return err
+		}
+
+		if err := change(tx); err != nil {
+			return err
+		}
+
+		var owners int64
+		err = tx.Model(&model.OrganizationMember{}).
+			Where("organization_id = ? AND role = ?", organizationID, model.OrganizationRoleOwner).
+			Count(&owners).Error
+		if err != nil {
+			return err
+		}
+		if owners == 0 {
+			return errs.NewConflictError("An organization needs at least one owner")
+		}
+
+		return nil
+	})
+}
+
+// BackfillOrganizations makes sure the default organization exists and moves every
+// event, category and file created before organizations existed into it. The
+// default organization is open so that any user can keep publishing in it.
+func BackfillOrganizations(db *gorm.DB, defaultSlug string) error {
+	db = db.WithContext(tenant.WithSystem(context.Background()))
+
+	var organization model.Organization
+	err := db.Where("slug = ?", defaultSlug).Limit(1).Find(&organization).Error
+	if err != nil {
+		return err
+	}
+
+	if organization.ID == "" {
+		now := time.Now()
+		organization = model.Organization{
+			Name:      "Default",
+			Slug:      defaultSlug,
+			Open:      true,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		if err := db.Create(&organization).Error; err != nil {
+			return err
+		}
+	}
+
+	for _, table := range []string{"events", "categories", "files"} {
+		err := db.Table(table).
+			Where("organization_id IS NULL").
+			Update("organization_id", organization.ID).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/hafiztri123/src/internal/model"
+	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"gorm.io/gorm"
+)
+
+type PasswordResetRepository interface {
+	Create(ctx context.Context, token *model.PasswordResetToken) error
+	GetByHash(ctx context.Context, hash string) (*model.PasswordResetToken, error)
+	Consume(ctx context.Context, token *model.PasswordResetToken, password string) (int, error)
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
+
+type passwordResetRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewPasswordResetRepository(db *gorm.DB) PasswordResetRepository {
+	return &passwordResetRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *passwordResetRepositoryImpl) Create(ctx context.Context, token *model.PasswordResetToken) error {
+	err := r.db.WithContext(ctx).Create(token).Error
+	if err != nil {
+		return DBError(err)
+	}
+
+	return nil
+}
+
+func (r *passwordResetRepositoryImpl) GetByHash(ctx context.Context, hash string) (*model.PasswordResetToken, error) {
+	var token model.PasswordResetToken
+	err := r.db.WithContext(ctx).Where("token_hash = ?", hash).First(&token).Error
+	if err != nil {
+		return nil, DBError(err)
+	}
+
+	return &token, nil
+}
+
+// Consume uses a reset token to set the user's new password. The token is only
+// taken if it is still unused and unexpired, so it works exactly once even under
+// concurrent requests; otherwise a ConflictError is returned. Every other
+// outstanding token of the user is used up with it. In the same transaction the
+// user is logged out everywhere: their refresh tokens are revoked and their token
+// generation is bumped. The new generation is returned.
+func (r *passwordResetRepositoryImpl) Consume(ctx context.Context, token *model.PasswordResetToken, password string) (int, error) {
+	var user model.User
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		result := tx.Model(&model.PasswordResetToken{}).
+			Where("id = ? AND used_at IS NULL AND expires_at > ?", token.ID, now).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return errs.NewConflictError("Reset link has already been used or has expired")
+		}
+
+		err := tx.Model(&model.PasswordResetToken{}).
+			Where("user_id = ? AND used_at IS NULL", token.UserID).
+			Update("used_at", now).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(&model.RefreshToken{}).
+			Where("user_id = ? AND revoked_at IS NULL", token.UserID).
+			Update("revoked_at", now).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(&model.User{}).Where("id = ?", token.UserID).Updates(map[string]interface{}{
+			"password":         password,
+			"version":          gorm.Expr("version + 1"),
+			"token_generation": gorm.Expr("token_generation + 1"),
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		token.UsedAt = &now
+		return tx.Select("token_generation").Where("id = ?", token.UserID).First(&user).Error
+	})
+
+	if err != nil {
+		return 0, DBError(err)
+	}
+
+	return user.TokenGeneration, nil
+}
+
+// DeleteExpired removes tokens that expired before the given time and reports how
+// many were removed.
+func (r *passwordResetRepositoryImpl) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("expires_at < ?", before).
+		Delete(&model.PasswordResetToken{})
+	if result.Error != nil {
+		return 0, DBError(result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
@@ -1,22 +1,33 @@
 package postgres
 
 import (
+	"context"
 	"log"
 
 	"github.com/hafiztri123/src/internal/model"
+	"github.com/hafiztri123/src/internal/pkg/i18n"
+	"github.com/hafiztri123/src/internal/pkg/tenant"
+	"github.com/hafiztri123/src/internal/repository"
 	"gorm.io/gorm"
 )
 
 
-func RunMigrations(db *gorm.DB)  {
+// RunMigrations runs outside of any organization since it touches every tenant's rows.
+func RunMigrations(db *gorm.DB, catalog *i18n.Catalog, defaultOrganization string)  {
+	db = db.WithContext(tenant.WithSystem(context.Background()))
+
 	setExtension(db)
 	migration(db)
+	dropIndexes(db)
+	partialIndexes(db)
+	backfill(db, defaultOrganization)
+	searchIndexes(db, catalog)
 }
 
 func setExtension(db *gorm.DB) {
 	result  := db.Exec("CREATE EXTENSION IF NOT EXISTS \"uuid-ossp\";")
 	if result.Error != nil {
-		log.Fatal("[FAIL] fail to set extension: %v ", result.Error)
+		log.Fatalf("[FAIL] fail to set extension: %v ", result.Error)
 	}
 }
 
@@ -27,8 +38,71 @@ func migration(db *gorm.DB) {
 		&model.Category{},
 		&model.Tag{},
 		&model.File{},
+		&model.Registration{},
+		&model.EventRevision{},
+		&model.TicketType{},
+		&model.Order{},
+		&model.OrderItem{},
+		&model.Payment{},
+		&model.RefundPolicy{},
+		&model.Refund{},
+		&model.RefundLedgerEntry{},
+		&model.PromoCode{},
+		&model.PromoRedemption{},
+		&model.Invoice{},
+		&model.InvoiceSequence{},
+		&model.EventDailyStat{},
+		&model.Bookmark{},
+		&model.Recommendation{},
+		&model.SlugRedirect{},
+		&model.EventTranslation{},
+		&model.CategoryTranslation{},
+		&model.Organization{},
+		&model.OrganizationMember{},
+		&model.RegistrationForm{},
+		&model.SeatSection{},
+		&model.SeatRow{},
+		&model.Seat{},
+		&model.TransferPolicy{},
+		&model.TicketTransfer{},
+		&model.TicketTransferAudit{},
+		&model.TicketLimit{},
+		&model.Attendee{},
+		&model.RefreshToken{},
+		&model.PasswordResetToken{},
 	)
 	if err != nil {
 		log.Fatal("[FAIL] fail to migrate")
 	}
 }
+
+// dropIndexes removes indexes that have been replaced by differently named ones.
+func dropIndexes(db *gorm.DB) {
+	if err := db.Exec("DROP INDEX IF EXISTS idx_categories_name").Error; err != nil {
+		log.Fatalf("[FAIL] fail to drop indexes: %v", err)
+	}
+}
+
+// partialIndexes creates unique indexes whose condition the gorm tags cannot express.
+func partialIndexes(db *gorm.DB) {
+	// An order has at most one refund waiting or being paid out at a time.
+	err := db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_refunds_open_order ON refunds (order_id) WHERE status IN ('requested', 'processing')").Error
+	if err != nil {
+		log.Fatalf("[FAIL] fail to create partial indexes: %v", err)
+	}
+}
+
+func backfill(db *gorm.DB, defaultOrganization string) {
+	if err := repository.BackfillSlugs(db); err != nil {
+		log.Fatalf("[FAIL] fail to backfill slugs: %v", err)
+	}
+	if err := repository.BackfillOrganizations(db, defaultOrganization); err != nil {
+		log.Fatalf("[FAIL] fail to backfill organizations: %v", err)
+	}
+}
+
+func searchIndexes(db *gorm.DB, catalog *i18n.Catalog) {
+	if err := repository.CreateSearchIndexes(db, catalog); err != nil {
+		log.Fatalf("[FAIL] fail to create search indexes: %v", err)
+	}
+}
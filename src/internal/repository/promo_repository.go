@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hafiztri123/src/internal/model"
+	"gorm.io/gorm"
+)
+
+type PromoRepository interface {
+	Create(ctx context.Context, promo *model.PromoCode) error
+	GetByID(ctx context.Context, id string) (*model.PromoCode, error)
+	ListByOrganizer(ctx context.Context, organizerID string) ([]*model.PromoCode, error)
+	FindForEvent(ctx context.Context, code string, eventID string, organizerID string) (*model.PromoCode, error)
+	CountRedemptions(ctx context.Context, promoID string, userID string) (int64, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type promoRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewPromoRepository(db *gorm.DB) PromoRepository {
+	return &promoRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *promoRepositoryImpl) Create(ctx context.Context, promo *model.PromoCode) error {
+	err := r.db.WithContext(ctx).Create(promo).Error
+	if err != nil {
+		return DBError(err)
+	}
+
+	return nil
+}
+
+func (r *promoRepositoryImpl) GetByID(ctx context.Context, id string) (*model.PromoCode, error) {
+	var promo model.PromoCode
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&promo).Error
+	if err != nil {
+		return nil, DBError(err)
+	}
+
+	return &promo, nil
+}
+
+func (r *promoRepositoryImpl) ListByOrganizer(ctx context.Context, organizerID string) ([]*model.PromoCode, error) {
+	var promos []*model.PromoCode
+	err := r.db.WithContext(ctx).
+		Where("organizer_id = ?", organizerID).
+		Order("created_at DESC").
+		Find(&promos).Error
+	if err != nil {
+		return nil, DBError(err)
+	}
+
+	return promos, nil
+}
+
+// FindForEvent looks up a code that is valid for the event, either because it is
+// scoped to the event or because it covers all events of the organizer.
+func (r *promoRepositoryImpl) FindForEvent(ctx context.Context, code string, eventID string, organizerID string) (*model.PromoCode, error) {
+	var promo model.PromoCode
+	err := r.db.WithContext(ctx).
+		Where("code = ? AND organizer_id = ?", strings.ToUpper(code), organizerID).
+		Where("event_id = ? OR event_id IS NULL", eventID).
+		First(&promo).Error
+	if err != nil {
+		return nil, DBError(err)
+	}
+
+	return &promo, nil
+}
+
+func (r *promoRepositoryImpl) CountRedemptions(ctx context.Context, promoID string, userID string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&model.PromoRedemption{}).
+		Where("promo_code_id = ? AND user_id = ?", promoID, userID).
+		Count(&count).Error
+	if err != nil {
+		return 0, DBError(err)
+	}
+
+	return count, nil
+}
+
+func (r *promoRepositoryImpl) Delete(ctx context.Context, id string) error {
+	err := r.db.WithContext(ctx).Where("id = ?", id).Delete(&model.PromoCode{}).Error
+	if err != nil {
+		return DBError(err)
+	}
+
+	return nil
+}
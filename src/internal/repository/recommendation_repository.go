@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/hafiztri123/src/internal/model"
+	"gorm.io/gorm"
+)
+
+type RecommendationRepository interface {
+	ListUpcomingEvents(ctx context.Context, now time.Time) ([]*model.Event, error)
+	ListEventsByIDs(ctx context.Context, ids []string) ([]*model.Event, error)
+	ListAttendance(ctx context.Context, since time.Time) ([]model.Attendance, error)
+	ListCoAttendance(ctx context.Context, targetIDs []string) ([]model.CoAttendance, error)
+	Replace(ctx context.Context, subjectType string, recommendations []*model.Recommendation) error
+	List(ctx context.Context, subjectType, subjectID string) ([]*model.Recommendation, error)
+}
+
+type recommendationRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewRecommendationRepository(db *gorm.DB) RecommendationRepository {
+	return &recommendationRepositoryImpl{
+		db: db,
+	}
+}
+
+// ListUpcomingEvents returns the events that have not started yet and are not
+// cancelled, with their tags.
+func (r *recommendationRepositoryImpl) ListUpcomingEvents(ctx context.Context, now time.Time) ([]*model.Event, error) {
+	var events []*model.Event
+	err := r.db.WithContext(ctx).
+		Preload("Tags").
+		Where("start_date > ? AND cancelled_at IS NULL", now).
+		Find(&events).Error
+	if err != nil {
+		return nil, DBError(err)
+	}
+
+	return events, nil
+}
+
+func (r *recommendationRepositoryImpl) ListEventsByIDs(ctx context.Context, ids []string) ([]*model.Event, error) {
+	var events []*model.Event
+	if len(ids) == 0 {
+		return events, nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Preload("Tags").
+		Where("id IN ?", ids).
+		Find(&events).Error
+	if err != nil {
+		return nil, DBError(err)
+	}
+
+	return events, nil
+}
+
+// ListAttendance returns the registrations for events that ended after since.
+func (r *recommendationRepositoryImpl) ListAttendance(ctx context.Context, since time.Time) ([]model.Attendance, error) {
+	var attendance []model.Attendance
+	err := r.db.WithContext(ctx).
+		Table("registrations").
+		Select("registrations.user_id, registrations.event_id").
+		Joins("JOIN events ON events.id = registrations.event_id AND events.deleted_at IS NULL").
+		Where("events.end_date > ?", since).
+		Scan(&attendance).Error
+	if err != nil {
+		return nil, DBError(err)
+	}
+
+	return attendance, nil
+}
+
+// ListCoAttendance counts, for every event and each of the target events, how many
+// users are registered for both.
+func (r *recommendationRepositoryImpl) ListCoAttendance(ctx context.Context, targetIDs []string) ([]model.CoAttendance, error) {
+	var pairs []model.CoAttendance
+	if len(targetIDs) == 0 {
+		return pairs, nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Table("registrations AS source").
+		Select("source.event_id AS source_id, target.event_id AS target_id, COUNT(*) AS shared").
+		Joins("JOIN registrations AS target ON target.user_id = source.user_id AND target.event_id <> source.event_id").
+		Where("target.event_id IN ?", targetIDs).
+		Group("source.event_id, target.event_id").
+		Scan(&pairs).Error
+	if err != nil {
+		return nil, DBError(err)
+	}
+
+	return pairs, nil
+}
+
+// Replace swaps all the recommendations of a subject type for a freshly computed
+// set in one transaction, so readers never see a partial result.
+func (r *recommendationRepositoryImpl) Replace(ctx context.Context, subjectType string, recommendations []*model.Recommendation) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("subject_type = ?", subjectType).Delete(&model.Recommendation{}).Error; err != nil {
+			return err
+		}
+
+		if len(recommendations) == 0 {
+			return nil
+		}
+
+		return tx.CreateInBatches(recommendations, 500).Error
+	})
+	if err != nil {
+		return DBError(err)
+	}
+
+	return nil
+}
+
+func (r *recommendationRepositoryImpl) List(ctx context.Context, subjectType, subjectID string) ([]*model.Recommendation, error) {
+	var recommendations []*model.Recommendation
+	err := r.db.WithContext(ctx).
+		Where("subject_type = ? AND subject_id = ?", subjectType, subjectID).
+		Order("score DESC").
+		Find(&recommendations).Error
+	if err != nil {
+		return nil, DBError(err)
+	}
+
+	return recommendations, nil
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/hafiztri123/src/internal/model"
+	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"gorm.io/gorm"
+)
+
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *model.RefreshToken) error
+	GetByHash(ctx context.Context, hash string) (*model.RefreshToken, error)
+	Rotate(ctx context.Context, used *model.RefreshToken, next *model.RefreshToken) error
+	RevokeFamily(ctx context.Context, familyID string) error
+	RevokeUser(ctx context.Context, userID string) error
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
+
+type refreshTokenRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewRefreshTokenRepository(db *gorm.DB) RefreshTokenRepository {
+	return &refreshTokenRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *refreshTokenRepositoryImpl) Create(ctx context.Context, token *model.RefreshToken) error {
+	err := r.db.WithContext(ctx).Create(token).Error
+	if err != nil {
+		return DBError(err)
+	}
+
+	return nil
+}
+
+func (r *refreshTokenRepositoryImpl) GetByHash(ctx context.Context, hash string) (*model.RefreshToken, error) {
+	var token model.RefreshToken
+	err := r.db.WithContext(ctx).Where("token_hash = ?", hash).First(&token).Error
+	if err != nil {
+		return nil, DBError(err)
+	}
+
+	return &token, nil
+}
+
+// Rotate marks a token as used and stores its successor in one transaction. The
+// token is only marked if it is still unused and not revoked, so two concurrent
+// refreshes with the same token cannot both succeed; the loser gets a ConflictError.
+func (r *refreshTokenRepositoryImpl) Rotate(ctx context.Context, used *model.RefreshToken, next *model.RefreshToken) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		result := tx.Model(&model.RefreshToken{}).
+			Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", used.ID).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return errs.NewConflictError("Refresh token has already been used")
+		}
+
+		used.UsedAt = &now
+		return tx.Create(next).Error
+	})
+
+	return DBError(err)
+}
+
+// RevokeFamily revokes every token that descends from the same login.
+func (r *refreshTokenRepositoryImpl) RevokeFamily(ctx context.Context, familyID string) error {
+	err := r.db.WithContext(ctx).
+		Model(&model.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+	if err != nil {
+		return DBError(err)
+	}
+
+	return nil
+}
+
+// RevokeUser revokes every token of the user.
+func (r *refreshTokenRepositoryImpl) RevokeUser(ctx context.Context, userID string) error {
+	err := r.db.WithContext(ctx).
+		Model(&model.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+	if err != nil {
+		return DBError(err)
+	}
+
+	return nil
+}
+
+// DeleteExpired removes tokens that expired before the given time and reports how
+// many were removed.
+func (r *refreshTokenRepositoryImpl) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("expires_at < ?", before).
+		Delete(&model.RefreshToken{})
+	if result.Error != nil {
+		return 0, DBError(result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
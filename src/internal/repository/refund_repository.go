@@ -0,0 +1,330 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/hafiztri123/src/internal/model"
+	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RefundRepository interface {
+	GetPolicy(ctx context.Context, eventID string) (*model.RefundPolicy, error)
+	SavePolicy(ctx context.Context, policy *model.RefundPolicy) error
+	Create(ctx context.Context, refund *model.Refund, actorID string) error
+	GetByID(ctx context.Context, id string) (*model.Refund, error)
+	ListByOrder(ctx context.Context, orderID string) ([]*model.Refund, error)
+	ListByEvent(ctx context.Context, eventID string) ([]*model.Refund, error)
+	ListLedger(ctx context.Context, eventID string) ([]*model.RefundLedgerEntry, error)
+	Claim(ctx context.Context, id string) error
+	Reject(ctx context.Context, refund *model.Refund, actorID string, note string) error
+	Complete(ctx context.Context, refund *model.Refund, actorID string, providerRef string) error
+	Fail(ctx context.Context, refund *model.Refund, actorID string, note string) error
+}
+
+type refundRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewRefundRepository(db *gorm.DB) RefundRepository {
+	return &refundRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *refundRepositoryImpl) GetPolicy(ctx context.Context, eventID string) (*model.RefundPolicy, error) {
+	var policy model.RefundPolicy
+	err := r.db.WithContext(ctx).Where("event_id = ?", eventID).First(&policy).Error
+	if err != nil {
+		return nil, DBError(err)
+	}
+
+	return &policy, nil
+}
+
+func (r *refundRepositoryImpl) SavePolicy(ctx context.Context, policy *model.RefundPolicy) error {
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "event_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"tiers", "updated_at"}),
+		}).
+		Create(policy).Error
+	if err != nil {
+		return DBError(err)
+	}
+
+	return nil
+}
+
+// Create stores a refund request together with its opening ledger entry. The
+// database allows one open refund per order, so of two concurrent requests for
+// the same order one fails with a ConflictError.
+func (r *refundRepositoryImpl) Create(ctx context.Context, refund *model.Refund, actorID string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(refund).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(newLedgerEntry(refund, model.LedgerEntryRequested, actorID, refund.Reason)).Error
+	})
+
+	err = DBError(err)
+	var duplicateErr *errs.DuplicateEntryError
+	if errors.As(err, &duplicateErr) {
+		return errs.NewConflictError("A refund for this order is already in progress")
+	}
+
+	return err
+}
+
+func (r *refundRepositoryImpl) GetByID(ctx context.Context, id string) (*model.Refund, error) {
+	var refund model.Refund
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&refund).Error
+	if err != nil {
+		return nil, DBError(err)
+	}
+
+	return &refund, nil
+}
+
+func (r *refundRepositoryImpl) ListByOrder(ctx context.Context, orderID string) ([]*model.Refund, error) {
+	var refunds []*model.Refund
+	err := r.db.WithContext(ctx).
+		Where("order_id = ?", orderID).
+		Order("created_at DESC").
+		Find(&refunds).Error
+	if err != nil {
+		return nil, DBError(err)
+	}
+
+	return refunds, nil
+}
+
+func (r *refundRepositoryImpl) ListByEvent(ctx context.Context, eventID string) ([]*model.Refund, error) {
+	var refunds []*model.Refund
+	err := r.db.WithContext(ctx).
+		Where("event_id = ?", eventID).
+		Order("created_at DESC").
+		Find(&refunds).Error
+	if err != nil {
+		return nil, DBError(err)
+	}
+
+	return refunds, nil
+}
+
+func (r *refundRepositoryImpl) ListLedger(ctx context.Context, eventID string) ([]*model.RefundLedgerEntry, error) {
+	var entries []*model.RefundLedgerEntry
+	err := r.db.WithContext(ctx).
+		Where("event_id = ?", eventID).
+		Order("created_at ASC").
+		Find(&entries).Error
+	if err != nil {
+		return nil, DBError(err)
+	}
+
+	return entries, nil
+}
+
+// Claim moves a requested refund to processing so that only one caller sends it to
+// the payment provider.
+func (r *refundRepositoryImpl) Claim(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).
+		Model(&model.Refund{}).
+		Where("id = ? AND status = ?", id, model.RefundStatusRequested).
+		Updates(map[string]interface{}{
+			"status":     model.RefundStatusProcessing,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return DBError(result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return errs.NewConflictError("Refund has already been processed")
+	}
+
+	return nil
+}
+
+func (r *refundRepositoryImpl) Reject(ctx context.Context, refund *model.Refund, actorID string, note string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&model.Refund{}).
+			Where("id = ? AND status = ?", refund.ID, model.RefundStatusRequested).
+			Updates(map[string]interface{}{
+				"status":       model.RefundStatusRejected,
+				"processed_at": now,
+				"updated_at":   now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return errs.NewConflictError("Refund has already been processed")
+		}
+
+		refund.Status = model.RefundStatusRejected
+		refund.ProcessedAt = &now
+		return tx.Create(newLedgerEntry(refund, model.LedgerEntryRejected, actorID, note)).Error
+	})
+
+	return DBError(err)
+}
+
+// Complete records a refund the provider has paid out. The order leaves the paid
+// state, its tickets and seats go back to the pool and the buyer's registration is dropped
+// unless they still hold another paid order for the event. The order counts as
+// refunded once its refunds add up to its total, so paying back the rest of a
+// partially refunded order completes it.
+func (r *refundRepositoryImpl) Complete(ctx context.Context, refund *model.Refund, actorID string, providerRef string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		err := tx.Model(&model.Refund{}).
+			Where("id = ?", refund.ID).
+			Updates(map[string]interface{}{
+				"status":       model.RefundStatusSucceeded,
+				"provider_ref": providerRef,
+				"processed_at": now,
+				"updated_at":   now,
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		var order model.Order
+		if err := tx.Preload("Items").Where("id = ?", refund.OrderID).First(&order).Error; err != nil {
+			return err
+		}
+
+		var refunded int64
+		err = tx.Model(&model.Refund{}).
+			Where("order_id = ? AND status = ?", order.ID, model.RefundStatusSucceeded).
+			Select("COALESCE(SUM(amount), 0)").
+			Scan(&refunded).Error
+		if err != nil {
+			return err
+		}
+
+		status := model.OrderStatusRefunded
+		if refunded < order.TotalAmount {
+			status = model.OrderStatusPartiallyRefunded
+		}
+
+		// A partially refunded order has already given up its tickets; paying back
+		// the rest only changes its status.
+		err = tx.Model(&model.Order{}).
+			Where("id = ? AND status = ?", order.ID, model.OrderStatusPartiallyRefunded).
+			Updates(map[string]interface{}{
+				"status":     status,
+				"updated_at": now,
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		result := tx.Model(&model.Order{}).
+			Where("id = ? AND status = ?", order.ID, model.OrderStatusPaid).
+			Updates(map[string]interface{}{
+				"status":     status,
+				"updated_at": now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected > 0 {
+			for _, item := range order.Items {
+				err := tx.Model(&model.TicketType{}).
+					Where("id = ?", item.TicketTypeID).
+					Updates(map[string]interface{}{
+						"sold":       gorm.Expr("sold - ?", item.Quantity),
+						"updated_at": now,
+					}).Error
+				if err != nil {
+					return err
+				}
+			}
+
+			if err := releaseSeats(tx, order.ID); err != nil {
+				return err
+			}
+
+			if err := tx.Where("order_id = ?", order.ID).Delete(&model.Attendee{}).Error; err != nil {
+				return err
+			}
+
+			var remaining int64
+			err := tx.Model(&model.Order{}).
+				Where("event_id = ? AND user_id = ? AND status = ?", order.EventID, order.UserID, model.OrderStatusPaid).
+				Count(&remaining).Error
+			if err != nil {
+				return err
+			}
+
+			if remaining == 0 {
+				err := tx.Where("registration_id IN (?)", tx.Model(&model.Registration{}).
+					Select("id").
+					Where("event_id = ? AND user_id = ?", order.EventID, order.UserID)).
+					Delete(&model.Attendee{}).Error
+				if err != nil {
+					return err
+				}
+
+				err = tx.Where("event_id = ? AND user_id = ?", order.EventID, order.UserID).
+					Delete(&model.Registration{}).Error
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		refund.Status = model.RefundStatusSucceeded
+		refund.ProviderRef = providerRef
+		refund.ProcessedAt = &now
+		return tx.Create(newLedgerEntry(refund, model.LedgerEntryRefunded, actorID, providerRef)).Error
+	})
+
+	return DBError(err)
+}
+
+// Fail records that the provider rejected a refund. The refund can be requested again.
+func (r *refundRepositoryImpl) Fail(ctx context.Context, refund *model.Refund, actorID string, note string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		err := tx.Model(&model.Refund{}).
+			Where("id = ?", refund.ID).
+			Updates(map[string]interface{}{
+				"status":       model.RefundStatusFailed,
+				"processed_at": now,
+				"updated_at":   now,
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		refund.Status = model.RefundStatusFailed
+		refund.ProcessedAt = &now
+		return tx.Create(newLedgerEntry(refund, model.LedgerEntryFailed, actorID, note)).Error
+	})
+
+	return DBError(err)
+}
+
+func newLedgerEntry(refund *model.Refund, entryType string, actorID string, note string) *model.RefundLedgerEntry {
+	return &model.RefundLedgerEntry{
+		RefundID:  refund.ID,
+		OrderID:   refund.OrderID,
+		EventID:   refund.EventID,
+		Type:      entryType,
+		Amount:    refund.Amount,
+		Currency:  refund.Currency,
+		ActorID:   actorID,
+		Note:      note,
+		CreatedAt: time.Now(),
+	}
+}
@@ -0,0 +1,234 @@
+package repository_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hafiztri123/src/internal/model"
+	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"github.com/hafiztri123/src/internal/repository"
+)
+
+// newRefund stores a refund of amount for the fixture's order, already claimed for
+// payout, the way the refund service and the late-payment path record it.
+func newRefund(t *testing.T, refunds repository.RefundRepository, f *orderFixture, amount int64) *model.Refund {
+	t.Helper()
+
+	now := time.Now()
+	refund := &model.Refund{
+		OrderID:   f.order.ID,
+		EventID:   f.event.ID,
+		UserID:    f.user.ID,
+		PaymentID: &f.payment.ID,
+		Amount:    amount,
+		Currency:  f.order.Currency,
+		Percent:   int(amount * 100 / f.order.TotalAmount),
+		Reason:    "Test refund",
+		Status:    model.RefundStatusProcessing,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := refunds.Create(systemContext(), refund, f.user.ID); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	return refund
+}
+
+func TestRefundRepositoryComplete(t *testing.T) {
+	db := openTestDB(t)
+	orders := repository.NewOrderRepository(db)
+	refunds := repository.NewRefundRepository(db)
+
+	tests := []struct {
+		name       string
+		tickets    int
+		refunded   int64
+		wantStatus string
+	}{
+		{name: "full refund of a single ticket", tickets: 1, refunded: 5000, wantStatus: model.OrderStatusRefunded},
+		{name: "full refund of a group booking", tickets: 3, refunded: 15000, wantStatus: model.OrderStatusRefunded},
+		{name: "partial refund", tickets: 2, refunded: 5000, wantStatus: model.OrderStatusPartiallyRefunded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newOrderFixture(t, db, tt.tickets)
+			if _, err := orders.MarkPaid(systemContext(), f.order.ID, f.payment.ID); err != nil {
+				t.Fatalf("MarkPaid() error = %v", err)
+			}
+
+			refund := newRefund(t, refunds, f, tt.refunded)
+			if err := refunds.Complete(systemContext(), refund, f.user.ID, "re_1"); err != nil {
+				t.Fatalf("Complete() error = %v", err)
+			}
+
+			if order := f.reloadOrder(t); order.Status != tt.wantStatus {
+				t.Errorf("order status = %q, want %q", order.Status, tt.wantStatus)
+			}
+			if refund.Status != model.RefundStatusSucceeded || refund.ProcessedAt == nil {
+				t.Errorf("refund status = %q, processed at = %v, want succeeded with a time", refund.Status, refund.ProcessedAt)
+			}
+
+			// Any refund gives up the order's tickets and the buyer's booking.
+			f.assertTickets(t, 0, 0)
+			if got := f.count(t, &model.Registration{}, "event_id = ? AND user_id = ?", f.event.ID, f.user.ID); got != 0 {
+				t.Errorf("registrations = %d, want 0", got)
+			}
+			if got := f.count(t, &model.Attendee{}, "order_id = ?", f.order.ID); got != 0 {
+				t.Errorf("attendees = %d, want 0", got)
+			}
+
+			if got := f.count(t, &model.RefundLedgerEntry{}, "refund_id = ?", refund.ID); got != 2 {
+				t.Errorf("ledger entries = %d, want 2", got)
+			}
+		})
+	}
+}
+
+func TestRefundRepositoryCompleteRest(t *testing.T) {
+	db := openTestDB(t)
+	orders := repository.NewOrderRepository(db)
+	refunds := repository.NewRefundRepository(db)
+	f := newOrderFixture(t, db, 2)
+
+	if _, err := orders.MarkPaid(systemContext(), f.order.ID, f.payment.ID); err != nil {
+		t.Fatalf("MarkPaid() error = %v", err)
+	}
+
+	first := newRefund(t, refunds, f, 4000)
+	if err := refunds.Complete(systemContext(), first, f.user.ID, "re_1"); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+
+	rest := newRefund(t, refunds, f, 6000)
+	if err := refunds.Complete(systemContext(), rest, f.user.ID, "re_2"); err != nil {
+		t.Fatalf("second Complete() error = %v", err)
+	}
+
+	if order := f.reloadOrder(t); order.Status != model.OrderStatusRefunded {
+		t.Errorf("order status = %q, want %q", order.Status, model.OrderStatusRefunded)
+	}
+	// The tickets went back with the first refund and must not be counted twice.
+	f.assertTickets(t, 0, 0)
+}
+
+func TestRefundRepositoryKeepsRegistrationOfOtherPaidOrder(t *testing.T) {
+	db := openTestDB(t)
+	orders := repository.NewOrderRepository(db)
+	refunds := repository.NewRefundRepository(db)
+	f := newOrderFixture(t, db, 1)
+
+	if _, err := orders.MarkPaid(systemContext(), f.order.ID, f.payment.ID); err != nil {
+		t.Fatalf("MarkPaid() error = %v", err)
+	}
+
+	other := &model.Order{
+		UserID:      f.user.ID,
+		EventID:     f.event.ID,
+		Status:      model.OrderStatusPaid,
+		Currency:    "USD",
+		TotalAmount: 5000,
+		ExpiresAt:   time.Now(),
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	mustCreate(t, db.WithContext(systemContext()), other)
+
+	refund := newRefund(t, refunds, f, f.order.TotalAmount)
+	if err := refunds.Complete(systemContext(), refund, f.user.ID, "re_1"); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+
+	if got := f.count(t, &model.Registration{}, "event_id = ? AND user_id = ?", f.event.ID, f.user.ID); got != 1 {
+		t.Errorf("registrations = %d, want 1", got)
+	}
+}
+
+func TestRefundRepositoryOneOpenRefundPerOrder(t *testing.T) {
+	db := openTestDB(t)
+	orders := repository.NewOrderRepository(db)
+	refunds := repository.NewRefundRepository(db)
+	f := newOrderFixture(t, db, 2)
+
+	if _, err := orders.MarkPaid(systemContext(), f.order.ID, f.payment.ID); err != nil {
+		t.Fatalf("MarkPaid() error = %v", err)
+	}
+	newRefund(t, refunds, f, 5000)
+
+	second := &model.Refund{
+		OrderID:   f.order.ID,
+		EventID:   f.event.ID,
+		UserID:    f.user.ID,
+		Amount:    5000,
+		Currency:  "USD",
+		Percent:   50,
+		Status:    model.RefundStatusRequested,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	err := refunds.Create(systemContext(), second, f.user.ID)
+	var conflictErr *errs.ConflictError
+	if !errors.As(err, &conflictErr) {
+		t.Fatalf("Create() error = %v, want a ConflictError", err)
+	}
+}
+
+// TestLatePaymentRefund follows the repository calls the webhook makes when a
+// payment succeeds after its order expired: the order cannot be paid any more, the
+// payment is claimed once and refunded, and the expired order and the ticket counts
+// stay as they were.
+func TestLatePaymentRefund(t *testing.T) {
+	db := openTestDB(t)
+	orders := repository.NewOrderRepository(db)
+	refunds := repository.NewRefundRepository(db)
+	f := newOrderFixture(t, db, 2)
+
+	if err := orders.Release(systemContext(), f.order.ID, model.OrderStatusExpired, ""); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+
+	_, err := orders.MarkPaid(systemContext(), f.order.ID, f.payment.ID)
+	var conflictErr *errs.ConflictError
+	if !errors.As(err, &conflictErr) {
+		t.Fatalf("MarkPaid() error = %v, want a ConflictError", err)
+	}
+
+	order, err := orders.GetByID(systemContext(), f.order.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if order.PaidAt != nil {
+		t.Fatalf("expired order has paid at %v", order.PaidAt)
+	}
+
+	claimed, err := orders.ClaimPaymentRefund(systemContext(), f.payment.ID)
+	if err != nil || !claimed {
+		t.Fatalf("ClaimPaymentRefund() = %v, %v, want true", claimed, err)
+	}
+
+	refund := newRefund(t, refunds, f, f.payment.Amount)
+	if err := refunds.Complete(systemContext(), refund, f.user.ID, "re_late"); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if err := orders.SetPaymentStatus(systemContext(), f.payment.ID, model.PaymentStatusRefunded); err != nil {
+		t.Fatalf("SetPaymentStatus() error = %v", err)
+	}
+
+	if order := f.reloadOrder(t); order.Status != model.OrderStatusExpired {
+		t.Errorf("order status = %q, want %q", order.Status, model.OrderStatusExpired)
+	}
+	if payment := f.reloadPayment(t); payment.Status != model.PaymentStatusRefunded {
+		t.Errorf("payment status = %q, want %q", payment.Status, model.PaymentStatusRefunded)
+	}
+	f.assertTickets(t, 0, 0)
+	if got := f.count(t, &model.Registration{}, "event_id = ? AND user_id = ?", f.event.ID, f.user.ID); got != 0 {
+		t.Errorf("registrations = %d, want 0", got)
+	}
+
+	// A repeated webhook finds the payment already claimed and refunds nothing.
+	claimed, err = orders.ClaimPaymentRefund(systemContext(), f.payment.ID)
+	if err != nil || claimed {
+		t.Errorf("second ClaimPaymentRefund() = %v, %v, want false", claimed, err)
+	}
+}
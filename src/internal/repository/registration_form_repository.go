@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/hafiztri123/src/internal/model"
+	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RegistrationFormRepository interface {
+	Get(ctx context.Context, eventID string) (*model.RegistrationForm, error)
+	Save(ctx context.Context, form *model.RegistrationForm) error
+	Delete(ctx context.Context, eventID string) error
+}
+
+type registrationFormRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewRegistrationFormRepository(db *gorm.DB) RegistrationFormRepository {
+	return &registrationFormRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *registrationFormRepositoryImpl) Get(ctx context.Context, eventID string) (*model.RegistrationForm, error) {
+	var form model.RegistrationForm
+	err := r.db.WithContext(ctx).Where("event_id = ?", eventID).First(&form).Error
+	if err != nil {
+		return nil, errs.NewNotFoundError("Registration form not found")
+	}
+
+	return &form, nil
+}
+
+// Save creates the event's form or replaces its fields.
+func (r *registrationFormRepositoryImpl) Save(ctx context.Context, form *model.RegistrationForm) error {
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "event_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"fields", "updated_at"}),
+		}).
+		Create(form).Error
+	if err != nil {
+		return DBError(err)
+	}
+
+	return nil
+}
+
+func (r *registrationFormRepositoryImpl) Delete(ctx context.Context, eventID string) error {
+	result := r.db.WithContext(ctx).
+		Where("event_id = ?", eventID).
+		Delete(&model.RegistrationForm{})
+	if result.Error != nil {
+		return DBError(result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return errs.NewNotFoundError("Registration form not found")
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/hafiztri123/src/internal/model"
+	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"gorm.io/gorm"
+)
+
+type RegistrationRepository interface {
+	Create(ctx context.Context, registration *model.Registration) error
+	GetByID(ctx context.Context, id string) (*model.Registration, error)
+	GetByEventAndUser(ctx context.Context, eventID, userID string) (*model.Registration, error)
+	ListOverlappingForUser(ctx context.Context, userID string, start, end time.Time) ([]*model.Event, error)
+	CheckIn(ctx context.Context, eventID, userID string, at time.Time) (*model.Registration, error)
+	ListEventIDsByUser(ctx context.Context, userID string) ([]string, error)
+	GetDetail(ctx context.Context, eventID, userID string) (*model.Registration, error)
+	ListByEvent(ctx context.Context, eventID string) ([]*model.Registration, error)
+}
+
+type registrationRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewRegistrationRepository(db *gorm.DB) RegistrationRepository {
+	return &registrationRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *registrationRepositoryImpl) Create(ctx context.Context, registration *model.Registration) error {
+	err := r.db.WithContext(ctx).Create(registration).Error
+	if err != nil {
+		return DBError(err)
+	}
+
+	return nil
+}
+
+func (r *registrationRepositoryImpl) GetByID(ctx context.Context, id string) (*model.Registration, error) {
+	var registration model.Registration
+	err := r.db.WithContext(ctx).
+		Preload("Attendees", orderAttendees).
+		Where("id = ?", id).
+		First(&registration).Error
+	if err != nil {
+		return nil, DBError(err)
+	}
+
+	return &registration, nil
+}
+
+func (r *registrationRepositoryImpl) GetByEventAndUser(ctx context.Context, eventID, userID string) (*model.Registration, error) {
+	var registration model.Registration
+	err := r.db.WithContext(ctx).
+		Preload("Attendees", orderAttendees).
+		Where("event_id = ? AND user_id = ?", eventID, userID).
+		First(&registration).Error
+	if err != nil {
+		return nil, DBError(err)
+	}
+
+	return &registration, nil
+}
+
+// ListOverlappingForUser returns the events the user is registered for whose
+// time range intersects [start, end).
+func (r *registrationRepositoryImpl) ListOverlappingForUser(ctx context.Context, userID string, start, end time.Time) ([]*model.Event, error) {
+	var events []*model.Event
+	err := r.db.WithContext(ctx).
+		Joins("JOIN registrations ON registrations.event_id = events.id").
+		Where("registrations.user_id = ?", userID).
+		Where("events.start_date < ? AND events.end_date > ?", end, start).
+		Order("events.start_date ASC").
+		Find(&events).Error
+	if err != nil {
+		return nil, DBError(err)
+	}
+
+	return events, nil
+}
+
+// CheckIn marks a registration as checked in. Checking in twice is a ConflictError.
+func (r *registrationRepositoryImpl) CheckIn(ctx context.Context, eventID, userID string, at time.Time) (*model.Registration, error) {
+	registration, err := r.GetByEventAndUser(ctx, eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := r.db.WithContext(ctx).
+		Model(&model.Registration{}).
+		Where("id = ? AND checked_in_at IS NULL", registration.ID).
+		Update("checked_in_at", at)
+	if result.Error != nil {
+		return nil, DBError(result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, errs.NewConflictError("Attendee has already checked in")
+	}
+
+	registration.CheckedInAt = &at
+	return registration, nil
+}
+
+func (r *registrationRepositoryImpl) ListEventIDsByUser(ctx context.Context, userID string) ([]string, error) {
+	var eventIDs []string
+	err := r.db.WithContext(ctx).
+		Model(&model.Registration{}).
+		Where("user_id = ?", userID).
+		Pluck("event_id", &eventIDs).Error
+	if err != nil {
+		return nil, DBError(err)
+	}
+
+	return eventIDs, nil
+}
+
+// GetDetail returns the user's registration for the event with the user loaded.
+func (r *registrationRepositoryImpl) GetDetail(ctx context.Context, eventID, userID string) (*model.Registration, error) {
+	var registration model.Registration
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Preload("Attendees", orderAttendees).
+		Where("event_id = ? AND user_id = ?", eventID, userID).
+		First(&registration).Error
+	if err != nil {
+		return nil, errs.NewNotFoundError("Registration not found")
+	}
+
+	return &registration, nil
+}
+
+// ListByEvent returns the event's registrations with their users, oldest first.
+func (r *registrationRepositoryImpl) ListByEvent(ctx context.Context, eventID string) ([]*model.Registration, error) {
+	var registrations []*model.Registration
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Preload("Attendees", orderAttendees).
+		Where("event_id = ?", eventID).
+		Order("created_at ASC").
+		Find(&registrations).Error
+	if err != nil {
+		return nil, DBError(err)
+	}
+
+	return registrations, nil
+}
+
+// orderAttendees lists a group booking's attendees in the order they were booked.
+func orderAttendees(db *gorm.DB) *gorm.DB {
+	return db.Order("created_at ASC, id ASC")
+}
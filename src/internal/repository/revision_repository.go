@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/hafiztri123/src/internal/model"
+	"gorm.io/gorm"
+)
+
+type RevisionRepository interface {
+	ListByEvent(ctx context.Context, eventID string) ([]*model.EventRevision, error)
+	GetByID(ctx context.Context, eventID, id string) (*model.EventRevision, error)
+}
+
+type revisionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewRevisionRepository(db *gorm.DB) RevisionRepository {
+	return &revisionRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *revisionRepositoryImpl) ListByEvent(ctx context.Context, eventID string) ([]*model.EventRevision, error) {
+	var revisions []*model.EventRevision
+	err := r.db.WithContext(ctx).
+		Where("event_id = ?", eventID).
+		Order("number DESC").
+		Find(&revisions).Error
+	if err != nil {
+		return nil, DBError(err)
+	}
+
+	return revisions, nil
+}
+
+func (r *revisionRepositoryImpl) GetByID(ctx context.Context, eventID, id string) (*model.EventRevision, error) {
+	var revision model.EventRevision
+	err := r.db.WithContext(ctx).
+		Where("event_id = ? AND id = ?", eventID, id).
+		First(&revision).Error
+	if err != nil {
+		return nil, DBError(err)
+	}
+
+	return &revision, nil
+}
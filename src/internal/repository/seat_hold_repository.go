@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hafiztri123/src/internal/model"
+	"github.com/hafiztri123/src/internal/pkg/cache"
+	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"github.com/redis/go-redis/v9"
+)
+
+// holdSeatsScript takes a hold only if none of its seats is locked and the user
+// holds nothing else for the event, then locks the seats, the user and the hold
+// record together. It runs atomically in Redis, so two holds can never share a
+// seat. KEYS are the hold record, the user lock and the seat locks; it returns 0
+// on success, -1 if the user already holds seats and n if the n-th seat is taken.
+var holdSeatsScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[2]) == 1 then
+	return -1
+end
+for i = 3, #KEYS do
+	if redis.call('EXISTS', KEYS[i]) == 1 then
+		return i - 2
+	end
+end
+for i = 2, #KEYS do
+	redis.call('SET', KEYS[i], ARGV[1], 'PX', ARGV[3])
+end
+redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+return 0
+`)
+
+// releaseSeatsScript removes a hold and the locks that still belong to it.
+var releaseSeatsScript = redis.NewScript(`
+for i = 2, #KEYS do
+	if redis.call('GET', KEYS[i]) == ARGV[1] then
+		redis.call('DEL', KEYS[i])
+	end
+end
+redis.call('DEL', KEYS[1])
+return 0
+`)
+
+// ownsSeatsScript reports whether the hold still exists and locks all its seats.
+var ownsSeatsScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return 0
+end
+for i = 2, #KEYS do
+	if redis.call('GET', KEYS[i]) ~= ARGV[1] then
+		return 0
+	end
+end
+return 1
+`)
+
+type SeatHoldRepository interface {
+	Hold(ctx context.Context, hold *model.SeatHold) error
+	Get(ctx context.Context, id string) (*model.SeatHold, error)
+	Release(ctx context.Context, hold *model.SeatHold) error
+	Owns(ctx context.Context, hold *model.SeatHold) (bool, error)
+	HeldSeats(ctx context.Context, eventID string, seatIDs []string) (map[string]bool, error)
+}
+
+type seatHoldRepositoryImpl struct {
+	cache *cache.RedisCache
+}
+
+func NewSeatHoldRepository(cache *cache.RedisCache) SeatHoldRepository {
+	return &seatHoldRepositoryImpl{
+		cache: cache,
+	}
+}
+
+func seatHoldKey(id string) string {
+	return fmt.Sprintf("seat_hold:%s", id)
+}
+
+func seatHoldUserKey(eventID string, userID string) string {
+	return fmt.Sprintf("seat_hold:%s:user:%s", eventID, userID)
+}
+
+func seatLockKey(eventID string, seatID string) string {
+	return fmt.Sprintf("seat_hold:%s:seat:%s", eventID, seatID)
+}
+
+// holdKeys lists the hold record, the user lock and the seat locks of a hold, in
+// the order the scripts expect them.
+func holdKeys(hold *model.SeatHold) []string {
+	keys := []string{seatHoldKey(hold.ID), seatHoldUserKey(hold.EventID, hold.UserID)}
+	for _, seatID := range hold.SeatIDs {
+		keys = append(keys, seatLockKey(hold.EventID, seatID))
+	}
+	return keys
+}
+
+// Hold locks the hold's seats until it expires. It returns a ConflictError naming
+// the first seat that is already held by someone else.
+func (r *seatHoldRepositoryImpl) Hold(ctx context.Context, hold *model.SeatHold) error {
+	record, err := json.Marshal(hold)
+	if err != nil {
+		return err
+	}
+
+	ttl := time.Until(hold.ExpiresAt).Milliseconds()
+	if ttl <= 0 {
+		return errs.NewBadRequestError("Seat hold has already expired")
+	}
+
+	taken, err := holdSeatsScript.Run(ctx, r.cache.Client, holdKeys(hold), hold.ID, record, ttl).Int()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case taken < 0:
+		return errs.NewConflictError("You are already holding seats for this event")
+	case taken > 0:
+		return errs.NewConflictError("Seat " + hold.SeatIDs[taken-1] + " is already held")
+	}
+
+	return nil
+}
+
+func (r *seatHoldRepositoryImpl) Get(ctx context.Context, id string) (*model.SeatHold, error) {
+	data, err := r.cache.Client.Get(ctx, seatHoldKey(id)).Bytes()
+	if err == redis.Nil {
+		return nil, errs.NewNotFoundError("Seat hold not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var hold model.SeatHold
+	if err := json.Unmarshal(data, &hold); err != nil {
+		return nil, err
+	}
+
+	return &hold, nil
+}
+
+func (r *seatHoldRepositoryImpl) Release(ctx context.Context, hold *model.SeatHold) error {
+	return releaseSeatsScript.Run(ctx, r.cache.Client, holdKeys(hold), hold.ID).Err()
+}
+
+// Owns reports whether the hold is still in place for all of its seats.
+func (r *seatHoldRepositoryImpl) Owns(ctx context.Context, hold *model.SeatHold) (bool, error) {
+	keys := holdKeys(hold)
+	keys = append(keys[:1], keys[2:]...)
+
+	owns, err := ownsSeatsScript.Run(ctx, r.cache.Client, keys, hold.ID).Int()
+	if err != nil {
+		return false, err
+	}
+
+	return owns == 1, nil
+}
+
+// HeldSeats reports which of the event's seats are currently held.
+func (r *seatHoldRepositoryImpl) HeldSeats(ctx context.Context, eventID string, seatIDs []string) (map[string]bool, error) {
+	held := make(map[string]bool, len(seatIDs))
+	if len(seatIDs) == 0 {
+		return held, nil
+	}
+
+	keys := make([]string, len(seatIDs))
+	for i, seatID := range seatIDs {
+		keys[i] = seatLockKey(eventID, seatID)
+	}
+
+	values, err := r.cache.Client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, value := range values {
+		if value != nil {
+			held[seatIDs[i]] = true
+		}
+	}
+
+	return held, nil
+}
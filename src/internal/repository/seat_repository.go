@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/hafiztri123/src/internal/model"
+	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SeatRepository interface {
+	GetMap(ctx context.Context, eventID string) ([]*model.SeatSection, error)
+	ReplaceMap(ctx context.Context, eventID string, sections []*model.SeatSection) error
+	ListByIDs(ctx context.Context, eventID string, ids []string) ([]*model.Seat, error)
+}
+
+type seatRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewSeatRepository(db *gorm.DB) SeatRepository {
+	return &seatRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *seatRepositoryImpl) GetMap(ctx context.Context, eventID string) ([]*model.SeatSection, error) {
+	var sections []*model.SeatSection
+	err := r.db.WithContext(ctx).
+		Preload("Rows", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
+		Preload("Rows.Seats", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
+		Where("event_id = ?", eventID).
+		Order("position ASC").
+		Find(&sections).Error
+	if err != nil {
+		return nil, DBError(err)
+	}
+
+	return sections, nil
+}
+
+// ReplaceMap swaps the event's seat map for a new one. The current seats are locked
+// first, so a checkout cannot claim a seat while it is being removed, and the map
+// cannot change once any seat is booked. Every ticket type used by the new map
+// becomes seated with one ticket per seat; ticket types it no longer uses are
+// sold without seats again.
+func (r *seatRepositoryImpl) ReplaceMap(ctx context.Context, eventID string, sections []*model.SeatSection) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current []*model.Seat
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("event_id = ?", eventID).
+			Find(&current).Error
+		if err != nil {
+			return err
+		}
+
+		for _, seat := range current {
+			if seat.OrderID != nil {
+				return errs.NewConflictError("The seat map cannot change once seats are booked")
+			}
+		}
+
+		if err := tx.Where("event_id = ?", eventID).Delete(&model.Seat{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("event_id = ?", eventID).Delete(&model.SeatRow{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("event_id = ?", eventID).Delete(&model.SeatSection{}).Error; err != nil {
+			return err
+		}
+
+		if len(sections) > 0 {
+			if err := tx.Create(sections).Error; err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		err = tx.Model(&model.TicketType{}).
+			Where("event_id = ? AND seated = ?", eventID, true).
+			Updates(map[string]interface{}{
+				"seated":     false,
+				"updated_at": now,
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		tiers := map[string]int{}
+		for _, section := range sections {
+			for _, row := range section.Rows {
+				for _, seat := range row.Seats {
+					tiers[seat.TicketTypeID]++
+				}
+			}
+		}
+
+		for ticketTypeID, seats := range tiers {
+			result := tx.Model(&model.TicketType{}).
+				Where("id = ? AND event_id = ?", ticketTypeID, eventID).
+				Where("sold + reserved <= ?", seats).
+				Updates(map[string]interface{}{
+					"seated":     true,
+					"quantity":   seats,
+					"updated_at": now,
+				})
+			if result.Error != nil {
+				return result.Error
+			}
+
+			if result.RowsAffected == 0 {
+				return errs.NewConflictError("Ticket type " + ticketTypeID + " has more tickets sold than seats")
+			}
+		}
+
+		return nil
+	})
+
+	return DBError(err)
+}
+
+func (r *seatRepositoryImpl) ListByIDs(ctx context.Context, eventID string, ids []string) ([]*model.Seat, error) {
+	var seats []*model.Seat
+	err := r.db.WithContext(ctx).
+		Where("event_id = ? AND id IN ?", eventID, ids).
+		Find(&seats).Error
+	if err != nil {
+		return nil, DBError(err)
+	}
+
+	return seats, nil
+}
@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hafiztri123/src/internal/model"
+	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"github.com/hafiztri123/src/internal/pkg/slug"
+	"github.com/hafiztri123/src/internal/pkg/tenant"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// slugSources maps each resource to its table and the column its slug is made from.
+var slugSources = map[string]struct {
+	table    string
+	column   string
+	fallback string
+	notFound string
+}{
+	model.SlugResourceEvent:    {"events", "title", "event", "Event not found"},
+	model.SlugResourceCategory: {"categories", "name", "category", "Category not found"},
+	model.SlugResourceUser:     {"users", "full_name", "organizer", "Organizer not found"},
+	model.SlugResourceOrganization: {"organizations", "name", "organization", "Organization not found"},
+}
+
+// slugAttempts is how many times a write is retried when a concurrent write took
+// the slug it picked.
+const slugAttempts = 3
+
+// allocateSlug returns a slug derived from text that no other row of the resource
+// holds, including soft-deleted rows, and that does not redirect to another row.
+// Taken slugs get a numeric suffix: "meetup", "meetup-2", "meetup-3", ...
+// Slugs are unique across organizations, so the lookup is not confined to one.
+func allocateSlug(tx *gorm.DB, resource, id, text string) (string, error) {
+	tx = tx.WithContext(tenant.WithSystem(tx.Statement.Context))
+	source := slugSources[resource]
+	base := slug.Make(text, source.fallback)
+	pattern := base + "-%"
+
+	var held []string
+	query := tx.Table(source.table).Where("slug = ? OR slug LIKE ?", base, pattern)
+	if id != "" {
+		query = query.Where("id <> ?", id)
+	}
+	if err := query.Pluck("slug", &held).Error; err != nil {
+		return "", err
+	}
+
+	var redirected []string
+	query = tx.Model(&model.SlugRedirect{}).
+		Where("resource_type = ? AND (old_slug = ? OR old_slug LIKE ?)", resource, base, pattern)
+	if id != "" {
+		query = query.Where("target_id <> ?", id)
+	}
+	if err := query.Pluck("old_slug", &redirected).Error; err != nil {
+		return "", err
+	}
+
+	taken := map[string]bool{}
+	for _, s := range append(held, redirected...) {
+		taken[s] = true
+	}
+
+	candidate := base
+	for n := 2; taken[candidate]; n++ {
+		candidate = fmt.Sprintf("%s-%d", base, n)
+	}
+
+	return candidate, nil
+}
+
+// renameSlug gives a row a slug derived from text and keeps its old slug as a
+// redirect. Renaming back to a previous slug reclaims it from the redirects.
+func renameSlug(tx *gorm.DB, resource, id, oldSlug, text string) (string, error) {
+	newSlug, err := allocateSlug(tx, resource, id, text)
+	if err != nil {
+		return "", err
+	}
+
+	if newSlug == oldSlug {
+		return oldSlug, nil
+	}
+
+	if oldSlug != "" {
+		redirect := &model.SlugRedirect{
+			ResourceType: resource,
+			OldSlug:      oldSlug,
+			TargetID:     id,
+		}
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "resource_type"}, {Name: "old_slug"}},
+			DoUpdates: clause.AssignmentColumns([]string{"target_id", "created_at"}),
+		}).Create(redirect).Error
+		if err != nil {
+			return "", err
+		}
+	}
+
+	err = tx.Where("resource_type = ? AND old_slug = ?", resource, newSlug).
+		Delete(&model.SlugRedirect{}).Error
+	if err != nil {
+		return "", err
+	}
+
+	err = tx.Table(slugSources[resource].table).
+		Where("id = ?", id).
+		Update("slug", newSlug).Error
+	if err != nil {
+		return "", err
+	}
+
+	return newSlug, nil
+}
+
+// resolveSlug returns the ID of the row that has the slug, or that had it before
+// being renamed.
+func resolveSlug(db *gorm.DB, resource, value string) (string, error) {
+	source := slugSources[resource]
+
+	var ids []string
+	err := db.Table(source.table).Where("slug = ?", value).Limit(1).Pluck("id", &ids).Error
+	if err != nil {
+		return "", DBError(err)
+	}
+	if len(ids) > 0 {
+		return ids[0], nil
+	}
+
+	err = db.Model(&model.SlugRedirect{}).
+		Where("resource_type = ? AND old_slug = ?", resource, value).
+		Pluck("target_id", &ids).Error
+	if err != nil {
+		return "", DBError(err)
+	}
+	if len(ids) > 0 {
+		return ids[0], nil
+	}
+
+	return "", errs.NewNotFoundError(source.notFound)
+}
+
+// withSlugRetry runs write again if it failed because another write claimed the
+// same slug in the meantime.
+func withSlugRetry(write func() error) error {
+	var err error
+	for attempt := 0; attempt < slugAttempts; attempt++ {
+		err = write()
+		if !isSlugConflict(err) {
+			return err
+		}
+	}
+	return err
+}
+
+func isSlugConflict(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" && strings.Contains(pgErr.ConstraintName, "slug")
+}
+
+// BackfillSlugs gives a slug to every event, category and user created before slugs
+// existed.
+func BackfillSlugs(db *gorm.DB) error {
+	for _, resource := range []string{model.SlugResourceEvent, model.SlugResourceCategory, model.SlugResourceUser} {
+		source := slugSources[resource]
+
+		var rows []struct {
+			ID   string
+			Text string
+		}
+		err := db.Table(source.table).
+			Select(fmt.Sprintf("id, %s AS text", source.column)).
+			Where("slug IS NULL OR slug = ''").
+			Order("created_at ASC").
+			Scan(&rows).Error
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			err := db.Transaction(func(tx *gorm.DB) error {
+				_, err := renameSlug(tx, resource, row.ID, "", row.Text)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
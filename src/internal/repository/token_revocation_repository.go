@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hafiztri123/src/internal/pkg/cache"
+	"github.com/redis/go-redis/v9"
+)
+
+// tokenGenerationTTL bounds how long a cached generation is trusted, so a write
+// that was lost after the database changed is corrected from the database later.
+const tokenGenerationTTL = time.Hour
+
+// setGenerationScript stores a generation only if it is higher than the cached
+// one. Generations only ever grow, so a reader caching a value it loaded before a
+// concurrent logout cannot overwrite the newer value the logout wrote.
+var setGenerationScript = redis.NewScript(`
+local current = redis.call('GET', KEYS[1])
+if current and tonumber(current) >= tonumber(ARGV[1]) then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+return 1
+`)
+
+type TokenRevocationRepository interface {
+	RevokeJTI(ctx context.Context, jti string, expiresAt time.Time) error
+	SetGeneration(ctx context.Context, userID string, generation int) error
+	Lookup(ctx context.Context, jti string, userID string) (revoked bool, generation int, known bool, err error)
+}
+
+type tokenRevocationRepositoryImpl struct {
+	cache *cache.RedisCache
+}
+
+func NewTokenRevocationRepository(cache *cache.RedisCache) TokenRevocationRepository {
+	return &tokenRevocationRepositoryImpl{
+		cache: cache,
+	}
+}
+
+func revokedJTIKey(jti string) string {
+	return fmt.Sprintf("revoked_jti:%s", jti)
+}
+
+func tokenGenerationKey(userID string) string {
+	return fmt.Sprintf("token_generation:%s", userID)
+}
+
+// RevokeJTI records an access token as revoked until it would have expired anyway.
+func (r *tokenRevocationRepositoryImpl) RevokeJTI(ctx context.Context, jti string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+
+	return r.cache.Client.Set(ctx, revokedJTIKey(jti), 1, ttl).Err()
+}
+
+// SetGeneration caches the user's current token generation unless a higher one is
+// already cached. Access tokens issued with a lower generation are no longer
+// accepted.
+func (r *tokenRevocationRepositoryImpl) SetGeneration(ctx context.Context, userID string, generation int) error {
+	keys := []string{tokenGenerationKey(userID)}
+	return setGenerationScript.Run(ctx, r.cache.Client, keys, generation, tokenGenerationTTL.Milliseconds()).Err()
+}
+
+// Lookup reads the revocation state of a token in one round trip. known is false
+// when the user's generation is not cached, in which case the caller has to load
+// it from the database.
+func (r *tokenRevocationRepositoryImpl) Lookup(ctx context.Context, jti string, userID string) (bool, int, bool, error) {
+	values, err := r.cache.Client.MGet(ctx, revokedJTIKey(jti), tokenGenerationKey(userID)).Result()
+	if err != nil {
+		return false, 0, false, err
+	}
+
+	revoked := values[0] != nil
+
+	raw, ok := values[1].(string)
+	if !ok {
+		return revoked, 0, false, nil
+	}
+
+	generation, err := strconv.Atoi(raw)
+	if err != nil {
+		return revoked, 0, false, nil
+	}
+
+	return revoked, generation, true, nil
+}
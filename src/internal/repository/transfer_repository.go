@@ -0,0 +1,258 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/hafiztri123/src/internal/model"
+	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"github.com/hafiztri123/src/internal/pkg/tenant"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TransferRepository interface {
+	GetPolicy(ctx context.Context, eventID string) (*model.TransferPolicy, error)
+	SavePolicy(ctx context.Context, policy *model.TransferPolicy) error
+	Create(ctx context.Context, transfer *model.TicketTransfer) error
+	GetByID(ctx context.Context, id string) (*model.TicketTransfer, error)
+	ListPendingByEmail(ctx context.Context, email string) ([]*model.TicketTransfer, error)
+	ListByUser(ctx context.Context, userID string) ([]*model.TicketTransfer, error)
+	ListByEvent(ctx context.Context, eventID string) ([]*model.TicketTransfer, error)
+	Accept(ctx context.Context, transfer *model.TicketTransfer, recipientID string, nonce string, maxTransfers int) error
+	Close(ctx context.Context, transfer *model.TicketTransfer, status string, actorID string) error
+}
+
+type transferRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewTransferRepository(db *gorm.DB) TransferRepository {
+	return &transferRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *transferRepositoryImpl) GetPolicy(ctx context.Context, eventID string) (*model.TransferPolicy, error) {
+	var policy model.TransferPolicy
+	err := r.db.WithContext(ctx).Where("event_id = ?", eventID).First(&policy).Error
+	if err != nil {
+		return nil, DBError(err)
+	}
+
+	return &policy, nil
+}
+
+func (r *transferRepositoryImpl) SavePolicy(ctx context.Context, policy *model.TransferPolicy) error {
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "event_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"enabled", "max_transfers", "updated_at"}),
+		}).
+		Create(policy).Error
+	if err != nil {
+		return DBError(err)
+	}
+
+	return nil
+}
+
+// Create opens a transfer together with its audit entry. The registration is
+// locked while it is checked, so a ticket never has two pending transfers.
+func (r *transferRepositoryImpl) Create(ctx context.Context, transfer *model.TicketTransfer) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var registration model.Registration
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND user_id = ?", transfer.RegistrationID, transfer.FromUserID).
+			First(&registration).Error
+		if err != nil {
+			return err
+		}
+
+		var pending int64
+		err = tx.Model(&model.TicketTransfer{}).
+			Where("registration_id = ? AND status = ?", registration.ID, model.TransferStatusPending).
+			Count(&pending).Error
+		if err != nil {
+			return err
+		}
+
+		if pending > 0 {
+			return errs.NewConflictError("This ticket already has a pending transfer")
+		}
+
+		if err := tx.Create(transfer).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(newTransferAudit(transfer, model.TransferActionInitiated, transfer.FromUserID)).Error
+	})
+
+	return DBError(err)
+}
+
+func (r *transferRepositoryImpl) GetByID(ctx context.Context, id string) (*model.TicketTransfer, error) {
+	var transfer model.TicketTransfer
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&transfer).Error
+	if err != nil {
+		return nil, errs.NewNotFoundError("Transfer not found")
+	}
+
+	return &transfer, nil
+}
+
+func (r *transferRepositoryImpl) ListPendingByEmail(ctx context.Context, email string) ([]*model.TicketTransfer, error) {
+	return r.listWithEvent(ctx, r.db.WithContext(ctx).
+		Where("LOWER(ticket_transfers.to_email) = LOWER(?) AND ticket_transfers.status = ?", email, model.TransferStatusPending))
+}
+
+func (r *transferRepositoryImpl) ListByUser(ctx context.Context, userID string) ([]*model.TicketTransfer, error) {
+	return r.listWithEvent(ctx, r.db.WithContext(ctx).
+		Where("ticket_transfers.from_user_id = ?", userID))
+}
+
+// listWithEvent runs a transfer query joined with the transfers' events, keeping
+// to the events of the context's organization.
+func (r *transferRepositoryImpl) listWithEvent(ctx context.Context, query *gorm.DB) ([]*model.TicketTransfer, error) {
+	query = query.Joins("Event")
+
+	if !tenant.IsSystem(ctx) {
+		organizationID, ok := tenant.FromContext(ctx)
+		if !ok {
+			return nil, DBError(tenant.ErrMissing)
+		}
+		query = query.Where(clause.Eq{Column: clause.Column{Table: "Event", Name: tenant.Column}, Value: organizationID})
+	}
+
+	var transfers []*model.TicketTransfer
+	err := query.
+		Order("ticket_transfers.created_at DESC").
+		Find(&transfers).Error
+	if err != nil {
+		return nil, DBError(err)
+	}
+
+	return transfers, nil
+}
+
+func (r *transferRepositoryImpl) ListByEvent(ctx context.Context, eventID string) ([]*model.TicketTransfer, error) {
+	var transfers []*model.TicketTransfer
+	err := r.db.WithContext(ctx).
+		Preload("Audit", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		Where("event_id = ?", eventID).
+		Order("created_at DESC").
+		Find(&transfers).Error
+	if err != nil {
+		return nil, DBError(err)
+	}
+
+	return transfers, nil
+}
+
+// Accept hands the ticket to the recipient and reissues it under a new nonce. The
+// registration only moves if the sender still holds it, it has not been used for
+// check-in, it is not a group booking, it is under the transfer cap and the sender
+// has no refund open for the event; otherwise the transfer stays pending and a
+// ConflictError is returned.
+func (r *transferRepositoryImpl) Accept(ctx context.Context, transfer *model.TicketTransfer, recipientID string, nonce string, maxTransfers int) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		result := tx.Model(&model.TicketTransfer{}).
+			Where("id = ? AND status = ?", transfer.ID, model.TransferStatusPending).
+			Updates(map[string]interface{}{
+				"status":       model.TransferStatusAccepted,
+				"to_user_id":   recipientID,
+				"responded_at": now,
+				"updated_at":   now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return errs.NewConflictError("Transfer is no longer pending")
+		}
+
+		query := tx.Model(&model.Registration{}).
+			Where("id = ? AND user_id = ? AND checked_in_at IS NULL", transfer.RegistrationID, transfer.FromUserID).
+			Where("NOT EXISTS (?)", tx.Model(&model.Refund{}).
+				Select("1").
+				Where("refunds.event_id = ? AND refunds.user_id = ?", transfer.EventID, transfer.FromUserID).
+				Where("refunds.status IN ?", []string{model.RefundStatusRequested, model.RefundStatusProcessing, model.RefundStatusSucceeded})).
+			Where("NOT EXISTS (?)", tx.Model(&model.Attendee{}).
+				Select("1").
+				Where("attendees.registration_id = registrations.id"))
+		if maxTransfers > 0 {
+			query = query.Where("transfer_count < ?", maxTransfers)
+		}
+
+		result = query.Updates(map[string]interface{}{
+			"user_id":        recipientID,
+			"ticket_nonce":   nonce,
+			"transfer_count": gorm.Expr("transfer_count + 1"),
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return errs.NewConflictError("This ticket can no longer be transferred")
+		}
+
+		transfer.Status = model.TransferStatusAccepted
+		transfer.ToUserID = &recipientID
+		transfer.RespondedAt = &now
+		transfer.UpdatedAt = now
+		return tx.Create(newTransferAudit(transfer, model.TransferActionAccepted, recipientID)).Error
+	})
+
+	return DBError(err)
+}
+
+// Close ends a pending transfer without moving the ticket.
+func (r *transferRepositoryImpl) Close(ctx context.Context, transfer *model.TicketTransfer, status string, actorID string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		result := tx.Model(&model.TicketTransfer{}).
+			Where("id = ? AND status = ?", transfer.ID, model.TransferStatusPending).
+			Updates(map[string]interface{}{
+				"status":       status,
+				"responded_at": now,
+				"updated_at":   now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return errs.NewConflictError("Transfer is no longer pending")
+		}
+
+		action := model.TransferActionCancelled
+		if status == model.TransferStatusDeclined {
+			action = model.TransferActionDeclined
+		}
+
+		transfer.Status = status
+		transfer.RespondedAt = &now
+		transfer.UpdatedAt = now
+		return tx.Create(newTransferAudit(transfer, action, actorID)).Error
+	})
+
+	return DBError(err)
+}
+
+func newTransferAudit(transfer *model.TicketTransfer, action string, actorID string) *model.TicketTransferAudit {
+	return &model.TicketTransferAudit{
+		TransferID:     transfer.ID,
+		EventID:        transfer.EventID,
+		RegistrationID: transfer.RegistrationID,
+		Action:         action,
+		ActorID:        actorID,
+		CreatedAt:      time.Now(),
+	}
+}
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hafiztri123/src/internal/model"
+	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"github.com/hafiztri123/src/internal/pkg/i18n"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TranslationRepository interface {
+	UpsertEvent(ctx context.Context, translation *model.EventTranslation) error
+	ListEvent(ctx context.Context, eventID string) ([]*model.EventTranslation, error)
+	FindEvents(ctx context.Context, eventIDs []string, locale string) ([]*model.EventTranslation, error)
+	DeleteEvent(ctx context.Context, eventID, locale string) error
+	UpsertCategory(ctx context.Context, translation *model.CategoryTranslation) error
+	ListCategory(ctx context.Context, categoryID string) ([]*model.CategoryTranslation, error)
+	FindCategories(ctx context.Context, categoryIDs []string, locale string) ([]*model.CategoryTranslation, error)
+	DeleteCategory(ctx context.Context, categoryID, locale string) error
+}
+
+type translationRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewTranslationRepository(db *gorm.DB) TranslationRepository {
+	return &translationRepositoryImpl{
+		db: db,
+	}
+}
+
+// UpsertEvent creates the translation or replaces its title and description.
+func (r *translationRepositoryImpl) UpsertEvent(ctx context.Context, translation *model.EventTranslation) error {
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "event_id"}, {Name: "locale"}},
+			DoUpdates: clause.AssignmentColumns([]string{"title", "description", "updated_at"}),
+		}).
+		Create(translation).Error
+	if err != nil {
+		return DBError(err)
+	}
+
+	return nil
+}
+
+func (r *translationRepositoryImpl) ListEvent(ctx context.Context, eventID string) ([]*model.EventTranslation, error) {
+	var translations []*model.EventTranslation
+	err := r.db.WithContext(ctx).
+		Where("event_id = ?", eventID).
+		Order("locale ASC").
+		Find(&translations).Error
+	if err != nil {
+		return nil, DBError(err)
+	}
+
+	return translations, nil
+}
+
+func (r *translationRepositoryImpl) FindEvents(ctx context.Context, eventIDs []string, locale string) ([]*model.EventTranslation, error) {
+	var translations []*model.EventTranslation
+	if len(eventIDs) == 0 {
+		return translations, nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Where("event_id IN ? AND locale = ?", eventIDs, locale).
+		Find(&translations).Error
+	if err != nil {
+		return nil, DBError(err)
+	}
+
+	return translations, nil
+}
+
+func (r *translationRepositoryImpl) DeleteEvent(ctx context.Context, eventID, locale string) error {
+	result := r.db.WithContext(ctx).
+		Where("event_id = ? AND locale = ?", eventID, locale).
+		Delete(&model.EventTranslation{})
+	if result.Error != nil {
+		return DBError(result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return errs.NewNotFoundError("Translation not found")
+	}
+
+	return nil
+}
+
+// UpsertCategory creates the translation or replaces its name and description.
+func (r *translationRepositoryImpl) UpsertCategory(ctx context.Context, translation *model.CategoryTranslation) error {
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "category_id"}, {Name: "locale"}},
+			DoUpdates: clause.AssignmentColumns([]string{"name", "description", "updated_at"}),
+		}).
+		Create(translation).Error
+	if err != nil {
+		return DBError(err)
+	}
+
+	return nil
+}
+
+func (r *translationRepositoryImpl) ListCategory(ctx context.Context, categoryID string) ([]*model.CategoryTranslation, error) {
+	var translations []*model.CategoryTranslation
+	err := r.db.WithContext(ctx).
+		Where("category_id = ?", categoryID).
+		Order("locale ASC").
+		Find(&translations).Error
+	if err != nil {
+		return nil, DBError(err)
+	}
+
+	return translations, nil
+}
+
+func (r *translationRepositoryImpl) FindCategories(ctx context.Context, categoryIDs []string, locale string) ([]*model.CategoryTranslation, error) {
+	var translations []*model.CategoryTranslation
+	if len(categoryIDs) == 0 {
+		return translations, nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Where("category_id IN ? AND locale = ?", categoryIDs, locale).
+		Find(&translations).Error
+	if err != nil {
+		return nil, DBError(err)
+	}
+
+	return translations, nil
+}
+
+func (r *translationRepositoryImpl) DeleteCategory(ctx context.Context, categoryID, locale string) error {
+	result := r.db.WithContext(ctx).
+		Where("category_id = ? AND locale = ?", categoryID, locale).
+		Delete(&model.CategoryTranslation{})
+	if result.Error != nil {
+		return DBError(result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return errs.NewNotFoundError("Translation not found")
+	}
+
+	return nil
+}
+
+// searchDocument is the text search vector of a title and a description. Queries
+// must build it exactly like CreateSearchIndexes for the indexes to be used.
+func searchDocument(config, title, description string) string {
+	return fmt.Sprintf("to_tsvector('%s', coalesce(%s, '') || ' ' || coalesce(%s, ''))", config, title, description)
+}
+
+// CreateSearchIndexes adds a GIN index over the base content of events in the
+// default locale and one per translated locale over event translations, each
+// built with the text search configuration of its language.
+func CreateSearchIndexes(db *gorm.DB, catalog *i18n.Catalog) error {
+	config := i18n.SearchConfig(catalog.Default())
+	statement := fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_events_search_%s ON events USING GIN (%s)",
+		config, searchDocument(config, "title", "description"))
+	if err := db.Exec(statement).Error; err != nil {
+		return err
+	}
+
+	for _, locale := range catalog.Translations() {
+		config := i18n.SearchConfig(locale)
+		statement := fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_event_translations_search_%s ON event_translations USING GIN (%s) WHERE locale = '%s'",
+			indexSuffix(locale), searchDocument(config, "title", "description"), locale)
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// matchSearch narrows an event query to events whose base content or one of whose
+// translations matches text, each parsed with the configuration of its language.
+func matchSearch(db *gorm.DB, catalog *i18n.Catalog, text string) *gorm.DB {
+	config := i18n.SearchConfig(catalog.Default())
+	condition := db.Where(fmt.Sprintf("%s @@ websearch_to_tsquery('%s', ?)",
+		searchDocument(config, "events.title", "events.description"), config), text)
+
+	for _, locale := range catalog.Translations() {
+		config := i18n.SearchConfig(locale)
+		condition = condition.Or(fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM event_translations et WHERE et.event_id = events.id AND et.locale = '%s' AND %s @@ websearch_to_tsquery('%s', ?))",
+			locale, searchDocument(config, "et.title", "et.description"), config), text)
+	}
+
+	return condition
+}
+
+// indexSuffix turns a locale such as "pt-BR" into an identifier-safe "pt_br".
+func indexSuffix(locale string) string {
+	return strings.ReplaceAll(strings.ToLower(locale), "-", "_")
+}
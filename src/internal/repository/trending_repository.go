@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hafiztri123/src/internal/pkg/cache"
+	"github.com/redis/go-redis/v9"
+)
+
+// trendingMinScore is the score below which an event is dropped from a ranking
+// when it decays.
+const trendingMinScore = 0.01
+
+type TrendingRepository interface {
+	Increment(ctx context.Context, keys []string, eventID string, weight float64) error
+	Top(ctx context.Context, key string, limit int) ([]redis.Z, error)
+	Remove(ctx context.Context, key string, eventIDs ...string) error
+	ListKeys(ctx context.Context) ([]string, error)
+	Decay(ctx context.Context, key string, factor float64) error
+	LastDecay(ctx context.Context, window string) (time.Time, error)
+	SetLastDecay(ctx context.Context, window string, at time.Time) error
+}
+
+type trendingRepositoryImpl struct {
+	cache *cache.RedisCache
+}
+
+func NewTrendingRepository(cache *cache.RedisCache) TrendingRepository {
+	return &trendingRepositoryImpl{
+		cache: cache,
+	}
+}
+
+// TrendingKey is the sorted set ranking an organization's events in a window,
+// optionally restricted to one category.
+func TrendingKey(organizationID string, window string, categoryID string) string {
+	if categoryID == "" {
+		return fmt.Sprintf("trending:%s:%s", organizationID, window)
+	}
+	return fmt.Sprintf("trending:%s:%s:category:%s", organizationID, window, categoryID)
+}
+
+// TrendingKeyWindow returns the window a ranking key belongs to.
+func TrendingKeyWindow(key string) string {
+	parts := strings.Split(key, ":")
+	if len(parts) < 3 {
+		return ""
+	}
+	return parts[2]
+}
+
+func (r *trendingRepositoryImpl) Increment(ctx context.Context, keys []string, eventID string, weight float64) error {
+	pipe := r.cache.Client.Pipeline()
+	for _, key := range keys {
+		pipe.ZIncrBy(ctx, key, weight, eventID)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (r *trendingRepositoryImpl) Top(ctx context.Context, key string, limit int) ([]redis.Z, error) {
+	return r.cache.Client.ZRevRangeWithScores(ctx, key, 0, int64(limit-1)).Result()
+}
+
+func (r *trendingRepositoryImpl) Remove(ctx context.Context, key string, eventIDs ...string) error {
+	if len(eventIDs) == 0 {
+		return nil
+	}
+
+	members := make([]interface{}, len(eventIDs))
+	for i, id := range eventIDs {
+		members[i] = id
+	}
+	return r.cache.Client.ZRem(ctx, key, members...).Err()
+}
+
+func (r *trendingRepositoryImpl) ListKeys(ctx context.Context) ([]string, error) {
+	var keys []string
+
+	iter := r.cache.Client.Scan(ctx, 0, "trending:*", 500).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// Decay multiplies every score in a ranking by factor and drops the events whose
+// score has become negligible.
+func (r *trendingRepositoryImpl) Decay(ctx context.Context, key string, factor float64) error {
+	pipe := r.cache.Client.TxPipeline()
+	pipe.ZUnionStore(ctx, key, &redis.ZStore{
+		Keys:    []string{key},
+		Weights: []float64{factor},
+	})
+	pipe.ZRemRangeByScore(ctx, key, "-inf", fmt.Sprintf("(%g", trendingMinScore))
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// LastDecay returns when the rankings of a window were last decayed, or the zero
+// time if they never were.
+func (r *trendingRepositoryImpl) LastDecay(ctx context.Context, window string) (time.Time, error) {
+	value, err := r.cache.Client.Get(ctx, "trending-decay:"+window).Result()
+	if err == redis.Nil {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	unix, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, nil
+	}
+
+	return time.Unix(unix, 0), nil
+}
+
+func (r *trendingRepositoryImpl) SetLastDecay(ctx context.Context, window string, at time.Time) error {
+	return r.cache.Client.Set(ctx, "trending-decay:"+window, at.Unix(), 0).Err()
+}
@@ -20,6 +20,9 @@ type UserRepository interface {
     Delete(id string) error
 	ChangePassword(id string, password string) error
 	ChangePhotoProfile(id string, imageURL string) error
+	ResolveSlug(slug string) (string, error)
+	BumpTokenGeneration(id string) (int, error)
+	MarkEmailVerified(id string, email string) error
 }
 
 type userRepository struct {
@@ -63,10 +66,23 @@ func (r *userRepository) IsEmailUnique(email string) (bool, error) {
 }
 
 func (r *userRepository) Create(user *model.User) error {
-    err := r.db.Create(user).Error
+	err := withSlugRetry(func() error {
+		return r.db.Transaction(func(tx *gorm.DB) error {
+			slug, err := allocateSlug(tx, model.SlugResourceUser, "", user.FullName)
+			if err != nil {
+				return err
+			}
+			user.Slug = slug
+
+			return tx.Create(user).Error
+		})
+	})
 	return DBError(err)
 }
 
+// Update overwrites the profile fields of a user with the given values, including
+// empty ones. A non-zero version makes the write conditional on the stored version.
+// A new full name moves the user to a new slug and the old one redirects to it.
 func (r *userRepository) Update(id string, updatedUser *model.User) error {
 	var existingUser model.User
 	
@@ -77,32 +93,27 @@ func (r *userRepository) Update(id string, updatedUser *model.User) error {
 		return DBError(err)
 	}
 
-	if updatedUser.FullName != "" {
-		existingUser.FullName = updatedUser.FullName
-	}
-
-	if updatedUser.PhoneNumber != "" {
-		existingUser.PhoneNumber = updatedUser.PhoneNumber
+	if updatedUser.Version != 0 && updatedUser.Version != existingUser.Version {
+		return errs.NewPreconditionFailedError("Profile was modified by another request")
 	}
 
-	if updatedUser.Organization != "" {
-		existingUser.Organization = updatedUser.Organization
-	}
-
-	if updatedUser.Bio != "" {
-		existingUser.Bio = updatedUser.Bio
-	}
-
-	if updatedUser.FullName != "" || updatedUser.PhoneNumber != "" || updatedUser.Organization != "" ||  updatedUser.Bio != "" {
-		existingUser.UpdatedAt = time.Now()
-	}
-
-	err = r.db.Transaction(func(tx *gorm.DB) error {
-		err := tx.Save(existingUser).Error
-		if err != nil {
-			return err
-		}
-		return nil
+	renamed := existingUser.FullName != updatedUser.FullName || existingUser.Slug == ""
+	existingUser.FullName = updatedUser.FullName
+	existingUser.PhoneNumber = updatedUser.PhoneNumber
+	existingUser.Organization = updatedUser.Organization
+	existingUser.Bio = updatedUser.Bio
+	existingUser.UpdatedAt = time.Now()
+
+	err = withSlugRetry(func() error {
+		return r.db.Transaction(func(tx *gorm.DB) error {
+			if renamed {
+				if _, err := renameSlug(tx, model.SlugResourceUser, existingUser.ID, existingUser.Slug, existingUser.FullName); err != nil {
+					return err
+				}
+			}
+
+			return updateProfile(tx, &existingUser)
+		})
 	})
 
 	if err != nil {
@@ -112,6 +123,26 @@ func (r *userRepository) Update(id string, updatedUser *model.User) error {
 	return nil
 }
 
+func updateProfile(tx *gorm.DB, existingUser *model.User) error {
+	result := tx.Model(&model.User{}).
+		Where("id = ? AND version = ?", existingUser.ID, existingUser.Version).
+		Updates(map[string]interface{}{
+			"full_name":    existingUser.FullName,
+			"phone_number": existingUser.PhoneNumber,
+			"organization": existingUser.Organization,
+			"bio":          existingUser.Bio,
+			"updated_at":   existingUser.UpdatedAt,
+			"version":      gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errs.NewPreconditionFailedError("Profile was modified by another request")
+	}
+	return nil
+}
+
 func (r *userRepository) Delete(id string) error {
     return DBError(r.db.Transaction(func(tx *gorm.DB) error {
         if err := tx.Delete(&model.User{}, "id = ?", id).Error; err != nil {
@@ -121,10 +152,19 @@ func (r *userRepository) Delete(id string) error {
     }))
 }
 
+// ResolveSlug returns the ID of the user with the slug, following the redirects
+// left by renames.
+func (r *userRepository) ResolveSlug(slug string) (string, error) {
+	return resolveSlug(r.db, model.SlugResourceUser, slug)
+}
+
 func (r *userRepository) ChangePassword(id string, password string) error {
 	userModel := r.db.Model(&model.User{})
 	err := r.db.Transaction(func(tx *gorm.DB) error {
-		err := userModel.Where("id = ?", id).Update("password", password).Error
+		err := userModel.Where("id = ?", id).Updates(map[string]interface{}{
+			"password": password,
+			"version":  gorm.Expr("version + 1"),
+		}).Error
 		if err != nil {
 			return err
 		}
@@ -139,7 +179,10 @@ func (r *userRepository) ChangePassword(id string, password string) error {
 
 func (r *userRepository) ChangePhotoProfile(id string, PhotoProfileURL string) error {
 	err := r.db.Transaction(func(tx *gorm.DB) error {
-		err := tx.Model(&model.User{}).Where("id = ?", id).Update("profile_image", PhotoProfileURL).Error
+		err := tx.Model(&model.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"profile_image": PhotoProfileURL,
+			"version":       gorm.Expr("version + 1"),
+		}).Error
 		return err
 	})
 
@@ -154,11 +197,59 @@ func (r *userRepository) ChangePhotoProfile(id string, PhotoProfileURL string) e
 
 
 
+// BumpTokenGeneration increments the user's token generation, invalidating every
+// access token issued before, and returns the new generation.
+func (r *userRepository) BumpTokenGeneration(id string) (int, error) {
+	var user model.User
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.User{}).Where("id = ?", id).
+			Update("token_generation", gorm.Expr("token_generation + 1")).Error
+		if err != nil {
+			return err
+		}
+		return tx.Select("token_generation").Where("id = ?", id).First(&user).Error
+	})
+
+	if err != nil {
+		return 0, DBError(err)
+	}
+
+	return user.TokenGeneration, nil
+}
+
+// MarkEmailVerified records that the user confirmed the given address. It is a no-op
+// if the address was verified before or is no longer the user's.
+func (r *userRepository) MarkEmailVerified(id string, email string) error {
+	err := r.db.Model(&model.User{}).
+		Where("id = ? AND email = ? AND email_verified_at IS NULL", id, email).
+		Update("email_verified_at", time.Now()).Error
+	if err != nil {
+		return DBError(err)
+	}
+
+	return nil
+}
+
 func DBError(err error) error {
     if err == nil {
         return nil // No error to handle
     }
 
+    var preconditionErr *errs.PreconditionFailedError
+    if errors.As(err, &preconditionErr) {
+        return preconditionErr
+    }
+
+    var conflictErr *errs.ConflictError
+    if errors.As(err, &conflictErr) {
+        return conflictErr
+    }
+
+    var notFoundErr *errs.NotFoundError
+    if errors.As(err, &notFoundErr) {
+        return notFoundErr
+    }
+
     switch {
     case errors.Is(err, gorm.ErrRecordNotFound):
         return errs.NewNotFoundError("Record not found")
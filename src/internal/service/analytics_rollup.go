@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/hafiztri123/src/internal/repository"
+)
+
+// AnalyticsRollup moves the live Redis counters of finished days into Postgres so
+// that Redis only holds the current day. A short grace period after midnight lets
+// in-flight writes for the previous day land first.
+type AnalyticsRollup struct {
+	analyticsRepository repository.AnalyticsRepository
+	grace               time.Duration
+}
+
+func NewAnalyticsRollup(analyticsRepo repository.AnalyticsRepository) *AnalyticsRollup {
+	return &AnalyticsRollup{
+		analyticsRepository: analyticsRepo,
+		grace:               5 * time.Minute,
+	}
+}
+
+func (j *AnalyticsRollup) Name() string {
+	return "analytics-rollup"
+}
+
+func (j *AnalyticsRollup) Run(ctx context.Context) error {
+	before := time.Now().UTC().Add(-j.grace).Truncate(24 * time.Hour)
+
+	days, err := j.analyticsRepository.ListLiveDays(ctx, before)
+	if err != nil {
+		return err
+	}
+
+	for _, day := range days {
+		if err := j.analyticsRepository.Rollup(ctx, day); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
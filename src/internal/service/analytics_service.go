@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/hafiztri123/src/internal/model"
+	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"github.com/hafiztri123/src/internal/repository"
+)
+
+const (
+	analyticsDateFormat  = "2006-01-02"
+	analyticsDefaultDays = 30
+	analyticsMaxDays     = 366
+)
+
+type AnalyticsService interface {
+	RecordView(ctx context.Context, eventID string, visitorID string)
+	RecordRegistration(ctx context.Context, eventID string)
+	RecordCheckIn(ctx context.Context, eventID string)
+	GetEventAnalytics(ctx context.Context, eventID string, userID string, input *model.AnalyticsInput) (*model.EventAnalytics, error)
+}
+
+type analyticsServiceImpl struct {
+	analyticsRepository repository.AnalyticsRepository
+	eventRepository     repository.EventRepository
+	trendingService     TrendingService
+}
+
+func NewAnalyticsService(analyticsRepo repository.AnalyticsRepository, eventRepo repository.EventRepository, trendingService TrendingService) AnalyticsService {
+	return &analyticsServiceImpl{
+		analyticsRepository: analyticsRepo,
+		eventRepository:     eventRepo,
+		trendingService:     trendingService,
+	}
+}
+
+// RecordView counts a page view of an event and feeds it to the trending ranking.
+// Tracking is best effort, so failures are logged rather than returned to the caller.
+func (s *analyticsServiceImpl) RecordView(ctx context.Context, eventID string, visitorID string) {
+	if err := s.analyticsRepository.RecordView(ctx, eventID, visitorID, time.Now()); err != nil {
+		log.Printf("[WARN] failed to record view of event %s: %v", eventID, err)
+	}
+
+	s.trendingService.Record(ctx, eventID, model.TrendingSignalView)
+}
+
+func (s *analyticsServiceImpl) RecordRegistration(ctx context.Context, eventID string) {
+	s.increment(ctx, eventID, model.MetricRegistrations)
+	s.trendingService.Record(ctx, eventID, model.TrendingSignalRegistration)
+}
+
+func (s *analyticsServiceImpl) RecordCheckIn(ctx context.Context, eventID string) {
+	s.increment(ctx, eventID, model.MetricCheckIns)
+}
+
+func (s *analyticsServiceImpl) increment(ctx context.Context, eventID string, metric string) {
+	if err := s.analyticsRepository.Increment(ctx, eventID, metric, time.Now()); err != nil {
+		log.Printf("[WARN] failed to record %s of event %s: %v", metric, eventID, err)
+	}
+}
+
+// GetEventAnalytics returns the daily series and the funnel of an event over the
+// requested days, the last 30 by default. Only the event creator may read them.
+func (s *analyticsServiceImpl) GetEventAnalytics(ctx context.Context, eventID string, userID string, input *model.AnalyticsInput) (*model.EventAnalytics, error) {
+	event, err := s.eventRepository.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if event.CreatorID != userID {
+		return nil, errs.NewForbiddenError("Only the event organizers can view its analytics")
+	}
+
+	to := input.To.UTC().Truncate(24 * time.Hour)
+	if input.To.IsZero() {
+		to = time.Now().UTC().Truncate(24 * time.Hour)
+	}
+
+	from := input.From.UTC().Truncate(24 * time.Hour)
+	if input.From.IsZero() {
+		from = to.AddDate(0, 0, -(analyticsDefaultDays - 1))
+	}
+
+	if from.After(to) {
+		return nil, errs.NewBadRequestError("from must not be after to")
+	}
+
+	if to.Sub(from) >= analyticsMaxDays*24*time.Hour {
+		return nil, errs.NewBadRequestError("Range must not exceed 366 days")
+	}
+
+	stats, uniqueVisitors, err := s.analyticsRepository.DailyStats(ctx, event.ID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	analytics := &model.EventAnalytics{
+		EventID: event.ID,
+		From:    from.Format(analyticsDateFormat),
+		To:      to.Format(analyticsDateFormat),
+		Series:  make([]model.AnalyticsDay, 0, len(stats)),
+	}
+
+	funnel := &analytics.Funnel
+	funnel.UniqueVisitors = uniqueVisitors
+
+	for _, stat := range stats {
+		analytics.Series = append(analytics.Series, model.AnalyticsDay{
+			Date:           stat.Day.Format(analyticsDateFormat),
+			Views:          stat.Views,
+			UniqueVisitors: stat.UniqueVisitors,
+			Registrations:  stat.Registrations,
+			CheckIns:       stat.CheckIns,
+		})
+
+		funnel.Views += stat.Views
+		funnel.Registrations += stat.Registrations
+		funnel.CheckIns += stat.CheckIns
+	}
+
+	funnel.VisitorToRegistration = ratio(funnel.Registrations, funnel.UniqueVisitors)
+	funnel.RegistrationToCheckIn = ratio(funnel.CheckIns, funnel.Registrations)
+
+	return analytics, nil
+}
+
+func ratio(part, whole int64) float64 {
+	if whole == 0 {
+		return 0
+	}
+	return float64(part) / float64(whole)
+}
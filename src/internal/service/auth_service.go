@@ -1,41 +1,69 @@
 package service
 
 import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/hafiztri123/src/internal/model"
 	"github.com/hafiztri123/src/internal/pkg/config"
 	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"github.com/hafiztri123/src/internal/pkg/mailer"
 	"github.com/hafiztri123/src/internal/repository"
 	"golang.org/x/crypto/bcrypt"
 )
 
 // AuthService defines the interface for authentication-related service operations.
 type AuthService interface {
-    Register(input *model.RegisterInput) error
-    Login(input *model.LoginInput) (*model.LoginResponse, error)
-
+    Register(ctx context.Context, input *model.RegisterInput) error
+    VerifyEmail(ctx context.Context, input *model.VerifyEmailInput) error
+    ResendVerification(ctx context.Context, userID string) error
+    ForgotPassword(ctx context.Context, input *model.ForgotPasswordInput) error
+    ResetPassword(ctx context.Context, input *model.ResetPasswordInput) error
+    Login(ctx context.Context, input *model.LoginInput) (*model.LoginResponse, error)
+    Refresh(ctx context.Context, input *model.RefreshInput) (*model.LoginResponse, error)
+    Logout(ctx context.Context, userID string, jti string, expiresAt time.Time, input *model.LogoutInput) error
+    IsTokenRevoked(ctx context.Context, jti string, userID string, generation int) (bool, error)
 }
 
 // AuthService implements the AuthService interface.
 type authService struct {
-    userRepo repository.UserRepository
-    config   *config.AuthConfig
+    userRepo          repository.UserRepository
+    refreshTokenRepo  repository.RefreshTokenRepository
+    passwordResetRepo repository.PasswordResetRepository
+    revocationRepo    repository.TokenRevocationRepository
+    mailer            mailer.Mailer
+    config            *config.AuthConfig
 }
 
 // NewAuthService creates a new instance of AuthService.
-func NewAuthService(userRepo repository.UserRepository, config *config.AuthConfig) AuthService {
+func NewAuthService(userRepo repository.UserRepository, refreshTokenRepo repository.RefreshTokenRepository, passwordResetRepo repository.PasswordResetRepository, revocationRepo repository.TokenRevocationRepository, mailer mailer.Mailer, config *config.AuthConfig) AuthService {
     return &authService{
-        userRepo: userRepo,
-        config:   config,
+        userRepo:          userRepo,
+        refreshTokenRepo:  refreshTokenRepo,
+        passwordResetRepo: passwordResetRepo,
+        revocationRepo:    revocationRepo,
+        mailer:            mailer,
+        config:            config,
     }
 }
 
 var _ AuthService = (*authService)(nil)
 
-// Register registers a new user with the provided input.
-func (s *authService) Register(input *model.RegisterInput) error {
+// Register registers a new user with the provided input and emails them a link to
+// verify their address.
+func (s *authService) Register(ctx context.Context, input *model.RegisterInput) error {
     emailExist, err := s.userRepo.IsEmailUnique(input.Email)
     if err != nil {
         return err
@@ -63,10 +91,77 @@ func (s *authService) Register(input *model.RegisterInput) error {
         return err
     }
 
+    // The account exists at this point, so a delivery failure does not fail the
+    // registration; the user can ask for the link again once logged in.
+    _ = s.sendVerification(ctx, user)
+
     return nil
 }
 
-func (s *authService) Login(input *model.LoginInput) (*model.LoginResponse, error) {
+// VerifyEmail marks the user's email as verified if the token is valid, unexpired
+// and was issued for the address the user still has.
+func (s *authService) VerifyEmail(ctx context.Context, input *model.VerifyEmailInput) error {
+    userID, expiresAt, ok := parseEmailVerification(input.Token)
+    if !ok {
+        return errs.NewBadRequestError("Invalid verification link")
+    }
+
+    user, err := s.userRepo.GetByID(userID)
+    var notFoundErr *errs.NotFoundError
+    if errors.As(err, &notFoundErr) {
+        return errs.NewBadRequestError("Invalid verification link")
+    }
+    if err != nil {
+        return err
+    }
+
+    expected := signEmailVerification(s.config.JWTSecret, user.ID, user.Email, expiresAt)
+    if !hmac.Equal([]byte(expected), []byte(input.Token)) {
+        return errs.NewBadRequestError("Invalid verification link")
+    }
+
+    if !expiresAt.After(time.Now()) {
+        return errs.NewBadRequestError("Verification link has expired")
+    }
+
+    return s.userRepo.MarkEmailVerified(user.ID, user.Email)
+}
+
+// ResendVerification emails the user a new verification link.
+func (s *authService) ResendVerification(ctx context.Context, userID string) error {
+    user, err := s.userRepo.GetByID(userID)
+    if err != nil {
+        return err
+    }
+
+    if user.EmailVerifiedAt != nil {
+        return errs.NewConflictError("Email is already verified")
+    }
+
+    if err := s.sendVerification(ctx, user); err != nil {
+        return errs.NewInternalServerError(err.Error())
+    }
+
+    return nil
+}
+
+// sendVerification emails the user a signed link that verifies their current address.
+func (s *authService) sendVerification(ctx context.Context, user *model.User) error {
+    expiresAt := time.Now().Add(s.emailVerificationTTL())
+    token := signEmailVerification(s.config.JWTSecret, user.ID, user.Email, expiresAt)
+
+    return s.mailer.Send(ctx, mailer.Message{
+        To:      user.Email,
+        Subject: "Verify your email address",
+        Body: fmt.Sprintf(
+            "Hi %s,\n\nConfirm your email address by opening the link below. It expires on %s.\n\n%s\n\nIf you did not create an account, you can ignore this email.\n",
+            user.FullName, expiresAt.UTC().Format(time.RFC1123), s.frontendLink("/verify-email", token),
+        ),
+    })
+}
+
+// Login checks the user's credentials and starts a new refresh token family.
+func (s *authService) Login(ctx context.Context, input *model.LoginInput) (*model.LoginResponse, error) {
     existingUser, err := s.userRepo.GetByEmail(input.Email)
     if err != nil {
         return nil, err
@@ -79,24 +174,280 @@ func (s *authService) Login(input *model.LoginInput) (*model.LoginResponse, erro
         return nil, errs.NewUnauthorizedError("Invalid credentials")
     }
 
-    token, err := s.generateToken(existingUser)
+    return s.issueTokens(ctx, existingUser, uuid.NewString(), nil)
+}
+
+// Refresh exchanges a refresh token for a new access token and a new refresh
+// token. Each refresh token works once: presenting one that was already used
+// revokes every token of its family, logging out whoever holds them.
+func (s *authService) Refresh(ctx context.Context, input *model.RefreshInput) (*model.LoginResponse, error) {
+    token, err := s.refreshTokenRepo.GetByHash(ctx, hashToken(input.RefreshToken))
+    var notFoundErr *errs.NotFoundError
+    if errors.As(err, &notFoundErr) {
+        return nil, errs.NewUnauthorizedError("Invalid refresh token")
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    if token.RevokedAt != nil {
+        return nil, errs.NewUnauthorizedError("Refresh token has been revoked")
+    }
+
+    if token.UsedAt != nil {
+        return nil, s.revokeReused(ctx, token)
+    }
+
+    if !token.ExpiresAt.After(time.Now()) {
+        return nil, errs.NewUnauthorizedError("Refresh token has expired")
+    }
+
+    user, err := s.userRepo.GetByID(token.UserID)
+    if errors.As(err, &notFoundErr) {
+        return nil, errs.NewUnauthorizedError("Invalid refresh token")
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    response, err := s.issueTokens(ctx, user, token.FamilyID, token)
+    var conflictErr *errs.ConflictError
+    if errors.As(err, &conflictErr) {
+        return nil, s.revokeReused(ctx, token)
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    return response, nil
+}
+
+// Logout revokes the access token it is called with until the token expires. A
+// refresh token in the input is revoked with its whole family, so the session
+// cannot be renewed. With All set, the user's token generation is bumped, which
+// invalidates every access token issued so far, and all refresh tokens are revoked.
+func (s *authService) Logout(ctx context.Context, userID string, jti string, expiresAt time.Time, input *model.LogoutInput) error {
+    if err := s.revocationRepo.RevokeJTI(ctx, jti, expiresAt); err != nil {
+        return errs.NewInternalServerError(err.Error())
+    }
+
+    if input.RefreshToken != "" {
+        token, err := s.refreshTokenRepo.GetByHash(ctx, hashToken(input.RefreshToken))
+        var notFoundErr *errs.NotFoundError
+        if err != nil && !errors.As(err, &notFoundErr) {
+            return err
+        }
+
+        if err == nil && token.UserID == userID {
+            if err := s.refreshTokenRepo.RevokeFamily(ctx, token.FamilyID); err != nil {
+                return err
+            }
+        }
+    }
+
+    if !input.All {
+        return nil
+    }
+
+    return s.revokeSessions(ctx, userID)
+}
+
+// revokeSessions logs the user out everywhere: their token generation is bumped,
+// which invalidates every access token issued so far, and all their refresh
+// tokens are revoked.
+func (s *authService) revokeSessions(ctx context.Context, userID string) error {
+    generation, err := s.userRepo.BumpTokenGeneration(userID)
+    if err != nil {
+        return err
+    }
+
+    if err := s.revocationRepo.SetGeneration(ctx, userID, generation); err != nil {
+        return errs.NewInternalServerError(err.Error())
+    }
+
+    return s.refreshTokenRepo.RevokeUser(ctx, userID)
+}
+
+// ForgotPassword emails a single-use password reset link to the address if it
+// belongs to a user. It succeeds either way. Only the user lookup happens in the
+// request; creating the token and sending the email happen in the background, so
+// the response takes about as long whether or not the account exists.
+func (s *authService) ForgotPassword(ctx context.Context, input *model.ForgotPasswordInput) error {
+    user, err := s.userRepo.GetByEmail(input.Email)
+    var notFoundErr *errs.NotFoundError
+    if errors.As(err, &notFoundErr) {
+        return nil
+    }
+    if err != nil {
+        return err
+    }
+
+    go func() {
+        if err := s.sendPasswordReset(context.WithoutCancel(ctx), user); err != nil {
+            log.Printf("[WARN] failed to send password reset to user %s: %v", user.ID, err)
+        }
+    }()
+
+    return nil
+}
+
+// sendPasswordReset stores a new reset token for the user and emails them the link.
+func (s *authService) sendPasswordReset(ctx context.Context, user *model.User) error {
+    token, err := newOpaqueToken()
+    if err != nil {
+        return err
+    }
+
+    now := time.Now()
+    reset := &model.PasswordResetToken{
+        UserID:    user.ID,
+        TokenHash: hashToken(token),
+        ExpiresAt: now.Add(s.passwordResetTTL()),
+        CreatedAt: now,
+    }
+
+    if err := s.passwordResetRepo.Create(ctx, reset); err != nil {
+        return err
+    }
+
+    return s.mailer.Send(ctx, mailer.Message{
+        To:      user.Email,
+        Subject: "Reset your password",
+        Body: fmt.Sprintf(
+            "Hi %s,\n\nSomeone asked to reset the password of your account. Open the link below to choose a new one. It can be used once and expires on %s.\n\n%s\n\nIf you did not ask for this, you can ignore this email; your password stays the same.\n",
+            user.FullName, reset.ExpiresAt.UTC().Format(time.RFC1123), s.frontendLink("/reset-password", token),
+        ),
+    })
+}
+
+// ResetPassword sets a new password with a reset token. The token is used up, and
+// the user is logged out of every session, including any an attacker may hold.
+func (s *authService) ResetPassword(ctx context.Context, input *model.ResetPasswordInput) error {
+    reset, err := s.passwordResetRepo.GetByHash(ctx, hashToken(input.Token))
+    var notFoundErr *errs.NotFoundError
+    if errors.As(err, &notFoundErr) {
+        return errs.NewBadRequestError("Invalid reset link")
+    }
+    if err != nil {
+        return err
+    }
+
+    if reset.UsedAt != nil || !reset.ExpiresAt.After(time.Now()) {
+        return errs.NewBadRequestError("Reset link has already been used or has expired")
+    }
+
+    encryptedPassword, err := bcrypt.GenerateFromPassword([]byte(input.NewPassword), bcrypt.DefaultCost)
+    if err != nil {
+        return errs.NewInternalServerError(err.Error())
+    }
+
+    // The password change and the revocation of the user's sessions are committed
+    // together; only the cached generation is updated afterwards.
+    generation, err := s.passwordResetRepo.Consume(ctx, reset, string(encryptedPassword))
+    var conflictErr *errs.ConflictError
+    if errors.As(err, &conflictErr) {
+        return errs.NewBadRequestError(conflictErr.Message)
+    }
+    if err != nil {
+        return err
+    }
+
+    if err := s.revocationRepo.SetGeneration(ctx, reset.UserID, generation); err != nil {
+        return errs.NewInternalServerError(err.Error())
+    }
+
+    return nil
+}
+
+// IsTokenRevoked reports whether an access token was logged out, either by its
+// own ID or by a later "log out everywhere" of its user. The user's generation is
+// cached in Redis; when it is missing it is loaded from the database and cached,
+// without replacing a newer value written by a concurrent logout.
+func (s *authService) IsTokenRevoked(ctx context.Context, jti string, userID string, generation int) (bool, error) {
+    revoked, current, known, err := s.revocationRepo.Lookup(ctx, jti, userID)
+    if err != nil {
+        return false, err
+    }
+    if revoked {
+        return true, nil
+    }
+
+    if !known {
+        user, err := s.userRepo.GetByID(userID)
+        var notFoundErr *errs.NotFoundError
+        if errors.As(err, &notFoundErr) {
+            return true, nil
+        }
+        if err != nil {
+            return false, err
+        }
+
+        current = user.TokenGeneration
+        if err := s.revocationRepo.SetGeneration(ctx, userID, current); err != nil {
+            return false, err
+        }
+    }
+
+    return generation < current, nil
+}
+
+// revokeReused revokes the family of a refresh token that was presented after it
+// had been used.
+func (s *authService) revokeReused(ctx context.Context, token *model.RefreshToken) error {
+    if err := s.refreshTokenRepo.RevokeFamily(ctx, token.FamilyID); err != nil {
+        return err
+    }
+    return errs.NewUnauthorizedError("Refresh token has already been used; please log in again")
+}
+
+// issueTokens signs an access token and stores a new refresh token in the family.
+// When rotating, the used token is retired in the same step.
+func (s *authService) issueTokens(ctx context.Context, user *model.User, familyID string, rotated *model.RefreshToken) (*model.LoginResponse, error) {
+    accessToken, err := s.generateToken(user)
+    if err != nil {
+        return nil, errs.NewInternalServerError(err.Error())
+    }
+
+    refreshToken, err := newOpaqueToken()
     if err != nil {
         return nil, errs.NewInternalServerError(err.Error())
     }
 
+    now := time.Now()
+    stored := &model.RefreshToken{
+        UserID:    user.ID,
+        FamilyID:  familyID,
+        TokenHash: hashToken(refreshToken),
+        ExpiresAt: now.Add(s.refreshTokenTTL()),
+        CreatedAt: now,
+    }
+
+    if rotated != nil {
+        err = s.refreshTokenRepo.Rotate(ctx, rotated, stored)
+    } else {
+        err = s.refreshTokenRepo.Create(ctx, stored)
+    }
+    if err != nil {
+        return nil, err
+    }
+
     return &model.LoginResponse{
-        Token: token,
+        Token:        accessToken,
+        ExpiresIn:    int64(s.accessTokenTTL().Seconds()),
+        RefreshToken: refreshToken,
     }, nil
 }
 
 // generateToken generates a signed JWT token for the given user.
 func (s *authService) generateToken(user *model.User) (string, error) {
     claims := jwt.MapClaims{
+        "jti":     uuid.NewString(),
+        "gen":     user.TokenGeneration,
         "user_id": user.ID,
         "email":   user.Email,
         "nbf":     time.Now().Unix(),
         "iat":     time.Now().Unix(),
-        "exp":     time.Now().Add(time.Hour * time.Duration(s.config.TokenExpiryHrs)).Unix(),
+        "exp":     time.Now().Add(s.accessTokenTTL()).Unix(),
         "role":    user.Role,
     }
 
@@ -107,4 +458,58 @@ func (s *authService) generateToken(user *model.User) (string, error) {
     }
 
     return signedToken, nil
-}
\ No newline at end of file
+}
+
+func (s *authService) accessTokenTTL() time.Duration {
+    if s.config.AccessTokenMinutes > 0 {
+        return time.Duration(s.config.AccessTokenMinutes) * time.Minute
+    }
+    return 15 * time.Minute
+}
+
+func (s *authService) emailVerificationTTL() time.Duration {
+    if s.config.EmailVerificationHours > 0 {
+        return time.Duration(s.config.EmailVerificationHours) * time.Hour
+    }
+    return 48 * time.Hour
+}
+
+func (s *authService) passwordResetTTL() time.Duration {
+    if s.config.PasswordResetMinutes > 0 {
+        return time.Duration(s.config.PasswordResetMinutes) * time.Minute
+    }
+    return time.Hour
+}
+
+// frontendLink builds a link to a page of the frontend that carries a token.
+func (s *authService) frontendLink(path string, token string) string {
+    base := strings.TrimSuffix(s.config.FrontendURL, "/")
+    if base == "" {
+        base = "http://localhost:5173"
+    }
+    return base + path + "?token=" + url.QueryEscape(token)
+}
+
+func (s *authService) refreshTokenTTL() time.Duration {
+    if s.config.RefreshTokenDays > 0 {
+        return time.Duration(s.config.RefreshTokenDays) * 24 * time.Hour
+    }
+    return 30 * 24 * time.Hour
+}
+
+// newOpaqueToken returns a random opaque token, used for refresh tokens and
+// password reset links.
+func newOpaqueToken() (string, error) {
+    buf := make([]byte, 32)
+    if _, err := rand.Read(buf); err != nil {
+        return "", err
+    }
+    return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashToken returns the form an opaque token is stored and looked up in.
+// The tokens are random, so a plain SHA-256 is enough.
+func hashToken(token string) string {
+    sum := sha256.Sum256([]byte(token))
+    return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/hafiztri123/src/internal/repository"
+)
+
+// AuthTokenPurger deletes refresh tokens and password reset tokens that have
+// expired. Expired tokens are refused anyway, so this only keeps the tables from
+// growing.
+type AuthTokenPurger struct {
+	refreshTokenRepository  repository.RefreshTokenRepository
+	passwordResetRepository repository.PasswordResetRepository
+}
+
+func NewAuthTokenPurger(refreshTokenRepo repository.RefreshTokenRepository, passwordResetRepo repository.PasswordResetRepository) *AuthTokenPurger {
+	return &AuthTokenPurger{
+		refreshTokenRepository:  refreshTokenRepo,
+		passwordResetRepository: passwordResetRepo,
+	}
+}
+
+func (p *AuthTokenPurger) Name() string {
+	return "auth-token-purge"
+}
+
+func (p *AuthTokenPurger) Run(ctx context.Context) error {
+	now := time.Now()
+
+	if _, err := p.refreshTokenRepository.DeleteExpired(ctx, now); err != nil {
+		return err
+	}
+
+	_, err := p.passwordResetRepository.DeleteExpired(ctx, now)
+	return err
+}
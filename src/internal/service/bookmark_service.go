@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/hafiztri123/src/internal/model"
+	"github.com/hafiztri123/src/internal/repository"
+)
+
+type BookmarkService interface {
+	AddBookmark(ctx context.Context, eventID string, userID string) (*model.Bookmark, error)
+	RemoveBookmark(ctx context.Context, eventID string, userID string) error
+	ListBookmarks(ctx context.Context, userID string) ([]*model.Bookmark, error)
+}
+
+type bookmarkServiceImpl struct {
+	bookmarkRepository repository.BookmarkRepository
+	eventRepository    repository.EventRepository
+	trendingService    TrendingService
+}
+
+func NewBookmarkService(bookmarkRepo repository.BookmarkRepository, eventRepo repository.EventRepository, trendingService TrendingService) BookmarkService {
+	return &bookmarkServiceImpl{
+		bookmarkRepository: bookmarkRepo,
+		eventRepository:    eventRepo,
+		trendingService:    trendingService,
+	}
+}
+
+// AddBookmark saves an event for the user. Bookmarking an event again is a no-op
+// and does not count towards its trending score a second time.
+func (s *bookmarkServiceImpl) AddBookmark(ctx context.Context, eventID string, userID string) (*model.Bookmark, error) {
+	event, err := s.eventRepository.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	bookmark := &model.Bookmark{
+		UserID:    userID,
+		EventID:   event.ID,
+		CreatedAt: time.Now(),
+	}
+
+	created, err := s.bookmarkRepository.Create(ctx, bookmark)
+	if err != nil {
+		return nil, err
+	}
+
+	if created {
+		s.trendingService.Record(ctx, event.ID, model.TrendingSignalBookmark)
+	}
+
+	return bookmark, nil
+}
+
+func (s *bookmarkServiceImpl) RemoveBookmark(ctx context.Context, eventID string, userID string) error {
+	return s.bookmarkRepository.Delete(ctx, userID, eventID)
+}
+
+func (s *bookmarkServiceImpl) ListBookmarks(ctx context.Context, userID string) ([]*model.Bookmark, error) {
+	return s.bookmarkRepository.ListByUser(ctx, userID)
+}
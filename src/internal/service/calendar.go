@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/hafiztri123/src/internal/model"
+	errs "github.com/hafiztri123/src/internal/pkg/error"
+)
+
+// maxCalendarRange bounds a single calendar request so one call cannot load years of
+// events.
+const maxCalendarRange = 366 * 24 * time.Hour
+
+// GetCalendar returns the events overlapping [From, To) grouped into day, week or
+// month buckets in the requested time zone.
+func (s *eventService) GetCalendar(ctx context.Context, input *model.CalendarInput) (*model.CalendarOutput, error) {
+	if input.Location == nil {
+		input.Location = time.UTC
+	}
+
+	from := input.From.In(input.Location)
+	to := input.To.In(input.Location)
+	if to.Sub(from) > maxCalendarRange {
+		return nil, errs.NewBadRequestError("Calendar range cannot exceed 366 days")
+	}
+
+	events, err := s.eventRepository.ListForCalendar(ctx, &input.Filters, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	buckets := []*model.CalendarBucket{}
+	index := map[int64]*model.CalendarBucket{}
+
+	for start := truncateToBucket(from, input.Granularity); start.Before(to); start = nextBucket(start, input.Granularity) {
+		bucket := &model.CalendarBucket{
+			Label:  bucketLabel(start, input.Granularity),
+			Start:  start,
+			End:    nextBucket(start, input.Granularity),
+			Events: []*model.Event{},
+		}
+		buckets = append(buckets, bucket)
+		index[start.Unix()] = bucket
+	}
+
+	for _, event := range events {
+		first := event.StartDate.In(input.Location)
+		if first.Before(from) {
+			first = from
+		}
+
+		last := event.EndDate.In(input.Location)
+		if last.After(to) {
+			last = to
+		}
+
+		for start := truncateToBucket(first, input.Granularity); start.Before(last); start = nextBucket(start, input.Granularity) {
+			if bucket, ok := index[start.Unix()]; ok {
+				bucket.Events = append(bucket.Events, event)
+				bucket.Count++
+			}
+		}
+	}
+
+	return &model.CalendarOutput{
+		From:        from,
+		To:          to,
+		Granularity: input.Granularity,
+		TimeZone:    input.Location.String(),
+		Buckets:     buckets,
+	}, nil
+}
+
+// truncateToBucket returns the start of the bucket containing t, in t's location.
+// Weeks start on Monday.
+func truncateToBucket(t time.Time, granularity string) time.Time {
+	year, month, day := t.Date()
+	switch granularity {
+	case model.CalendarMonth:
+		return startOfDay(year, month, 1, t.Location())
+	case model.CalendarWeek:
+		offset := (int(t.Weekday()) + 6) % 7
+		return startOfDay(year, month, day-offset, t.Location())
+	default:
+		return startOfDay(year, month, day, t.Location())
+	}
+}
+
+// nextBucket returns the start of the bucket after the one starting at start. It is
+// worked out from the date rather than by adding to start, since a bucket does not
+// always start at midnight.
+func nextBucket(start time.Time, granularity string) time.Time {
+	year, month, day := start.Date()
+	switch granularity {
+	case model.CalendarMonth:
+		return startOfDay(year, month+1, 1, start.Location())
+	case model.CalendarWeek:
+		return startOfDay(year, month, day+7, start.Location())
+	default:
+		return startOfDay(year, month, day+1, start.Location())
+	}
+}
+
+// startOfDay returns the first instant of the given day. Where a clock change skips
+// midnight, time.Date answers with a time on the previous day, and the day starts
+// when the clocks jump instead.
+func startOfDay(year int, month time.Month, day int, location *time.Location) time.Time {
+	start := time.Date(year, month, day, 0, 0, 0, 0, location)
+
+	_, _, wantDay := time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Date()
+	if start.Day() != wantDay {
+		_, start = start.ZoneBounds()
+	}
+
+	return start
+}
+
+func bucketLabel(start time.Time, granularity string) string {
+	switch granularity {
+	case model.CalendarMonth:
+		return start.Format("2006-01")
+	default:
+		return start.Format("2006-01-02")
+	}
+}
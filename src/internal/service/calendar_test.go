@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/hafiztri123/src/internal/model"
+	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"github.com/hafiztri123/src/internal/repository"
+)
+
+// calendarEvents serves ListForCalendar from a fixed list. Calling any other
+// repository method panics.
+type calendarEvents struct {
+	repository.EventRepository
+	events []*model.Event
+}
+
+func (r *calendarEvents) ListForCalendar(ctx context.Context, params *model.SearchEventsInput, from, to time.Time) ([]*model.Event, error) {
+	var events []*model.Event
+	for _, event := range r.events {
+		if event.StartDate.Before(to) && event.EndDate.After(from) {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("LoadLocation(%q) error = %v", name, err)
+	}
+	return location
+}
+
+func calendarEvent(id, start, end string) *model.Event {
+	startDate, err := time.Parse(time.RFC3339, start)
+	if err != nil {
+		panic(err)
+	}
+	endDate, err := time.Parse(time.RFC3339, end)
+	if err != nil {
+		panic(err)
+	}
+	return &model.Event{ID: id, StartDate: startDate, EndDate: endDate}
+}
+
+type wantBucket struct {
+	label  string
+	length time.Duration
+	events []string
+}
+
+func TestGetCalendarBuckets(t *testing.T) {
+	newYork := mustLoadLocation(t, "America/New_York")
+	berlin := mustLoadLocation(t, "Europe/Berlin")
+	saoPaulo := mustLoadLocation(t, "America/Sao_Paulo")
+	kolkata := mustLoadLocation(t, "Asia/Kolkata")
+
+	tests := []struct {
+		name        string
+		location    *time.Location
+		granularity string
+		from, to    time.Time
+		events      []*model.Event
+		want        []wantBucket
+	}{
+		{
+			name:        "events spanning days appear in each day",
+			location:    time.UTC,
+			granularity: model.CalendarDay,
+			from:        time.Date(2025, 7, 14, 0, 0, 0, 0, time.UTC),
+			to:          time.Date(2025, 7, 17, 0, 0, 0, 0, time.UTC),
+			events: []*model.Event{
+				calendarEvent("overnight", "2025-07-14T22:00:00Z", "2025-07-15T02:00:00Z"),
+				calendarEvent("afternoon", "2025-07-16T13:00:00Z", "2025-07-16T17:00:00Z"),
+			},
+			want: []wantBucket{
+				{"2025-07-14", 24 * time.Hour, []string{"overnight"}},
+				{"2025-07-15", 24 * time.Hour, []string{"overnight"}},
+				{"2025-07-16", 24 * time.Hour, []string{"afternoon"}},
+			},
+		},
+		{
+			name:        "an event ending at midnight stays out of the next day",
+			location:    time.UTC,
+			granularity: model.CalendarDay,
+			from:        time.Date(2025, 7, 14, 0, 0, 0, 0, time.UTC),
+			to:          time.Date(2025, 7, 16, 0, 0, 0, 0, time.UTC),
+			events: []*model.Event{
+				calendarEvent("evening", "2025-07-14T18:00:00Z", "2025-07-15T00:00:00Z"),
+			},
+			want: []wantBucket{
+				{"2025-07-14", 24 * time.Hour, []string{"evening"}},
+				{"2025-07-15", 24 * time.Hour, nil},
+			},
+		},
+		{
+			name:        "events are bucketed by the local date",
+			location:    newYork,
+			granularity: model.CalendarDay,
+			from:        time.Date(2025, 2, 28, 0, 0, 0, 0, newYork),
+			to:          time.Date(2025, 3, 2, 0, 0, 0, 0, newYork),
+			events: []*model.Event{
+				calendarEvent("late", "2025-03-01T02:00:00Z", "2025-03-01T04:00:00Z"),
+			},
+			want: []wantBucket{
+				{"2025-02-28", 24 * time.Hour, []string{"late"}},
+				{"2025-03-01", 24 * time.Hour, nil},
+			},
+		},
+		{
+			name:        "half-hour offsets",
+			location:    kolkata,
+			granularity: model.CalendarDay,
+			from:        time.Date(2025, 7, 15, 0, 0, 0, 0, kolkata),
+			to:          time.Date(2025, 7, 17, 0, 0, 0, 0, kolkata),
+			events: []*model.Event{
+				calendarEvent("utc-evening", "2025-07-15T19:00:00Z", "2025-07-15T20:00:00Z"),
+			},
+			want: []wantBucket{
+				{"2025-07-15", 24 * time.Hour, nil},
+				{"2025-07-16", 24 * time.Hour, []string{"utc-evening"}},
+			},
+		},
+		{
+			name:        "spring forward day is 23 hours",
+			location:    newYork,
+			granularity: model.CalendarDay,
+			from:        time.Date(2025, 3, 8, 0, 0, 0, 0, newYork),
+			to:          time.Date(2025, 3, 11, 0, 0, 0, 0, newYork),
+			events: []*model.Event{
+				calendarEvent("night", "2025-03-09T06:30:00Z", "2025-03-09T07:30:00Z"),
+			},
+			want: []wantBucket{
+				{"2025-03-08", 24 * time.Hour, nil},
+				{"2025-03-09", 23 * time.Hour, []string{"night"}},
+				{"2025-03-10", 24 * time.Hour, nil},
+			},
+		},
+		{
+			name:        "fall back day is 25 hours",
+			location:    newYork,
+			granularity: model.CalendarDay,
+			from:        time.Date(2025, 11, 1, 0, 0, 0, 0, newYork),
+			to:          time.Date(2025, 11, 4, 0, 0, 0, 0, newYork),
+			events: []*model.Event{
+				// 23:30 local on November 2nd, after the clocks went back.
+				calendarEvent("late", "2025-11-03T04:30:00Z", "2025-11-03T04:45:00Z"),
+			},
+			want: []wantBucket{
+				{"2025-11-01", 24 * time.Hour, nil},
+				{"2025-11-02", 25 * time.Hour, []string{"late"}},
+				{"2025-11-03", 24 * time.Hour, nil},
+			},
+		},
+		{
+			name:        "a day without a midnight starts at one",
+			location:    saoPaulo,
+			granularity: model.CalendarDay,
+			from:        time.Date(2018, 11, 3, 0, 0, 0, 0, saoPaulo),
+			to:          time.Date(2018, 11, 6, 0, 0, 0, 0, saoPaulo),
+			events: []*model.Event{
+				calendarEvent("early", "2018-11-04T01:30:00-02:00", "2018-11-04T02:00:00-02:00"),
+			},
+			want: []wantBucket{
+				{"2018-11-03", 24 * time.Hour, nil},
+				{"2018-11-04", 23 * time.Hour, []string{"early"}},
+				{"2018-11-05", 24 * time.Hour, nil},
+			},
+		},
+		{
+			name:        "weeks start on monday",
+			location:    time.UTC,
+			granularity: model.CalendarWeek,
+			from:        time.Date(2025, 7, 16, 0, 0, 0, 0, time.UTC),
+			to:          time.Date(2025, 7, 28, 0, 0, 0, 0, time.UTC),
+			events: []*model.Event{
+				calendarEvent("sunday", "2025-07-20T10:00:00Z", "2025-07-20T12:00:00Z"),
+				calendarEvent("monday", "2025-07-21T10:00:00Z", "2025-07-21T12:00:00Z"),
+			},
+			want: []wantBucket{
+				{"2025-07-14", 7 * 24 * time.Hour, []string{"sunday"}},
+				{"2025-07-21", 7 * 24 * time.Hour, []string{"monday"}},
+			},
+		},
+		{
+			name:        "week across the DST change",
+			location:    berlin,
+			granularity: model.CalendarWeek,
+			from:        time.Date(2025, 3, 24, 0, 0, 0, 0, berlin),
+			to:          time.Date(2025, 4, 7, 0, 0, 0, 0, berlin),
+			want: []wantBucket{
+				{"2025-03-24", 7*24*time.Hour - time.Hour, nil},
+				{"2025-03-31", 7 * 24 * time.Hour, nil},
+			},
+		},
+		{
+			name:        "months follow the calendar and DST",
+			location:    berlin,
+			granularity: model.CalendarMonth,
+			from:        time.Date(2025, 2, 10, 0, 0, 0, 0, berlin),
+			to:          time.Date(2025, 4, 10, 0, 0, 0, 0, berlin),
+			events: []*model.Event{
+				// Midnight of March 1st in Berlin, still February in UTC.
+				calendarEvent("first", "2025-02-28T23:00:00Z", "2025-02-28T23:30:00Z"),
+				calendarEvent("long", "2025-03-30T10:00:00Z", "2025-04-02T10:00:00Z"),
+			},
+			want: []wantBucket{
+				{"2025-02", 28 * 24 * time.Hour, nil},
+				{"2025-03", 31*24*time.Hour - time.Hour, []string{"first", "long"}},
+				{"2025-04", 30 * 24 * time.Hour, []string{"long"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &eventService{eventRepository: &calendarEvents{events: tt.events}}
+
+			got, err := s.GetCalendar(context.Background(), &model.CalendarInput{
+				From:        tt.from,
+				To:          tt.to,
+				Granularity: tt.granularity,
+				Location:    tt.location,
+			})
+			if err != nil {
+				t.Fatalf("GetCalendar() error = %v", err)
+			}
+
+			if got.TimeZone != tt.location.String() {
+				t.Errorf("TimeZone = %q, want %q", got.TimeZone, tt.location.String())
+			}
+			if len(got.Buckets) != len(tt.want) {
+				t.Fatalf("got %d buckets, want %d", len(got.Buckets), len(tt.want))
+			}
+
+			for i, want := range tt.want {
+				bucket := got.Buckets[i]
+				if bucket.Label != want.label {
+					t.Errorf("bucket %d label = %q, want %q", i, bucket.Label, want.label)
+				}
+				if length := bucket.End.Sub(bucket.Start); length != want.length {
+					t.Errorf("bucket %s is %v long, want %v", want.label, length, want.length)
+				}
+				if i > 0 && !got.Buckets[i-1].End.Equal(bucket.Start) {
+					t.Errorf("bucket %s does not start where the previous one ends", want.label)
+				}
+				if bucket.Count != len(want.events) || len(bucket.Events) != len(want.events) {
+					t.Fatalf("bucket %s has %d events, want %d", want.label, len(bucket.Events), len(want.events))
+				}
+				for j, id := range want.events {
+					if bucket.Events[j].ID != id {
+						t.Errorf("bucket %s event %d = %q, want %q", want.label, j, bucket.Events[j].ID, id)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestGetCalendarRejectsLongRanges(t *testing.T) {
+	s := &eventService{eventRepository: &calendarEvents{}}
+
+	_, err := s.GetCalendar(context.Background(), &model.CalendarInput{
+		From:        time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:          time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC),
+		Granularity: model.CalendarDay,
+	})
+
+	var badRequest *errs.BadRequestError
+	if !errors.As(err, &badRequest) {
+		t.Fatalf("GetCalendar() error = %v, want a BadRequestError", err)
+	}
+}
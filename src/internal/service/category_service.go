@@ -6,36 +6,46 @@ import (
 
 	"github.com/hafiztri123/src/internal/model"
 	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"github.com/hafiztri123/src/internal/pkg/slug"
 	"github.com/hafiztri123/src/internal/repository"
 )
 
 type CategoryService interface {
-	CreateCategory(input *model.CreateCategoryInput) error
-	UpdateCategory(id string, input *model.UpdateCategoryInput) error
-	DeleteCategory(id string) error
-	GetCategory(id string) (*model.Category, error)
-	ListCategories() ([]*model.Category, error)
+	CreateCategory(ctx context.Context, input *model.CreateCategoryInput, creatorID string) error
+	UpdateCategory(ctx context.Context, id string, input *model.UpdateCategoryInput, userID string, version int) error
+	PatchCategory(ctx context.Context, id string, patch []byte, userID string, version int) error
+	DeleteCategory(ctx context.Context, id string, userID string, version int) error
+	GetCategory(ctx context.Context, id string) (*model.Category, error)
+	ListCategories(ctx context.Context) ([]*model.Category, error)
+	ListTrash(ctx context.Context, userID string) ([]*model.Category, error)
+	RestoreCategory(ctx context.Context, id string, userID string) error
 }
 
 type categoryServiceImpl struct {
-	categoryRepository repository.CategoryRepository
+	categoryRepository  repository.CategoryRepository
+	organizationService OrganizationService
 }
 
-func NewCategoryService(categoryRepository repository.CategoryRepository) CategoryService {
+func NewCategoryService(categoryRepository repository.CategoryRepository, organizationService OrganizationService) CategoryService {
 	return categoryServiceImpl{
-		categoryRepository: categoryRepository,
+		categoryRepository:  categoryRepository,
+		organizationService: organizationService,
 	}
 }
 
-func (s categoryServiceImpl) CreateCategory(input *model.CreateCategoryInput) error {
+func (s categoryServiceImpl) CreateCategory(ctx context.Context, input *model.CreateCategoryInput, creatorID string) error {
 	if input == nil {
 		return errs.NewBadRequestError("Request is missing")
 	}
 
-	ctx := context.Background()
+	if err := s.organizationService.AuthorizeContributor(ctx, creatorID); err != nil {
+		return err
+	}
+
 	category := &model.Category{
 		Name:        input.Name,
 		Description: input.Description,
+		CreatorID:   &creatorID,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
@@ -47,25 +57,33 @@ func (s categoryServiceImpl) CreateCategory(input *model.CreateCategoryInput) er
 	return nil
 }
 
-func (s categoryServiceImpl) UpdateCategory(id string, input *model.UpdateCategoryInput) error {
+func (s categoryServiceImpl) UpdateCategory(ctx context.Context, id string, input *model.UpdateCategoryInput, userID string, version int) error {
 	if input.Description == "" && input.Name =="" {
 		return errs.NewBadRequestError("Request is missing")
 	}
 
-	exists := s.isCategoryIDExists(id)
-
-	if !exists {
+	existing, err := s.categoryRepository.GetByID(ctx, id)
+	if err != nil {
 		return errs.NewNotFoundError("Category not found")
 	}
 
-	ctx := context.Background()
+	if err := s.authorizeEditor(ctx, existing, userID); err != nil {
+		return err
+	}
 
 	updatedModel := &model.Category{
 		ID:          id,
-		Name:        input.Name,
-		Description: input.Description,
-		CreatedAt:   time.Now(), //placeholder
-		UpdatedAt:   time.Now(), //placeholder
+		Name:        existing.Name,
+		Description: existing.Description,
+		Version:     version,
+	}
+
+	if input.Name != "" {
+		updatedModel.Name = input.Name
+	}
+
+	if input.Description != "" {
+		updatedModel.Description = input.Description
 	}
 
 	if err := s.categoryRepository.Update(ctx, updatedModel); err != nil {
@@ -76,41 +94,79 @@ func (s categoryServiceImpl) UpdateCategory(id string, input *model.UpdateCatego
 
 }
 
-func (s categoryServiceImpl) DeleteCategory(id string) error {
-	if id == "" {
-		return errs.NewBadRequestError("Category ID is missing")
+// PatchCategory applies an RFC 7396 merge patch to a category. Explicit null clears
+// the description; the name is required on the merged result.
+func (s categoryServiceImpl) PatchCategory(ctx context.Context, id string, patch []byte, userID string, version int) error {
+	existing, err := s.categoryRepository.GetByID(ctx, id)
+	if err != nil {
+		return errs.NewNotFoundError("Category not found")
 	}
 
-	exists := s.isCategoryIDExists(id)
+	if err := s.authorizeEditor(ctx, existing, userID); err != nil {
+		return err
+	}
 
+	current := model.CategorySnapshot{
+		Name:        existing.Name,
+		Description: existing.Description,
+	}
 
-	if !exists {
+	var merged model.CategorySnapshot
+	if err := applyMergePatch(current, patch, &merged); err != nil {
+		return err
+	}
+
+	return s.categoryRepository.Update(ctx, &model.Category{
+		ID:          id,
+		Name:        merged.Name,
+		Description: merged.Description,
+		Version:     version,
+	})
+}
+
+func (s categoryServiceImpl) DeleteCategory(ctx context.Context, id string, userID string, version int) error {
+	if id == "" {
+		return errs.NewBadRequestError("Category ID is missing")
+	}
+
+	existing, err := s.categoryRepository.GetByID(ctx, id)
+	if err != nil {
 		return errs.NewNotFoundError("Category not found")
 	}
 
-	ctx := context.Background()
+	if err := s.authorizeEditor(ctx, existing, userID); err != nil {
+		return err
+	}
 
-	if err := s.categoryRepository.Delete(ctx, id); err != nil {
+	if err := s.categoryRepository.Delete(ctx, id, version); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (s categoryServiceImpl) GetCategory(id string) (*model.Category, error) {
+// GetCategory retrieves a category by its ID or by its slug, including slugs it had
+// before being renamed.
+func (s categoryServiceImpl) GetCategory(ctx context.Context, id string) (*model.Category, error) {
 	if id == "" {
 		return nil, errs.NewBadRequestError("Category ID is missing")
 	}
 
-	exists := s.isCategoryIDExists(id)
+	if !slug.IsID(id) {
+		categoryID, err := s.categoryRepository.ResolveSlug(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		id = categoryID
+	}
+
+	exists := s.isCategoryIDExists(ctx, id)
 
 
 	if !exists {
 		return nil, errs.NewNotFoundError("Category not found")
 	}
 
-	ctx := context.Background()
-
 	category, err := s.categoryRepository.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
@@ -119,9 +175,7 @@ func (s categoryServiceImpl) GetCategory(id string) (*model.Category, error) {
 	return category, nil
 }
 
-func (s categoryServiceImpl) ListCategories() ([]*model.Category, error) {
-	ctx := context.Background()
-
+func (s categoryServiceImpl) ListCategories(ctx context.Context) ([]*model.Category, error) {
 	categories, err := s.categoryRepository.List(ctx)
 
 	if err != nil {
@@ -131,6 +185,35 @@ func (s categoryServiceImpl) ListCategories() ([]*model.Category, error) {
 	return categories, nil
 }
 
-func (s categoryServiceImpl) isCategoryIDExists(id string) bool {
-	return s.categoryRepository.IsIDExists(id)
+func (s categoryServiceImpl) ListTrash(ctx context.Context, userID string) ([]*model.Category, error) {
+	return s.categoryRepository.ListDeleted(ctx, userID)
+}
+
+func (s categoryServiceImpl) RestoreCategory(ctx context.Context, id string, userID string) error {
+	category, err := s.categoryRepository.GetDeletedByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.authorizeEditor(ctx, category, userID); err != nil {
+		return err
+	}
+
+	return s.categoryRepository.Restore(ctx, id)
+}
+
+// authorizeEditor checks that the user may change the category. Categories from
+// before creators were recorded can only be managed by the organization's owners
+// and admins.
+func (s categoryServiceImpl) authorizeEditor(ctx context.Context, category *model.Category, userID string) error {
+	creatorID := ""
+	if category.CreatorID != nil {
+		creatorID = *category.CreatorID
+	}
+
+	return s.organizationService.AuthorizeEditor(ctx, creatorID, userID)
+}
+
+func (s categoryServiceImpl) isCategoryIDExists(ctx context.Context, id string) bool {
+	return s.categoryRepository.IsIDExists(ctx, id)
 }
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// signEmailVerification returns the token of a verification link. The token names
+// the user and when it expires; the address being verified is only covered by the
+// signature, so the link stops working if the user's email changes before it is used.
+func signEmailVerification(secret string, userID string, email string, expiresAt time.Time) string {
+	payload := userID + "." + strconv.FormatInt(expiresAt.Unix(), 10)
+
+	h := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(h, "verify-email.%s.%s", payload, strings.ToLower(email))
+	return payload + "." + hex.EncodeToString(h.Sum(nil))
+}
+
+// parseEmailVerification reads the user and expiry out of a verification token.
+// The caller still has to check the signature against the user's current email.
+func parseEmailVerification(token string) (userID string, expiresAt time.Time, ok bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", time.Time{}, false
+	}
+
+	unix, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+
+	return parts[0], time.Unix(unix, 0), true
+}
@@ -2,46 +2,87 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"mime/multipart"
+	"reflect"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/hafiztri123/src/internal/model"
 	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"github.com/hafiztri123/src/internal/pkg/slug"
 	"github.com/hafiztri123/src/internal/pkg/storage"
 	"github.com/hafiztri123/src/internal/repository"
 )
 
 // EventService defines the interface for event-related service operations.
 type EventService interface {
-    CreateEvent(input *model.CreateEventInput, creatorID string) error
-    UpdateEvent(id string, input *model.UpdateEventInput, userID string) error
-    DeleteEvent(id string, userID string) error
-    GetEvent(id string) (*model.Event, error)
-    ListEvents(input *model.ListEventsInput) ([]*model.Event, error)
-    SearchEvents(input *model.SearchEventsInput) (*model.SearchEventsOutput, error)
-    UploadFile(ctx context.Context,  file multipart.File,input model.UploadFile , eventID string) error
+    CreateEvent(ctx context.Context, input *model.CreateEventInput, creatorID string) error
+    UpdateEvent(ctx context.Context, id string, input *model.UpdateEventInput, userID string, version int) error
+    PatchEvent(ctx context.Context, id string, patch []byte, userID string, version int) error
+    DeleteEvent(ctx context.Context, id string, userID string, version int) error
+    GetEvent(ctx context.Context, id string) (*model.Event, error)
+    ListEvents(ctx context.Context, input *model.ListEventsInput) ([]*model.Event, error)
+    SearchEvents(ctx context.Context, input *model.SearchEventsInput) (*model.SearchEventsOutput, error)
+    GetCalendar(ctx context.Context, input *model.CalendarInput) (*model.CalendarOutput, error)
+    UploadFile(ctx context.Context,  file multipart.File,input model.UploadFile , eventID string, userID string) error
+    ListFiles(ctx context.Context, eventID string) ([]*model.File, error)
+    PatchFile(ctx context.Context, eventID string, fileID string, patch []byte, userID string) (*model.File, error)
+    DeleteFile(ctx context.Context, eventID string, fileID string, userID string) error
+    ReorderFiles(ctx context.Context, eventID string, input *model.ReorderFilesInput, userID string) error
+    ListConflicts(ctx context.Context, input *model.ConflictsInput, userID string) ([]*model.ScheduleConflict, error)
+    ListTrash(ctx context.Context, userID string) ([]*model.Event, error)
+    RestoreEvent(ctx context.Context, id string, userID string) error
+    ListRevisions(ctx context.Context, eventID string, userID string) ([]*model.EventRevision, error)
+    RollbackEvent(ctx context.Context, eventID string, revisionID string, userID string) error
 }
 
 // eventService implements the EventService interface.
 type eventService struct {
     eventRepository repository.EventRepository
     categoryRepository repository.CategoryRepository
+    registrationRepository repository.RegistrationRepository
+    revisionRepository repository.RevisionRepository
+    organizationService OrganizationService
+    userRepository repository.UserRepository
     cloudinary storage.StorageService
+    requireVerifiedEmail bool
 }
 
 // NewEventService creates a new instance of EventService.
-func NewEventService(eventRepo repository.EventRepository, categoryRepo repository.CategoryRepository, cloudinary storage.StorageService) EventService {
+func NewEventService(eventRepo repository.EventRepository, categoryRepo repository.CategoryRepository, registrationRepo repository.RegistrationRepository, revisionRepo repository.RevisionRepository, organizationService OrganizationService, userRepo repository.UserRepository, cloudinary storage.StorageService, requireVerifiedEmail bool) EventService {
     return &eventService{
         eventRepository: eventRepo,
         categoryRepository: categoryRepo,
+        registrationRepository: registrationRepo,
+        revisionRepository: revisionRepo,
+        organizationService: organizationService,
+        userRepository: userRepo,
         cloudinary: cloudinary,
+        requireVerifiedEmail: requireVerifiedEmail,
 
     }
 }
 
-// CreateEvent creates a new event using the provided input and creator ID.
-func (s *eventService) CreateEvent(input *model.CreateEventInput, creatorID string) error {
-    bool := s.categoryRepository.IsIDExists(input.CategoryID)
+// CreateEvent creates a new event using the provided input and creator ID. When
+// verified emails are required, creators who have not verified theirs are refused.
+func (s *eventService) CreateEvent(ctx context.Context, input *model.CreateEventInput, creatorID string) error {
+    if s.requireVerifiedEmail {
+        creator, err := s.userRepository.GetByID(creatorID)
+        if err != nil {
+            return err
+        }
+        if creator.EmailVerifiedAt == nil {
+            return errs.NewForbiddenError("Verify your email address before creating events")
+        }
+    }
+
+    if err := s.organizationService.AuthorizeContributor(ctx, creatorID); err != nil {
+        return err
+    }
+
+    bool := s.categoryRepository.IsIDExists(ctx, input.CategoryID)
 
 
     if !bool {
@@ -51,6 +92,7 @@ func (s *eventService) CreateEvent(input *model.CreateEventInput, creatorID stri
     event := &model.Event{
         Title:          input.Title,
         Description:    input.Description,
+        Venue:          input.Venue,
         CategoryID:     input.CategoryID,
         StartDate:      input.StartDate,
         EndDate:        input.EndDate,
@@ -59,53 +101,123 @@ func (s *eventService) CreateEvent(input *model.CreateEventInput, creatorID stri
         CreatedAt:   time.Now(),
         UpdatedAt:   time.Now(),
     }
-    if err := s.eventRepository.Create(context.Background(), event); err != nil {
+
+    revision, err := newEventRevision(creatorID, nil, model.NewEventSnapshot(event))
+    if err != nil {
+        return err
+    }
+
+    if err := s.eventRepository.Create(ctx, event, revision); err != nil {
         return err
     }
     return nil
 }
 
-// UpdateEvent updates an existing event if the user is authorized.
-func (s *eventService) UpdateEvent(id string, input *model.UpdateEventInput, userID string) error {
-    event, err := s.eventRepository.GetByID(context.Background(), id)
+// UpdateEvent updates an existing event if the user is authorized. A non-zero version
+// must match the current version of the event.
+func (s *eventService) UpdateEvent(ctx context.Context, id string, input *model.UpdateEventInput, userID string, version int) error {
+    event, err := s.eventRepository.GetByID(ctx, id)
     if err != nil {
         return err
     }
     if event == nil {
         return errs.NewNotFoundError("Event not found")
     }
-    if event.CreatorID != userID {
-        return errs.NewForbiddenError("Creator ID does not matched the required value")
+    if err := s.organizationService.AuthorizeEditor(ctx, event.CreatorID, userID); err != nil {
+        return err
+    }
+    if err := checkVersion(event.Version, version); err != nil {
+        return err
+    }
+
+    after := model.NewEventSnapshot(event)
+    after.Title = input.Title
+    after.Description = input.Description
+    after.Venue = input.Venue
+    after.StartDate = input.StartDate
+    after.EndDate = input.EndDate
+    if input.CategoryID != "" {
+        after.CategoryID = input.CategoryID
+    }
+
+    return s.applyEventChanges(ctx, event, after, userID)
+}
+
+// PatchEvent applies an RFC 7396 merge patch to an event. Omitted fields are kept,
+// null clears a field, and the merged event must pass the same validation as a full
+// update.
+func (s *eventService) PatchEvent(ctx context.Context, id string, patch []byte, userID string, version int) error {
+    event, err := s.eventRepository.GetByID(ctx, id)
+    if err != nil {
+        return err
+    }
+    if err := s.organizationService.AuthorizeEditor(ctx, event.CreatorID, userID); err != nil {
+        return err
+    }
+    if err := checkVersion(event.Version, version); err != nil {
+        return err
+    }
+
+    var after model.EventSnapshot
+    if err := applyMergePatch(model.NewEventSnapshot(event), patch, &after); err != nil {
+        return err
+    }
+
+    return s.applyEventChanges(ctx, event, after, userID)
+}
+
+// applyEventChanges validates the new state of an event, saves it and records a
+// revision containing the changed fields.
+func (s *eventService) applyEventChanges(ctx context.Context, event *model.Event, after model.EventSnapshot, actorID string) error {
+    if after.CategoryID != event.CategoryID && !s.categoryRepository.IsIDExists(ctx, after.CategoryID) {
+        return errs.NewNotFoundError("Category not found")
+    }
+
+    before := model.NewEventSnapshot(event)
+    revision, err := newEventRevision(actorID, &before, after)
+    if err != nil {
+        return err
     }
-    event.Title = input.Title
-    event.Description = input.Description
-    event.StartDate = input.StartDate
-    event.EndDate = input.EndDate
+
+    after.ApplyTo(event)
     event.UpdatedAt = time.Now()
-    if err := s.eventRepository.Update(context.Background(), event); err != nil {
+    if err := s.eventRepository.Update(ctx, event, revision); err != nil {
         return err
     }
     return nil
 }
 
-// DeleteEvent deletes an event if the user is authorized.
-func (s *eventService) DeleteEvent(id string, userID string) error {
-    event, err := s.eventRepository.GetByID(context.Background(), id)
+// DeleteEvent deletes an event if the user is authorized. A non-zero version must
+// match the current version of the event.
+func (s *eventService) DeleteEvent(ctx context.Context, id string, userID string, version int) error {
+    event, err := s.eventRepository.GetByID(ctx, id)
     if err != nil {
         return err
     }
     if event == nil {
         return errs.NewNotFoundError("Event not found")
     }
-    if event.CreatorID != userID {
-        return errs.NewForbiddenError("Creator ID does not matched the required value")
+    if err := s.organizationService.AuthorizeEditor(ctx, event.CreatorID, userID); err != nil {
+        return err
+    }
+    if err := checkVersion(event.Version, version); err != nil {
+        return err
     }
-    return s.eventRepository.Delete(context.Background(), id)
+    return s.eventRepository.Delete(ctx, id, event.Version)
 }
 
-// GetEvent retrieves an event by its ID.
-func (s *eventService) GetEvent(id string) (*model.Event, error) {
-    event, err := s.eventRepository.GetByID(context.Background(), id)
+// GetEvent retrieves an event by its ID or by its slug, including slugs it had
+// before being renamed.
+func (s *eventService) GetEvent(ctx context.Context, id string) (*model.Event, error) {
+    if !slug.IsID(id) {
+        eventID, err := s.eventRepository.ResolveSlug(ctx, id)
+        if err != nil {
+            return nil, err
+        }
+        id = eventID
+    }
+
+    event, err := s.eventRepository.GetByID(ctx, id)
     if err != nil {
         return nil, err
     }
@@ -116,13 +228,13 @@ func (s *eventService) GetEvent(id string) (*model.Event, error) {
 }
 
 // ListEvents retrieves a paginated list of events based on the input parameters.
-func (s *eventService) ListEvents(input *model.ListEventsInput) ([]*model.Event, error) {
+func (s *eventService) ListEvents(ctx context.Context, input *model.ListEventsInput) ([]*model.Event, error) {
     offset := (input.Page - 1) * input.PageSize
-    return s.eventRepository.List(context.Background(), input.PageSize, offset, input.SortBy, input.SortDir)
+    return s.eventRepository.List(ctx, input.PageSize, offset, input.SortBy, input.SortDir)
 }
 
 // SearchEvents searches for events based on the input parameters and returns paginated results.
-func (s *eventService) SearchEvents(input *model.SearchEventsInput) (*model.SearchEventsOutput, error) {
+func (s *eventService) SearchEvents(ctx context.Context, input *model.SearchEventsInput) (*model.SearchEventsOutput, error) {
     if input.Page < 1 {
         input.Page = 1
     }
@@ -131,7 +243,7 @@ func (s *eventService) SearchEvents(input *model.SearchEventsInput) (*model.Sear
         input.PageSize = 10
     }
 
-    events, totalCount, err := s.eventRepository.Search(context.Background(), input)
+    events, totalCount, err := s.eventRepository.Search(ctx, input)
     if err != nil {
         return nil, err
     }
@@ -151,7 +263,12 @@ func (s *eventService) SearchEvents(input *model.SearchEventsInput) (*model.Sear
 }
 
 
-func (s *eventService ) UploadFile(ctx context.Context, file multipart.File,input model.UploadFile ,eventID string) error {
+// UploadFile stores a file in the storage service and attaches it to the event.
+// Only the event's organizers may upload.
+func (s *eventService ) UploadFile(ctx context.Context, file multipart.File,input model.UploadFile ,eventID string, userID string) error {
+    if _, err := s.authorizeOrganizer(ctx, eventID, userID); err != nil {
+        return err
+    }
 
     fileURL, err := s.cloudinary.UploadFile(ctx, file, input.FileName)
     if err != nil {
@@ -164,6 +281,7 @@ func (s *eventService ) UploadFile(ctx context.Context, file multipart.File,inpu
         FileType: input.FileType,
         FileURL: fileURL,
         CreatedAt: time.Now(),
+        UpdatedAt: time.Now(),
     }
 
     err = s.eventRepository.UploadFile(ctx, fileModel)
@@ -175,3 +293,319 @@ func (s *eventService ) UploadFile(ctx context.Context, file multipart.File,inpu
 
 
 }
+
+// ListFiles returns the files attached to an event in display order.
+func (s *eventService) ListFiles(ctx context.Context, eventID string) ([]*model.File, error) {
+    if _, err := s.eventRepository.GetByID(ctx, eventID); err != nil {
+        return nil, err
+    }
+    return s.eventRepository.ListFiles(ctx, eventID)
+}
+
+// PatchFile applies an RFC 7396 merge patch to the metadata of an event file.
+func (s *eventService) PatchFile(ctx context.Context, eventID string, fileID string, patch []byte, userID string) (*model.File, error) {
+    if _, err := s.authorizeOrganizer(ctx, eventID, userID); err != nil {
+        return nil, err
+    }
+
+    file, err := s.eventRepository.GetFile(ctx, eventID, fileID)
+    if err != nil {
+        return nil, err
+    }
+
+    var merged model.FileSnapshot
+    if err := applyMergePatch(model.FileSnapshot{FileName: file.FileName}, patch, &merged); err != nil {
+        return nil, err
+    }
+
+    file.FileName = merged.FileName
+    file.UpdatedAt = time.Now()
+    if err := s.eventRepository.UpdateFile(ctx, file); err != nil {
+        return nil, err
+    }
+
+    return file, nil
+}
+
+// DeleteFile removes the stored object first and then the file record, so a failed
+// storage call leaves the record in place to be retried.
+func (s *eventService) DeleteFile(ctx context.Context, eventID string, fileID string, userID string) error {
+    if _, err := s.authorizeOrganizer(ctx, eventID, userID); err != nil {
+        return err
+    }
+
+    file, err := s.eventRepository.GetFile(ctx, eventID, fileID)
+    if err != nil {
+        return err
+    }
+
+    if publicID := storage.ExtractPublicID(file.FileURL); publicID != "" {
+        if err := s.cloudinary.DeleteFile(ctx, publicID); err != nil {
+            return errs.NewInternalServerError("Failed to delete stored file")
+        }
+    }
+
+    return s.eventRepository.DeleteFile(ctx, eventID, fileID)
+}
+
+// ReorderFiles sets the display order of an event's files. The input must list every
+// file of the event exactly once.
+func (s *eventService) ReorderFiles(ctx context.Context, eventID string, input *model.ReorderFilesInput, userID string) error {
+    if _, err := s.authorizeOrganizer(ctx, eventID, userID); err != nil {
+        return err
+    }
+
+    files, err := s.eventRepository.ListFiles(ctx, eventID)
+    if err != nil {
+        return err
+    }
+
+    existing := make(map[string]bool, len(files))
+    for _, file := range files {
+        existing[file.ID] = true
+    }
+
+    seen := make(map[string]bool, len(input.FileIDs))
+    for _, fileID := range input.FileIDs {
+        if !existing[fileID] || seen[fileID] {
+            return errs.NewValidationError("file_ids must list every file of the event exactly once")
+        }
+        seen[fileID] = true
+    }
+
+    if len(seen) != len(existing) {
+        return errs.NewValidationError("file_ids must list every file of the event exactly once")
+    }
+
+    return s.eventRepository.ReorderFiles(ctx, eventID, input.FileIDs)
+}
+
+// authorizeOrganizer loads an event and checks that the user may manage it.
+func (s *eventService) authorizeOrganizer(ctx context.Context, eventID string, userID string) (*model.Event, error) {
+    event, err := s.eventRepository.GetByID(ctx, eventID)
+    if err != nil {
+        return nil, err
+    }
+    if err := s.organizationService.AuthorizeEditor(ctx, event.CreatorID, userID); err != nil {
+        return nil, err
+    }
+    return event, nil
+}
+
+// ListTrash returns the user's soft-deleted events.
+func (s *eventService) ListTrash(ctx context.Context, userID string) ([]*model.Event, error) {
+    return s.eventRepository.ListDeleted(ctx, userID)
+}
+
+// RestoreEvent moves an event out of the trash if the user may edit it.
+func (s *eventService) RestoreEvent(ctx context.Context, id string, userID string) error {
+    event, err := s.eventRepository.GetDeletedByID(ctx, id)
+    if err != nil {
+        return err
+    }
+    if err := s.organizationService.AuthorizeEditor(ctx, event.CreatorID, userID); err != nil {
+        return err
+    }
+    return s.eventRepository.Restore(ctx, id)
+}
+
+// ListRevisions returns the revision history of an event, newest first.
+func (s *eventService) ListRevisions(ctx context.Context, eventID string, userID string) ([]*model.EventRevision, error) {
+    event, err := s.eventRepository.GetByID(ctx, eventID)
+    if err != nil {
+        return nil, err
+    }
+    if err := s.organizationService.AuthorizeEditor(ctx, event.CreatorID, userID); err != nil {
+        return nil, err
+    }
+    return s.revisionRepository.ListByEvent(ctx, eventID)
+}
+
+// RollbackEvent restores the fields of an event to the state recorded by the chosen
+// revision. The rollback itself is recorded as a new revision.
+func (s *eventService) RollbackEvent(ctx context.Context, eventID string, revisionID string, userID string) error {
+    event, err := s.eventRepository.GetByID(ctx, eventID)
+    if err != nil {
+        return err
+    }
+    if err := s.organizationService.AuthorizeEditor(ctx, event.CreatorID, userID); err != nil {
+        return err
+    }
+
+    revision, err := s.revisionRepository.GetByID(ctx, eventID, revisionID)
+    if err != nil {
+        return err
+    }
+
+    var target model.EventSnapshot
+    if err := json.Unmarshal(revision.Snapshot, &target); err != nil {
+        return errs.NewInternalServerError(err.Error())
+    }
+
+    return s.applyEventChanges(ctx, event, target, userID)
+}
+
+// ListConflicts returns every pair of overlapping events inside the requested range
+// that share a venue or a creator, plus clashes between events the user registered for.
+// The range is bounded like the calendar's.
+func (s *eventService) ListConflicts(ctx context.Context, input *model.ConflictsInput, userID string) ([]*model.ScheduleConflict, error) {
+    if input.EndDate.Sub(input.StartDate) > maxCalendarRange {
+        return nil, errs.NewBadRequestError("Range cannot exceed 366 days")
+    }
+
+    events, err := s.eventRepository.ListInRange(ctx, input)
+    if err != nil {
+        return nil, err
+    }
+
+    conflicts := []*model.ScheduleConflict{}
+    forEachOverlap(events, func(a, b *model.Event) {
+        if a.Venue != "" && strings.EqualFold(a.Venue, b.Venue) {
+            conflicts = append(conflicts, newScheduleConflict(model.ConflictTypeVenue, a, b))
+        }
+
+        if a.CreatorID == b.CreatorID {
+            conflicts = append(conflicts, newScheduleConflict(model.ConflictTypeCreator, a, b))
+        }
+    })
+
+    if userID == "" {
+        return conflicts, nil
+    }
+
+    registered, err := s.registrationRepository.ListOverlappingForUser(ctx, userID, input.StartDate, input.EndDate)
+    if err != nil {
+        return nil, err
+    }
+
+    forEachOverlap(registered, func(a, b *model.Event) {
+        conflicts = append(conflicts, newScheduleConflict(model.ConflictTypeAttendee, a, b))
+    })
+
+    return conflicts, nil
+}
+
+// forEachOverlap calls fn once for every pair of overlapping events, the earlier
+// starting one first. The events are swept in start order while keeping only those
+// still running, so the work grows with the number of overlaps rather than with
+// every pair.
+func forEachOverlap(events []*model.Event, fn func(a, b *model.Event)) {
+    sorted := make([]*model.Event, len(events))
+    copy(sorted, events)
+    sort.SliceStable(sorted, func(i, j int) bool {
+        return sorted[i].StartDate.Before(sorted[j].StartDate)
+    })
+
+    var running []*model.Event
+    for _, event := range sorted {
+        kept := running[:0]
+        for _, other := range running {
+            if other.EndDate.After(event.StartDate) {
+                kept = append(kept, other)
+            }
+        }
+        running = kept
+
+        for _, other := range running {
+            if overlaps(other, event) {
+                fn(other, event)
+            }
+        }
+        running = append(running, event)
+    }
+}
+
+// newEventRevision builds a revision holding the field-level diff between before and
+// after. It returns nil when nothing changed. A nil before marks the initial revision.
+func newEventRevision(actorID string, before *model.EventSnapshot, after model.EventSnapshot) (*model.EventRevision, error) {
+    afterFields, err := snapshotFields(after)
+    if err != nil {
+        return nil, err
+    }
+
+    beforeFields := map[string]interface{}{}
+    if before != nil {
+        beforeFields, err = snapshotFields(*before)
+        if err != nil {
+            return nil, err
+        }
+    }
+
+    changes := map[string]model.FieldChange{}
+    for field, newValue := range afterFields {
+        oldValue := beforeFields[field]
+        if before != nil && reflect.DeepEqual(oldValue, newValue) {
+            continue
+        }
+        changes[field] = model.FieldChange{Old: oldValue, New: newValue}
+    }
+
+    if len(changes) == 0 {
+        return nil, nil
+    }
+
+    changesJSON, err := json.Marshal(changes)
+    if err != nil {
+        return nil, errs.NewInternalServerError(err.Error())
+    }
+
+    snapshotJSON, err := json.Marshal(after)
+    if err != nil {
+        return nil, errs.NewInternalServerError(err.Error())
+    }
+
+    return &model.EventRevision{
+        ActorID:   actorID,
+        Changes:   changesJSON,
+        Snapshot:  snapshotJSON,
+        CreatedAt: time.Now(),
+    }, nil
+}
+
+func snapshotFields(snapshot model.EventSnapshot) (map[string]interface{}, error) {
+    snapshot.StartDate = snapshot.StartDate.UTC()
+    snapshot.EndDate = snapshot.EndDate.UTC()
+
+    raw, err := json.Marshal(snapshot)
+    if err != nil {
+        return nil, errs.NewInternalServerError(err.Error())
+    }
+
+    fields := map[string]interface{}{}
+    if err := json.Unmarshal(raw, &fields); err != nil {
+        return nil, errs.NewInternalServerError(err.Error())
+    }
+    return fields, nil
+}
+
+// checkVersion compares the version a client expects (0 when it sent no If-Match)
+// with the current version of a resource.
+func checkVersion(current, expected int) error {
+    if expected != 0 && expected != current {
+        return errs.NewPreconditionFailedError("Resource was modified by another request")
+    }
+    return nil
+}
+
+func overlaps(a, b *model.Event) bool {
+    return a.StartDate.Before(b.EndDate) && b.StartDate.Before(a.EndDate)
+}
+
+func newScheduleConflict(conflictType string, a, b *model.Event) *model.ScheduleConflict {
+    from := a.StartDate
+    if b.StartDate.After(from) {
+        from = b.StartDate
+    }
+
+    to := a.EndDate
+    if b.EndDate.Before(to) {
+        to = b.EndDate
+    }
+
+    return &model.ScheduleConflict{
+        Type:        conflictType,
+        Events:      []*model.Event{a, b},
+        OverlapFrom: from,
+        OverlapTo:   to,
+    }
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hafiztri123/src/internal/model"
+	errs "github.com/hafiztri123/src/internal/pkg/error"
+)
+
+func TestForEachOverlap(t *testing.T) {
+	base := time.Date(2025, 7, 15, 0, 0, 0, 0, time.UTC)
+	at := func(id string, fromHour, toHour int) *model.Event {
+		return &model.Event{
+			ID:        id,
+			StartDate: base.Add(time.Duration(fromHour) * time.Hour),
+			EndDate:   base.Add(time.Duration(toHour) * time.Hour),
+		}
+	}
+
+	tests := []struct {
+		name   string
+		events []*model.Event
+		want   [][2]string
+	}{
+		{name: "no events"},
+		{name: "back to back events do not overlap", events: []*model.Event{at("a", 9, 10), at("b", 10, 11)}},
+		{
+			name:   "pairs come out earlier event first",
+			events: []*model.Event{at("late", 10, 12), at("early", 9, 11)},
+			want:   [][2]string{{"early", "late"}},
+		},
+		{
+			name:   "a long event overlaps everything inside it",
+			events: []*model.Event{at("day", 8, 18), at("a", 9, 10), at("b", 11, 12), at("c", 17, 19)},
+			want:   [][2]string{{"day", "a"}, {"day", "b"}, {"day", "c"}},
+		},
+		{
+			name:   "same start",
+			events: []*model.Event{at("a", 9, 10), at("b", 9, 11)},
+			want:   [][2]string{{"a", "b"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got [][2]string
+			forEachOverlap(tt.events, func(a, b *model.Event) {
+				got = append(got, [2]string{a.ID, b.ID})
+			})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("forEachOverlap() pairs = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestForEachOverlapFindsEveryPair(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	base := time.Date(2025, 7, 15, 0, 0, 0, 0, time.UTC)
+
+	for round := 0; round < 50; round++ {
+		events := make([]*model.Event, 40)
+		for i := range events {
+			start := base.Add(time.Duration(random.Intn(200)) * time.Hour)
+			events[i] = &model.Event{
+				ID:        string(rune('A' + i)),
+				StartDate: start,
+				EndDate:   start.Add(time.Duration(1+random.Intn(30)) * time.Hour),
+			}
+		}
+
+		want := map[[2]string]bool{}
+		for i := range events {
+			for j := i + 1; j < len(events); j++ {
+				if overlaps(events[i], events[j]) {
+					want[[2]string{events[i].ID, events[j].ID}] = true
+				}
+			}
+		}
+
+		got := map[[2]string]bool{}
+		forEachOverlap(events, func(a, b *model.Event) {
+			key := [2]string{a.ID, b.ID}
+			if want[[2]string{b.ID, a.ID}] {
+				key = [2]string{b.ID, a.ID}
+			}
+			if got[key] {
+				t.Fatalf("round %d: pair %v reported twice", round, key)
+			}
+			got[key] = true
+		})
+
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("round %d: found %d pairs, want %d", round, len(got), len(want))
+		}
+	}
+}
+
+func TestListConflictsRejectsLongRanges(t *testing.T) {
+	s := &eventService{}
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := s.ListConflicts(context.Background(), &model.ConflictsInput{
+		StartDate: start,
+		EndDate:   start.AddDate(1, 0, 2),
+	}, "")
+
+	var badRequest *errs.BadRequestError
+	if !errors.As(err, &badRequest) {
+		t.Fatalf("ListConflicts() error = %v, want a BadRequestError", err)
+	}
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hafiztri123/src/internal/model"
+	"github.com/hafiztri123/src/internal/pkg/pdf"
+)
+
+type invoiceLine struct {
+	Name string
+	Item model.OrderItem
+}
+
+// invoiceDocument holds everything printed on an invoice or receipt.
+type invoiceDocument struct {
+	Invoice   *model.Invoice
+	Order     *model.Order
+	Event     *model.Event
+	Organizer *model.User
+	Buyer     *model.User
+	Lines     []invoiceLine
+	TaxLabel  string
+}
+
+func (d *invoiceDocument) renderInvoice() []byte {
+	doc := pdf.New()
+	d.header(doc, "INVOICE")
+
+	doc.Line(pdf.CourierBold, 9, fmt.Sprintf("%-30s %5s %18s %18s", "Description", "Qty", "Unit price", "Amount"))
+	doc.Rule()
+	for _, line := range d.Lines {
+		doc.Line(pdf.Courier, 9, fmt.Sprintf("%-30s %5d %18s %18s",
+			truncate(line.Name, 30), line.Item.Quantity,
+			formatMoney(line.Item.UnitPrice, d.Invoice.Currency), formatMoney(line.Item.Amount, d.Invoice.Currency)))
+	}
+	doc.Rule()
+
+	d.totalLine(doc, pdf.Courier, "Subtotal", d.Invoice.Subtotal)
+	if d.Invoice.Discount > 0 {
+		d.totalLine(doc, pdf.Courier, "Discount", -d.Invoice.Discount)
+	}
+	if d.Invoice.TaxRate > 0 {
+		d.totalLine(doc, pdf.Courier, fmt.Sprintf("%s %g%% (included)", d.taxLabel(), d.Invoice.TaxRate), d.Invoice.Tax)
+	}
+	d.totalLine(doc, pdf.CourierBold, "Total", d.Invoice.Total)
+
+	doc.Space(16)
+	doc.Line(pdf.Helvetica, 9, fmt.Sprintf("Paid on %s. Prices include %s.", d.Invoice.IssuedAt.UTC().Format("02 Jan 2006"), d.taxLabel()))
+	return doc.Bytes()
+}
+
+func (d *invoiceDocument) renderReceipt() []byte {
+	doc := pdf.New()
+	d.header(doc, "RECEIPT")
+
+	doc.Line(pdf.Helvetica, 10, fmt.Sprintf("Received from %s the amount of %s", d.Buyer.FullName, formatMoney(d.Invoice.Total, d.Invoice.Currency)))
+	doc.Line(pdf.Helvetica, 10, fmt.Sprintf("for %d ticket(s) to %s.", d.ticketCount(), d.Event.Title))
+	doc.Space(8)
+
+	doc.Line(pdf.Courier, 9, fmt.Sprintf("%-20s %s", "Payment date", d.Order.PaidAt.UTC().Format(time.RFC1123)))
+	for _, p := range d.Order.Payments {
+		if p.Status == model.PaymentStatusSucceeded {
+			doc.Line(pdf.Courier, 9, fmt.Sprintf("%-20s %s", "Payment method", p.Provider))
+			doc.Line(pdf.Courier, 9, fmt.Sprintf("%-20s %s", "Payment reference", p.ProviderRef))
+		}
+	}
+	doc.Line(pdf.Courier, 9, fmt.Sprintf("%-20s %s", "Invoice", d.Invoice.InvoiceNumber))
+	doc.Rule()
+	d.totalLine(doc, pdf.CourierBold, "Amount paid", d.Invoice.Total)
+	return doc.Bytes()
+}
+
+func (d *invoiceDocument) header(doc *pdf.Document, title string) {
+	doc.Line(pdf.HelveticaBold, 20, title)
+	doc.Line(pdf.Helvetica, 10, fmt.Sprintf("No. %s    Date %s", d.Invoice.InvoiceNumber, d.Invoice.IssuedAt.UTC().Format("02 Jan 2006")))
+	doc.Space(10)
+
+	doc.Line(pdf.HelveticaBold, 10, "From")
+	organizer := d.Organizer.FullName
+	if d.Organizer.Organization != "" {
+		organizer = d.Organizer.Organization + " (" + d.Organizer.FullName + ")"
+	}
+	doc.Line(pdf.Helvetica, 10, organizer)
+	doc.Line(pdf.Helvetica, 10, d.Organizer.Email)
+	if d.Organizer.PhoneNumber != "" {
+		doc.Line(pdf.Helvetica, 10, d.Organizer.PhoneNumber)
+	}
+	doc.Space(6)
+
+	doc.Line(pdf.HelveticaBold, 10, "Billed to")
+	doc.Line(pdf.Helvetica, 10, d.Buyer.FullName)
+	if d.Buyer.Organization != "" {
+		doc.Line(pdf.Helvetica, 10, d.Buyer.Organization)
+	}
+	doc.Line(pdf.Helvetica, 10, d.Buyer.Email)
+	doc.Space(6)
+
+	doc.Line(pdf.HelveticaBold, 10, "Event")
+	doc.Line(pdf.Helvetica, 10, d.Event.Title)
+	doc.Line(pdf.Helvetica, 10, fmt.Sprintf("%s, %s", d.Event.Venue, d.Event.StartDate.UTC().Format("02 Jan 2006 15:04 MST")))
+	doc.Line(pdf.Helvetica, 9, "Order "+d.Order.ID)
+	doc.Space(12)
+}
+
+func (d *invoiceDocument) totalLine(doc *pdf.Document, font pdf.Font, label string, amount int64) {
+	doc.Line(font, 9, fmt.Sprintf("%55s %18s", label, formatMoney(amount, d.Invoice.Currency)))
+}
+
+func (d *invoiceDocument) taxLabel() string {
+	if d.TaxLabel == "" {
+		return "tax"
+	}
+	return d.TaxLabel
+}
+
+func (d *invoiceDocument) ticketCount() int {
+	count := 0
+	for _, line := range d.Lines {
+		count += line.Item.Quantity
+	}
+	return count
+}
+
+// zeroDecimalCurrencies have no minor unit, so amounts are printed as is.
+var zeroDecimalCurrencies = map[string]bool{
+	"JPY": true,
+	"KRW": true,
+	"VND": true,
+}
+
+func formatMoney(amount int64, currency string) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	if zeroDecimalCurrencies[currency] {
+		return fmt.Sprintf("%s%s %d", sign, currency, amount)
+	}
+	return fmt.Sprintf("%s%s %d.%02d", sign, currency, amount/100, amount%100)
+}
+
+func truncate(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	return string(runes[:max-3]) + "..."
+}
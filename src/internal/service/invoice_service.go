@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/hafiztri123/src/internal/model"
+	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"github.com/hafiztri123/src/internal/pkg/storage"
+	"github.com/hafiztri123/src/internal/repository"
+)
+
+type InvoiceService interface {
+	IssueForOrder(ctx context.Context, orderID string) (*model.Invoice, error)
+	GetInvoice(ctx context.Context, orderID string, userID string) (*model.Invoice, error)
+	DocumentURL(ctx context.Context, orderID string, document string, userID string) (string, error)
+}
+
+// documentLinkTTL is how long a download link to an invoice or receipt PDF works.
+const documentLinkTTL = 5 * time.Minute
+
+type invoiceServiceImpl struct {
+	invoiceRepository repository.InvoiceRepository
+	orderRepository   repository.OrderRepository
+	eventRepository   repository.EventRepository
+	userRepository    repository.UserRepository
+	storage           storage.StorageService
+	taxRate           float64
+	taxLabel          string
+}
+
+func NewInvoiceService(invoiceRepo repository.InvoiceRepository, orderRepo repository.OrderRepository, eventRepo repository.EventRepository, userRepo repository.UserRepository, storage storage.StorageService, taxRate float64, taxLabel string) InvoiceService {
+	return &invoiceServiceImpl{
+		invoiceRepository: invoiceRepo,
+		orderRepository:   orderRepo,
+		eventRepository:   eventRepo,
+		userRepository:    userRepo,
+		storage:           storage,
+		taxRate:           taxRate,
+		taxLabel:          taxLabel,
+	}
+}
+
+// IssueForOrder returns the invoice of a paid order, numbering it on first use and
+// (re)generating the PDFs if they have not been stored yet. It is safe to call
+// repeatedly and concurrently for the same order.
+func (s *invoiceServiceImpl) IssueForOrder(ctx context.Context, orderID string) (*model.Invoice, error) {
+	order, err := s.orderRepository.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.PaidAt == nil {
+		return nil, errs.NewBadRequestError("Order has not been paid")
+	}
+
+	event, err := s.eventRepository.GetByID(ctx, order.EventID)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice, err := s.invoiceRepository.GetByOrder(ctx, order.ID)
+	var notFoundErr *errs.NotFoundError
+	if errors.As(err, &notFoundErr) {
+		invoice, err = s.createInvoice(ctx, order, event)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if invoice.InvoiceFile != "" && invoice.ReceiptFile != "" {
+		return invoice, nil
+	}
+
+	if err := s.storeDocuments(ctx, invoice, order, event); err != nil {
+		return nil, err
+	}
+
+	return invoice, nil
+}
+
+// GetInvoice returns the invoice of an order to its buyer or the event organizer.
+func (s *invoiceServiceImpl) GetInvoice(ctx context.Context, orderID string, userID string) (*model.Invoice, error) {
+	order, err := s.orderRepository.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.UserID != userID {
+		event, err := s.eventRepository.GetByID(ctx, order.EventID)
+		if err != nil {
+			return nil, err
+		}
+		if event.CreatorID != userID {
+			return nil, errs.NewForbiddenError("Only the buyer and the event organizers can access this invoice")
+		}
+	}
+
+	return s.IssueForOrder(ctx, order.ID)
+}
+
+// DocumentURL returns a short-lived signed link to the invoice or receipt PDF, for
+// the buyer or the event organizer.
+func (s *invoiceServiceImpl) DocumentURL(ctx context.Context, orderID string, document string, userID string) (string, error) {
+	invoice, err := s.GetInvoice(ctx, orderID, userID)
+	if err != nil {
+		return "", err
+	}
+
+	file := invoice.InvoiceFile
+	if document == model.DocumentReceipt {
+		file = invoice.ReceiptFile
+	}
+
+	url, err := s.storage.PrivateFileURL(ctx, file, time.Now().Add(documentLinkTTL))
+	if err != nil {
+		return "", errs.NewInternalServerError(err.Error())
+	}
+
+	return url, nil
+}
+
+func (s *invoiceServiceImpl) createInvoice(ctx context.Context, order *model.Order, event *model.Event) (*model.Invoice, error) {
+	invoice := &model.Invoice{
+		OrderID:     order.ID,
+		OrganizerID: event.CreatorID,
+		Currency:    order.Currency,
+		Subtotal:    order.Subtotal,
+		Discount:    order.Discount,
+		TaxRate:     s.taxRate,
+		Tax:         includedTax(order.TotalAmount, s.taxRate),
+		Total:       order.TotalAmount,
+		IssuedAt:    *order.PaidAt,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	err := s.invoiceRepository.Create(ctx, invoice)
+	var duplicateErr *errs.DuplicateEntryError
+	if errors.As(err, &duplicateErr) {
+		return s.invoiceRepository.GetByOrder(ctx, order.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return invoice, nil
+}
+
+// storeDocuments renders the invoice and receipt PDFs and uploads them as private
+// files.
+func (s *invoiceServiceImpl) storeDocuments(ctx context.Context, invoice *model.Invoice, order *model.Order, event *model.Event) error {
+	organizer, err := s.userRepository.GetByID(event.CreatorID)
+	if err != nil {
+		return err
+	}
+
+	buyer, err := s.userRepository.GetByID(order.UserID)
+	if err != nil {
+		return err
+	}
+
+	lines := make([]invoiceLine, 0, len(order.Items))
+	for _, item := range order.Items {
+		name := "Ticket"
+		ticketType, err := s.orderRepository.GetTicketType(ctx, item.TicketTypeID)
+		if err == nil {
+			name = ticketType.Name
+		}
+		lines = append(lines, invoiceLine{Name: name, Item: item})
+	}
+
+	doc := &invoiceDocument{
+		Invoice:   invoice,
+		Order:     order,
+		Event:     event,
+		Organizer: organizer,
+		Buyer:     buyer,
+		Lines:     lines,
+		TaxLabel:  s.taxLabel,
+	}
+
+	invoiceFile, err := s.storage.UploadPrivateFile(ctx, storage.NewBytesFile(doc.renderInvoice()),
+		fmt.Sprintf("invoices/%s/%s.pdf", invoice.OrganizerID, invoice.InvoiceNumber))
+	if err != nil {
+		return err
+	}
+
+	receiptFile, err := s.storage.UploadPrivateFile(ctx, storage.NewBytesFile(doc.renderReceipt()),
+		fmt.Sprintf("receipts/%s/%s.pdf", invoice.OrganizerID, invoice.InvoiceNumber))
+	if err != nil {
+		return err
+	}
+
+	if err := s.invoiceRepository.UpdateDocuments(ctx, invoice.ID, invoiceFile, receiptFile); err != nil {
+		return err
+	}
+
+	invoice.InvoiceFile = invoiceFile
+	invoice.ReceiptFile = receiptFile
+	return nil
+}
+
+// includedTax returns the tax contained in a tax-inclusive amount.
+func includedTax(total int64, rate float64) int64 {
+	if rate <= 0 {
+		return 0
+	}
+	return int64(math.Round(float64(total) * rate / (100 + rate)))
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/hafiztri123/src/internal/model"
+	"github.com/hafiztri123/src/internal/repository"
+)
+
+// OrderExpirer releases the tickets held by pending orders whose payment never
+// completed within the reservation window.
+type OrderExpirer struct {
+	orderRepository repository.OrderRepository
+}
+
+func NewOrderExpirer(orderRepo repository.OrderRepository) *OrderExpirer {
+	return &OrderExpirer{
+		orderRepository: orderRepo,
+	}
+}
+
+func (e *OrderExpirer) Name() string {
+	return "order-expiry"
+}
+
+func (e *OrderExpirer) Run(ctx context.Context) error {
+	orders, err := e.orderRepository.ListExpired(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, order := range orders {
+		if err := e.orderRepository.Release(ctx, order.ID, model.OrderStatusExpired, ""); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
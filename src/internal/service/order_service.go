@@ -0,0 +1,581 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hafiztri123/src/internal/model"
+	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"github.com/hafiztri123/src/internal/pkg/payment"
+	"github.com/hafiztri123/src/internal/pkg/tenant"
+	"github.com/hafiztri123/src/internal/repository"
+)
+
+type OrderService interface {
+	CreateTicketType(ctx context.Context, eventID string, input *model.CreateTicketTypeInput, userID string) (*model.TicketType, error)
+	ListTicketTypes(ctx context.Context, eventID string) ([]*model.TicketType, error)
+	GetTicketLimit(ctx context.Context, eventID string) (*model.TicketLimit, error)
+	SetTicketLimit(ctx context.Context, eventID string, input *model.TicketLimitInput, userID string) (*model.TicketLimit, error)
+	Checkout(ctx context.Context, input *model.CheckoutInput, userID string) (*model.CheckoutOutput, error)
+	CheckoutSeats(ctx context.Context, input *model.CheckoutInput, seatIDs []string, userID string) (*model.CheckoutOutput, error)
+	PreviewPromoCode(ctx context.Context, input *model.PromoPreviewInput, userID string) (*model.PromoPreviewOutput, error)
+	GetOrder(ctx context.Context, id string, userID string) (*model.Order, error)
+	ListOrders(ctx context.Context, userID string) ([]*model.Order, error)
+	HandleWebhook(ctx context.Context, payload []byte, header http.Header) error
+}
+
+type orderServiceImpl struct {
+	orderRepository         repository.OrderRepository
+	eventRepository         repository.EventRepository
+	promoRepository         repository.PromoRepository
+	refundRepository        repository.RefundRepository
+	invoiceService          InvoiceService
+	analytics               AnalyticsService
+	registrationFormService RegistrationFormService
+	provider                payment.Provider
+	currency                string
+	reservation             time.Duration
+	maxTicketsPerUser       int
+}
+
+func NewOrderService(orderRepo repository.OrderRepository, eventRepo repository.EventRepository, promoRepo repository.PromoRepository, refundRepo repository.RefundRepository, invoiceService InvoiceService, analyticsService AnalyticsService, registrationFormService RegistrationFormService, provider payment.Provider, currency string, reservation time.Duration, maxTicketsPerUser int) OrderService {
+	return &orderServiceImpl{
+		orderRepository:         orderRepo,
+		eventRepository:         eventRepo,
+		promoRepository:         promoRepo,
+		refundRepository:        refundRepo,
+		invoiceService:          invoiceService,
+		analytics:               analyticsService,
+		registrationFormService: registrationFormService,
+		provider:                provider,
+		currency:                strings.ToUpper(currency),
+		reservation:             reservation,
+		maxTicketsPerUser:       maxTicketsPerUser,
+	}
+}
+
+// CreateTicketType adds a ticket type to an event. Only the event creator may do so.
+func (s *orderServiceImpl) CreateTicketType(ctx context.Context, eventID string, input *model.CreateTicketTypeInput, userID string) (*model.TicketType, error) {
+	event, err := s.eventRepository.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if event.CreatorID != userID {
+		return nil, errs.NewForbiddenError("Only the event organizers can manage its tickets")
+	}
+
+	currency := strings.ToUpper(input.Currency)
+	if currency == "" {
+		currency = s.currency
+	}
+
+	ticketType := &model.TicketType{
+		EventID:     event.ID,
+		Name:        input.Name,
+		Description: input.Description,
+		Price:       input.Price,
+		Currency:    currency,
+		Quantity:    input.Quantity,
+	}
+
+	if err := s.orderRepository.CreateTicketType(ctx, ticketType); err != nil {
+		return nil, err
+	}
+
+	return ticketType, nil
+}
+
+func (s *orderServiceImpl) ListTicketTypes(ctx context.Context, eventID string) ([]*model.TicketType, error) {
+	if _, err := s.eventRepository.GetByID(ctx, eventID); err != nil {
+		return nil, err
+	}
+
+	return s.orderRepository.ListTicketTypes(ctx, eventID)
+}
+
+// GetTicketLimit returns how many tickets one user may hold for the event. Events
+// without a stored limit use the configured default.
+func (s *orderServiceImpl) GetTicketLimit(ctx context.Context, eventID string) (*model.TicketLimit, error) {
+	event, err := s.eventRepository.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, err := s.orderRepository.GetTicketLimit(ctx, event.ID)
+	var notFoundErr *errs.NotFoundError
+	if errors.As(err, &notFoundErr) {
+		return &model.TicketLimit{EventID: event.ID, MaxPerUser: s.maxTicketsPerUser}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return limit, nil
+}
+
+func (s *orderServiceImpl) SetTicketLimit(ctx context.Context, eventID string, input *model.TicketLimitInput, userID string) (*model.TicketLimit, error) {
+	event, err := s.eventRepository.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if event.CreatorID != userID {
+		return nil, errs.NewForbiddenError("Only the event organizers can manage its tickets")
+	}
+
+	limit := &model.TicketLimit{
+		EventID:    event.ID,
+		MaxPerUser: *input.MaxPerUser,
+		UpdatedAt:  time.Now(),
+	}
+
+	if err := s.orderRepository.SaveTicketLimit(ctx, limit); err != nil {
+		return nil, err
+	}
+
+	return limit, nil
+}
+
+// Checkout reserves the requested tickets and opens a payment intent for them. The
+// answers to the registration form are checked now and stored on the registration
+// once the order is paid. The reservation holds until the order is paid, fails or expires. Free orders are
+// confirmed straight away without going through the provider. Seated tickets are
+// not sold here; they are booked by confirming a seat hold. Orders for several
+// tickets become group bookings, and no buyer may hold more tickets for an event
+// than its ticket limit.
+func (s *orderServiceImpl) Checkout(ctx context.Context, input *model.CheckoutInput, userID string) (*model.CheckoutOutput, error) {
+	return s.checkout(ctx, input, nil, userID)
+}
+
+// CheckoutSeats checks out seated tickets together with the seats they are for.
+// The seats are claimed in the same transaction as the tickets, so a seat that
+// another order has taken fails the whole checkout.
+func (s *orderServiceImpl) CheckoutSeats(ctx context.Context, input *model.CheckoutInput, seatIDs []string, userID string) (*model.CheckoutOutput, error) {
+	return s.checkout(ctx, input, seatIDs, userID)
+}
+
+func (s *orderServiceImpl) checkout(ctx context.Context, input *model.CheckoutInput, seatIDs []string, userID string) (*model.CheckoutOutput, error) {
+	event, err := s.eventRepository.GetByID(ctx, input.EventID)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := s.priceOrder(ctx, event, input.Items, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkSeating(ctx, order, len(seatIDs) > 0); err != nil {
+		return nil, err
+	}
+
+	order.Answers, err = s.registrationFormService.ValidateAnswers(ctx, event.ID, input.Answers)
+	if err != nil {
+		return nil, err
+	}
+
+	var redemption *model.PromoRedemption
+	if input.PromoCode != "" {
+		redemption, err = s.applyPromoCode(ctx, order, event, input.PromoCode)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	limit, err := s.GetTicketLimit(ctx, event.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.orderRepository.CreatePending(ctx, order, redemption, seatIDs, limit.MaxPerUser); err != nil {
+		return nil, err
+	}
+
+	if order.TotalAmount == 0 {
+		registered, err := s.orderRepository.MarkPaid(ctx, order.ID, "")
+		if err != nil {
+			return nil, err
+		}
+		if registered {
+			s.analytics.RecordRegistration(ctx, order.EventID)
+		}
+		s.issueInvoice(ctx, order.ID)
+
+		paid, err := s.orderRepository.GetByID(ctx, order.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		return &model.CheckoutOutput{Order: paid}, nil
+	}
+
+	intent, err := s.provider.CreateIntent(ctx, payment.IntentRequest{
+		OrderID:     order.ID,
+		Amount:      order.TotalAmount,
+		Currency:    order.Currency,
+		Description: event.Title,
+	})
+	if err != nil {
+		s.releaseOrder(ctx, order.ID, err)
+		return nil, errs.NewInternalServerError("Payment provider is unavailable")
+	}
+
+	orderPayment := &model.Payment{
+		OrderID:     order.ID,
+		Provider:    s.provider.Name(),
+		ProviderRef: intent.ID,
+		Amount:      order.TotalAmount,
+		Currency:    order.Currency,
+		Status:      model.PaymentStatusRequiresPayment,
+	}
+
+	if err := s.orderRepository.CreatePayment(ctx, orderPayment); err != nil {
+		s.releaseOrder(ctx, order.ID, err)
+		return nil, err
+	}
+
+	orderPayment.ClientSecret = intent.ClientSecret
+	order.Payments = []model.Payment{*orderPayment}
+
+	return &model.CheckoutOutput{
+		Order:   order,
+		Payment: orderPayment,
+	}, nil
+}
+
+// PreviewPromoCode prices the items with the code applied without reserving anything.
+// Limits are checked against current usage, so a later checkout can still lose the
+// last use of a code to a concurrent buyer.
+func (s *orderServiceImpl) PreviewPromoCode(ctx context.Context, input *model.PromoPreviewInput, userID string) (*model.PromoPreviewOutput, error) {
+	event, err := s.eventRepository.GetByID(ctx, input.EventID)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := s.priceOrder(ctx, event, input.Items, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	redemption, err := s.applyPromoCode(ctx, order, event, input.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.PromoPreviewOutput{
+		Code:     strings.ToUpper(input.Code),
+		Currency: order.Currency,
+		Subtotal: order.Subtotal,
+		Discount: redemption.Discount,
+		Total:    order.TotalAmount,
+	}, nil
+}
+
+func (s *orderServiceImpl) GetOrder(ctx context.Context, id string, userID string) (*model.Order, error) {
+	order, err := s.orderRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.UserID != userID {
+		return nil, errs.NewForbiddenError("You can only view your own orders")
+	}
+
+	return order, nil
+}
+
+func (s *orderServiceImpl) ListOrders(ctx context.Context, userID string) ([]*model.Order, error) {
+	return s.orderRepository.ListByUser(ctx, userID)
+}
+
+// HandleWebhook applies a provider notification to the order behind the intent.
+// Deliveries are idempotent: a repeated success for a paid order is a no-op.
+func (s *orderServiceImpl) HandleWebhook(ctx context.Context, payload []byte, header http.Header) error {
+	event, err := s.provider.ParseWebhook(payload, header)
+	if err != nil {
+		return errs.NewBadRequestError("Invalid webhook")
+	}
+
+	// The provider acts for no organization; the verified intent decides which
+	// order, and so which organization, the notification touches.
+	ctx = tenant.WithSystem(ctx)
+
+	orderPayment, err := s.orderRepository.GetPaymentByProviderRef(ctx, s.provider.Name(), event.IntentID)
+	if err != nil {
+		return err
+	}
+
+	switch event.Type {
+	case payment.EventPaymentSucceeded:
+		registered, err := s.orderRepository.MarkPaid(ctx, orderPayment.OrderID, orderPayment.ID)
+
+		var conflictErr *errs.ConflictError
+		if errors.As(err, &conflictErr) {
+			order, getErr := s.orderRepository.GetByID(ctx, orderPayment.OrderID)
+			if getErr != nil {
+				return getErr
+			}
+			if order.PaidAt == nil {
+				return s.refundLatePayment(ctx, order, orderPayment)
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if registered {
+			s.recordRegistration(ctx, orderPayment.OrderID)
+		}
+		s.issueInvoice(ctx, orderPayment.OrderID)
+		return nil
+
+	case payment.EventPaymentFailed:
+		return s.orderRepository.Release(ctx, orderPayment.OrderID, model.OrderStatusFailed, orderPayment.ID)
+	}
+
+	return nil
+}
+
+// refundLatePayment pays back a payment that succeeded after its order had expired,
+// failed or been cancelled, since the order no longer delivers tickets. The refund
+// is recorded like any other. The payment is claimed first, so repeated webhook
+// deliveries refund it once; if the provider refuses, the claim is undone and the
+// error makes the provider deliver the webhook again.
+func (s *orderServiceImpl) refundLatePayment(ctx context.Context, order *model.Order, orderPayment *model.Payment) error {
+	claimed, err := s.orderRepository.ClaimPaymentRefund(ctx, orderPayment.ID)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return nil
+	}
+
+	now := time.Now()
+	refund := &model.Refund{
+		OrderID:   order.ID,
+		EventID:   order.EventID,
+		UserID:    order.UserID,
+		PaymentID: &orderPayment.ID,
+		Amount:    orderPayment.Amount,
+		Currency:  orderPayment.Currency,
+		Percent:   100,
+		Reason:    fmt.Sprintf("Payment arrived after the order was %s", order.Status),
+		Status:    model.RefundStatusProcessing,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	// The refund is recorded on the buyer's behalf; nobody else acted on it.
+	if err := s.refundRepository.Create(ctx, refund, order.UserID); err != nil {
+		if resetErr := s.orderRepository.SetPaymentStatus(ctx, orderPayment.ID, model.PaymentStatusSucceeded); resetErr != nil {
+			return resetErr
+		}
+		return err
+	}
+
+	result, err := s.provider.Refund(ctx, payment.RefundRequest{
+		IntentID: orderPayment.ProviderRef,
+		Amount:   orderPayment.Amount,
+		Currency: orderPayment.Currency,
+		Reason:   refund.Reason,
+	})
+	if err != nil {
+		log.Printf("[FAIL] refund of late payment %s failed at the provider: %v", orderPayment.ID, err)
+		if failErr := s.refundRepository.Fail(ctx, refund, order.UserID, err.Error()); failErr != nil {
+			return failErr
+		}
+		if resetErr := s.orderRepository.SetPaymentStatus(ctx, orderPayment.ID, model.PaymentStatusSucceeded); resetErr != nil {
+			return resetErr
+		}
+		return errs.NewInternalServerError("Refund of late payment failed at the payment provider")
+	}
+
+	if err := s.refundRepository.Complete(ctx, refund, order.UserID, result.ID); err != nil {
+		return err
+	}
+
+	return s.orderRepository.SetPaymentStatus(ctx, orderPayment.ID, model.PaymentStatusRefunded)
+}
+
+// priceOrder builds a pending order for the requested items at their current prices.
+func (s *orderServiceImpl) priceOrder(ctx context.Context, event *model.Event, items []model.CheckoutItemInput, userID string) (*model.Order, error) {
+	if event.CancelledAt != nil {
+		return nil, errs.NewBadRequestError("Event has been cancelled")
+	}
+
+	if !event.EndDate.After(time.Now()) {
+		return nil, errs.NewBadRequestError("Event has already ended")
+	}
+
+	order := &model.Order{
+		UserID:    userID,
+		EventID:   event.ID,
+		Status:    model.OrderStatusPending,
+		ExpiresAt: time.Now().Add(s.reservation),
+	}
+
+	positions := map[string]int{}
+	for _, requested := range items {
+		if i, ok := positions[requested.TicketTypeID]; ok {
+			order.Items[i].Quantity += requested.Quantity
+			order.Items[i].Amount = order.Items[i].UnitPrice * int64(order.Items[i].Quantity)
+			continue
+		}
+
+		ticketType, err := s.orderRepository.GetTicketType(ctx, requested.TicketTypeID)
+		if err != nil {
+			return nil, err
+		}
+
+		if ticketType.EventID != event.ID {
+			return nil, errs.NewBadRequestError("Ticket type does not belong to this event")
+		}
+
+		if order.Currency == "" {
+			order.Currency = ticketType.Currency
+		} else if order.Currency != ticketType.Currency {
+			return nil, errs.NewBadRequestError("All tickets in an order must share the same currency")
+		}
+
+		positions[requested.TicketTypeID] = len(order.Items)
+		order.Items = append(order.Items, model.OrderItem{
+			TicketTypeID: ticketType.ID,
+			Quantity:     requested.Quantity,
+			UnitPrice:    ticketType.Price,
+			Amount:       ticketType.Price * int64(requested.Quantity),
+		})
+	}
+
+	for _, item := range order.Items {
+		order.Subtotal += item.Amount
+	}
+	order.TotalAmount = order.Subtotal
+
+	return order, nil
+}
+
+// checkSeating keeps seats and seated ticket types together: a seated order only
+// buys seated tickets and any other order only buys tickets without seats.
+func (s *orderServiceImpl) checkSeating(ctx context.Context, order *model.Order, seated bool) error {
+	ticketTypes, err := s.orderRepository.ListTicketTypes(ctx, order.EventID)
+	if err != nil {
+		return err
+	}
+
+	seatedTypes := make(map[string]bool, len(ticketTypes))
+	for _, ticketType := range ticketTypes {
+		seatedTypes[ticketType.ID] = ticketType.Seated
+	}
+
+	for _, item := range order.Items {
+		if seatedTypes[item.TicketTypeID] == seated {
+			continue
+		}
+		if seated {
+			return errs.NewBadRequestError("Ticket type " + item.TicketTypeID + " is not sold with seats")
+		}
+		return errs.NewBadRequestError("Ticket type " + item.TicketTypeID + " is seated; hold seats to book it")
+	}
+
+	return nil
+}
+
+// applyPromoCode checks that a code may be used for the order and discounts it. The
+// returned redemption is counted against the code's limits when the order is stored.
+func (s *orderServiceImpl) applyPromoCode(ctx context.Context, order *model.Order, event *model.Event, code string) (*model.PromoRedemption, error) {
+	promo, err := s.promoRepository.FindForEvent(ctx, code, event.ID, event.CreatorID)
+	var notFoundErr *errs.NotFoundError
+	if errors.As(err, &notFoundErr) {
+		return nil, errs.NewNotFoundError("Promo code not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !promo.ActiveAt(time.Now()) {
+		return nil, errs.NewBadRequestError("Promo code is not active")
+	}
+
+	if promo.MaxUses > 0 && promo.UsedCount >= promo.MaxUses {
+		return nil, errs.NewConflictError("Promo code has reached its usage limit")
+	}
+
+	if promo.MaxUsesPerUser > 0 {
+		used, err := s.promoRepository.CountRedemptions(ctx, promo.ID, order.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if used >= int64(promo.MaxUsesPerUser) {
+			return nil, errs.NewConflictError("You have already used this promo code")
+		}
+	}
+
+	var eligible int64
+	for _, item := range order.Items {
+		if promo.AppliesTo(item.TicketTypeID) {
+			eligible += item.Amount
+		}
+	}
+
+	if eligible == 0 {
+		return nil, errs.NewBadRequestError("Promo code does not apply to these tickets")
+	}
+
+	var discount int64
+	switch promo.DiscountType {
+	case model.DiscountPercentage:
+		discount = eligible * promo.DiscountValue / 100
+	case model.DiscountFixed:
+		if promo.Currency != order.Currency {
+			return nil, errs.NewBadRequestError("Promo code is not valid for this currency")
+		}
+		discount = promo.DiscountValue
+	}
+
+	if discount > eligible {
+		discount = eligible
+	}
+
+	order.Discount = discount
+	order.TotalAmount = order.Subtotal - discount
+	order.PromoCodeID = &promo.ID
+
+	return &model.PromoRedemption{
+		PromoCodeID: promo.ID,
+		UserID:      order.UserID,
+		Discount:    discount,
+		CreatedAt:   time.Now(),
+	}, nil
+}
+
+// issueInvoice generates the invoice of a freshly paid order. Failures are only
+// logged; the invoice is generated again when it is first requested.
+func (s *orderServiceImpl) issueInvoice(ctx context.Context, orderID string) {
+	if _, err := s.invoiceService.IssueForOrder(ctx, orderID); err != nil {
+		log.Printf("[FAIL] failed to issue invoice for order %s: %v", orderID, err)
+	}
+}
+
+// recordRegistration counts the registration created when an order was paid.
+func (s *orderServiceImpl) recordRegistration(ctx context.Context, orderID string) {
+	order, err := s.orderRepository.GetByID(ctx, orderID)
+	if err != nil {
+		log.Printf("[WARN] failed to record registration of order %s: %v", orderID, err)
+		return
+	}
+	s.analytics.RecordRegistration(ctx, order.EventID)
+}
+
+func (s *orderServiceImpl) releaseOrder(ctx context.Context, orderID string, cause error) {
+	log.Printf("[FAIL] checkout of order %s failed: %v", orderID, cause)
+	if err := s.orderRepository.Release(ctx, orderID, model.OrderStatusFailed, ""); err != nil {
+		log.Printf("[FAIL] failed to release order %s: %v", orderID, err)
+	}
+}
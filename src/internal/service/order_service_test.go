@@ -0,0 +1,272 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hafiztri123/src/internal/model"
+	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"github.com/hafiztri123/src/internal/pkg/payment"
+	"github.com/hafiztri123/src/internal/repository"
+)
+
+// webhookOrders keeps one order and its payment in memory and follows the status
+// rules of the Postgres repository for the calls a webhook makes.
+type webhookOrders struct {
+	repository.OrderRepository
+	order   *model.Order
+	payment *model.Payment
+}
+
+func (r *webhookOrders) GetPaymentByProviderRef(ctx context.Context, provider, ref string) (*model.Payment, error) {
+	if r.payment.Provider != provider || r.payment.ProviderRef != ref {
+		return nil, errs.NewNotFoundError("Payment not found")
+	}
+	payment := *r.payment
+	return &payment, nil
+}
+
+func (r *webhookOrders) GetByID(ctx context.Context, id string) (*model.Order, error) {
+	order := *r.order
+	return &order, nil
+}
+
+func (r *webhookOrders) MarkPaid(ctx context.Context, orderID string, paymentID string) (bool, error) {
+	// The payment update is rolled back with the rest when the order is not pending.
+	if r.order.Status != model.OrderStatusPending {
+		return false, errs.NewConflictError("Order is no longer pending")
+	}
+	r.payment.Status = model.PaymentStatusSucceeded
+	now := time.Now()
+	r.order.Status = model.OrderStatusPaid
+	r.order.PaidAt = &now
+	return true, nil
+}
+
+func (r *webhookOrders) Release(ctx context.Context, orderID string, status string, paymentID string) error {
+	if paymentID != "" {
+		r.payment.Status = model.PaymentStatusFailed
+	}
+	if r.order.Status == model.OrderStatusPending {
+		r.order.Status = status
+	}
+	return nil
+}
+
+func (r *webhookOrders) ClaimPaymentRefund(ctx context.Context, paymentID string) (bool, error) {
+	if r.payment.Status == model.PaymentStatusRefunding || r.payment.Status == model.PaymentStatusRefunded {
+		return false, nil
+	}
+	r.payment.Status = model.PaymentStatusRefunding
+	return true, nil
+}
+
+func (r *webhookOrders) SetPaymentStatus(ctx context.Context, paymentID string, status string) error {
+	r.payment.Status = status
+	return nil
+}
+
+type webhookRefunds struct {
+	repository.RefundRepository
+	refunds []*model.Refund
+}
+
+func (r *webhookRefunds) Create(ctx context.Context, refund *model.Refund, actorID string) error {
+	refund.ID = fmt.Sprintf("refund-%d", len(r.refunds)+1)
+	r.refunds = append(r.refunds, refund)
+	return nil
+}
+
+func (r *webhookRefunds) Complete(ctx context.Context, refund *model.Refund, actorID string, providerRef string) error {
+	refund.Status = model.RefundStatusSucceeded
+	refund.ProviderRef = providerRef
+	return nil
+}
+
+func (r *webhookRefunds) Fail(ctx context.Context, refund *model.Refund, actorID string, note string) error {
+	refund.Status = model.RefundStatusFailed
+	return nil
+}
+
+// refusingProvider turns refunds down while refuse is set.
+type refusingProvider struct {
+	*payment.FakeProvider
+	refuse bool
+}
+
+func (p *refusingProvider) Refund(ctx context.Context, req payment.RefundRequest) (*payment.RefundResult, error) {
+	if p.refuse {
+		return nil, fmt.Errorf("[FAIL] provider unavailable")
+	}
+	return p.FakeProvider.Refund(ctx, req)
+}
+
+type webhookInvoices struct {
+	InvoiceService
+	issued int
+}
+
+func (s *webhookInvoices) IssueForOrder(ctx context.Context, orderID string) (*model.Invoice, error) {
+	s.issued++
+	return &model.Invoice{OrderID: orderID}, nil
+}
+
+type webhookAnalytics struct {
+	AnalyticsService
+}
+
+func (webhookAnalytics) RecordRegistration(ctx context.Context, eventID string) {}
+
+type webhookTest struct {
+	service  *orderServiceImpl
+	orders   *webhookOrders
+	refunds  *webhookRefunds
+	invoices *webhookInvoices
+	provider *refusingProvider
+}
+
+func newWebhookTest(t *testing.T, status string) *webhookTest {
+	t.Helper()
+
+	provider := &refusingProvider{FakeProvider: payment.NewFakeProvider("secret")}
+	intent, err := provider.CreateIntent(context.Background(), payment.IntentRequest{OrderID: "order-1", Amount: 10000, Currency: "USD"})
+	if err != nil {
+		t.Fatalf("CreateIntent() error = %v", err)
+	}
+
+	orders := &webhookOrders{
+		order: &model.Order{ID: "order-1", UserID: "buyer-1", EventID: "event-1", Status: status, Currency: "USD", TotalAmount: 10000},
+		payment: &model.Payment{
+			ID: "payment-1", OrderID: "order-1", Provider: provider.Name(), ProviderRef: intent.ID,
+			Amount: 10000, Currency: "USD", Status: model.PaymentStatusRequiresPayment,
+		},
+	}
+	refunds := &webhookRefunds{}
+	invoices := &webhookInvoices{}
+
+	return &webhookTest{
+		service: &orderServiceImpl{
+			orderRepository:  orders,
+			refundRepository: refunds,
+			invoiceService:   invoices,
+			analytics:        webhookAnalytics{},
+			provider:         provider,
+		},
+		orders:   orders,
+		refunds:  refunds,
+		invoices: invoices,
+		provider: provider,
+	}
+}
+
+func (w *webhookTest) deliver(t *testing.T, succeed bool) error {
+	t.Helper()
+	payload, header, err := w.provider.Simulate(w.orders.payment.ProviderRef, succeed)
+	if err != nil {
+		t.Fatalf("Simulate() error = %v", err)
+	}
+	return w.service.HandleWebhook(context.Background(), payload, header)
+}
+
+func TestHandleWebhookPaysPendingOrder(t *testing.T) {
+	w := newWebhookTest(t, model.OrderStatusPending)
+
+	for i := 0; i < 2; i++ {
+		if err := w.deliver(t, true); err != nil {
+			t.Fatalf("delivery %d: HandleWebhook() error = %v", i+1, err)
+		}
+	}
+
+	if w.orders.order.Status != model.OrderStatusPaid {
+		t.Errorf("order status = %q, want %q", w.orders.order.Status, model.OrderStatusPaid)
+	}
+	if w.orders.payment.Status != model.PaymentStatusSucceeded {
+		t.Errorf("payment status = %q, want %q", w.orders.payment.Status, model.PaymentStatusSucceeded)
+	}
+	if w.invoices.issued != 1 {
+		t.Errorf("invoices issued = %d, want 1", w.invoices.issued)
+	}
+	if len(w.refunds.refunds) != 0 {
+		t.Errorf("refunds = %d, want none for a paid order", len(w.refunds.refunds))
+	}
+}
+
+func TestHandleWebhookReleasesFailedPayment(t *testing.T) {
+	w := newWebhookTest(t, model.OrderStatusPending)
+
+	if err := w.deliver(t, false); err != nil {
+		t.Fatalf("HandleWebhook() error = %v", err)
+	}
+
+	if w.orders.order.Status != model.OrderStatusFailed {
+		t.Errorf("order status = %q, want %q", w.orders.order.Status, model.OrderStatusFailed)
+	}
+	if w.orders.payment.Status != model.PaymentStatusFailed {
+		t.Errorf("payment status = %q, want %q", w.orders.payment.Status, model.PaymentStatusFailed)
+	}
+}
+
+func TestHandleWebhookRefundsLatePayment(t *testing.T) {
+	for _, status := range []string{model.OrderStatusExpired, model.OrderStatusFailed, model.OrderStatusCancelled} {
+		t.Run(status, func(t *testing.T) {
+			w := newWebhookTest(t, status)
+
+			// The provider may deliver the same notification more than once.
+			for i := 0; i < 2; i++ {
+				if err := w.deliver(t, true); err != nil {
+					t.Fatalf("delivery %d: HandleWebhook() error = %v", i+1, err)
+				}
+			}
+
+			if w.orders.order.Status != status {
+				t.Errorf("order status = %q, want %q", w.orders.order.Status, status)
+			}
+			if w.orders.payment.Status != model.PaymentStatusRefunded {
+				t.Errorf("payment status = %q, want %q", w.orders.payment.Status, model.PaymentStatusRefunded)
+			}
+			if len(w.refunds.refunds) != 1 {
+				t.Fatalf("refunds = %d, want 1", len(w.refunds.refunds))
+			}
+
+			refund := w.refunds.refunds[0]
+			if refund.Status != model.RefundStatusSucceeded || refund.Amount != 10000 || refund.Percent != 100 {
+				t.Errorf("refund = %+v, want a succeeded refund of the whole payment", refund)
+			}
+			if w.invoices.issued != 0 {
+				t.Errorf("invoices issued = %d, want none", w.invoices.issued)
+			}
+		})
+	}
+}
+
+func TestHandleWebhookRetriesRefusedLateRefund(t *testing.T) {
+	w := newWebhookTest(t, model.OrderStatusExpired)
+	w.provider.refuse = true
+
+	err := w.deliver(t, true)
+	var internalErr *errs.InternalServerError
+	if !errors.As(err, &internalErr) {
+		t.Fatalf("HandleWebhook() error = %v, want an InternalServerError so the provider retries", err)
+	}
+	if w.orders.payment.Status != model.PaymentStatusSucceeded {
+		t.Errorf("payment status = %q, want the claim undone", w.orders.payment.Status)
+	}
+	if len(w.refunds.refunds) != 1 || w.refunds.refunds[0].Status != model.RefundStatusFailed {
+		t.Fatalf("want one failed refund, got %d", len(w.refunds.refunds))
+	}
+
+	w.provider.refuse = false
+	if err := w.deliver(t, true); err != nil {
+		t.Fatalf("retried HandleWebhook() error = %v", err)
+	}
+
+	if w.orders.payment.Status != model.PaymentStatusRefunded {
+		t.Errorf("payment status = %q, want %q", w.orders.payment.Status, model.PaymentStatusRefunded)
+	}
+	if len(w.refunds.refunds) != 2 || w.refunds.refunds[1].Status != model.RefundStatusSucceeded {
+		t.Errorf("want the retry to record a succeeded refund")
+	}
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/hafiztri123/src/internal/model"
+	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"github.com/hafiztri123/src/internal/pkg/tenant"
+	"github.com/hafiztri123/src/internal/repository"
+)
+
+type OrganizationService interface {
+	CreateOrganization(ctx context.Context, input *model.CreateOrganizationInput, userID string) (*model.Organization, error)
+	ListOrganizations(ctx context.Context, userID string) ([]*model.MembershipView, error)
+	ListMembers(ctx context.Context, organizationID string, userID string) ([]*model.OrganizationMember, error)
+	SetMember(ctx context.Context, organizationID string, memberID string, input *model.SetMemberInput, userID string) (*model.OrganizationMember, error)
+	RemoveMember(ctx context.Context, organizationID string, memberID string, userID string) error
+	ResolveOrganization(ctx context.Context, idOrSlug string) (string, error)
+	AuthorizeOrganization(ctx context.Context, organizationID string, userID string) error
+	AuthorizeContributor(ctx context.Context, userID string) error
+	AuthorizeEditor(ctx context.Context, creatorID string, userID string) error
+}
+
+type organizationServiceImpl struct {
+	organizationRepository repository.OrganizationRepository
+	userRepository         repository.UserRepository
+}
+
+func NewOrganizationService(organizationRepo repository.OrganizationRepository, userRepo repository.UserRepository) OrganizationService {
+	return &organizationServiceImpl{
+		organizationRepository: organizationRepo,
+		userRepository:         userRepo,
+	}
+}
+
+// CreateOrganization creates an organization owned by the user.
+func (s *organizationServiceImpl) CreateOrganization(ctx context.Context, input *model.CreateOrganizationInput, userID string) (*model.Organization, error) {
+	now := time.Now()
+	organization := &model.Organization{
+		Name:      input.Name,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := s.organizationRepository.Create(ctx, organization, userID); err != nil {
+		return nil, err
+	}
+
+	return organization, nil
+}
+
+func (s *organizationServiceImpl) ListOrganizations(ctx context.Context, userID string) ([]*model.MembershipView, error) {
+	return s.organizationRepository.ListByUser(ctx, userID)
+}
+
+// ListMembers returns the members of an organization to any of its members.
+func (s *organizationServiceImpl) ListMembers(ctx context.Context, organizationID string, userID string) ([]*model.OrganizationMember, error) {
+	if _, err := s.authorizeMember(ctx, organizationID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.organizationRepository.ListMembers(ctx, organizationID)
+}
+
+// SetMember adds a user to the organization or changes their role. Owners and
+// admins manage members, but only owners may grant or take away ownership, and the
+// last owner cannot step down.
+func (s *organizationServiceImpl) SetMember(ctx context.Context, organizationID string, memberID string, input *model.SetMemberInput, userID string) (*model.OrganizationMember, error) {
+	actor, err := s.authorizeManager(ctx, organizationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.userRepository.GetByID(memberID); err != nil {
+		return nil, errs.NewNotFoundError("User not found")
+	}
+
+	current, err := s.organizationRepository.GetMember(ctx, organizationID, memberID)
+	if err != nil {
+		current = nil
+	}
+
+	touchesOwner := input.Role == model.OrganizationRoleOwner ||
+		(current != nil && current.Role == model.OrganizationRoleOwner)
+	if touchesOwner && actor.Role != model.OrganizationRoleOwner {
+		return nil, errs.NewForbiddenError("Only the organization owners can change ownership")
+	}
+
+	now := time.Now()
+	member := &model.OrganizationMember{
+		OrganizationID: organizationID,
+		UserID:         memberID,
+		Role:           input.Role,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if current != nil {
+		member.CreatedAt = current.CreatedAt
+	}
+
+	if err := s.organizationRepository.SaveMember(ctx, member); err != nil {
+		return nil, err
+	}
+
+	return member, nil
+}
+
+// RemoveMember takes a user out of the organization. Members may leave on their
+// own; removing someone else takes an owner or admin, and only owners can remove
+// owners. The last owner cannot leave.
+func (s *organizationServiceImpl) RemoveMember(ctx context.Context, organizationID string, memberID string, userID string) error {
+	member, err := s.organizationRepository.GetMember(ctx, organizationID, memberID)
+	if err != nil {
+		return err
+	}
+
+	if memberID != userID {
+		actor, err := s.authorizeManager(ctx, organizationID, userID)
+		if err != nil {
+			return err
+		}
+		if member.Role == model.OrganizationRoleOwner && actor.Role != model.OrganizationRoleOwner {
+			return errs.NewForbiddenError("Only the organization owners can change ownership")
+		}
+	}
+
+	return s.organizationRepository.DeleteMember(ctx, organizationID, memberID)
+}
+
+// ResolveOrganization returns the ID of the organization with the ID or slug.
+func (s *organizationServiceImpl) ResolveOrganization(ctx context.Context, idOrSlug string) (string, error) {
+	organization, err := s.organizationRepository.Resolve(ctx, idOrSlug)
+	if err != nil {
+		return "", err
+	}
+
+	return organization.ID, nil
+}
+
+// AuthorizeOrganization checks that a signed-in user may act in the organization:
+// anyone in an open organization, members otherwise.
+func (s *organizationServiceImpl) AuthorizeOrganization(ctx context.Context, organizationID string, userID string) error {
+	organization, err := s.organizationRepository.GetByID(ctx, organizationID)
+	if err != nil {
+		return err
+	}
+	if organization.Open {
+		return nil
+	}
+
+	if _, err := s.organizationRepository.GetMember(ctx, organizationID, userID); err != nil {
+		return errs.NewForbiddenError("Only members of the organization can use it")
+	}
+
+	return nil
+}
+
+// AuthorizeContributor checks that the user may publish events and categories in
+// the organization of the request: anyone in an open organization, members
+// otherwise.
+func (s *organizationServiceImpl) AuthorizeContributor(ctx context.Context, userID string) error {
+	organizationID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return errs.NewBadRequestError("Organization is missing")
+	}
+
+	var forbiddenErr *errs.ForbiddenError
+	err := s.AuthorizeOrganization(ctx, organizationID, userID)
+	if errors.As(err, &forbiddenErr) {
+		return errs.NewForbiddenError("Only members of the organization can publish in it")
+	}
+
+	return err
+}
+
+// AuthorizeEditor checks that the user may change or remove an event or category of
+// the organization of the request. Owners and admins may manage everything in it;
+// other users only what they created, and in a closed organization only while they
+// are still members.
+func (s *organizationServiceImpl) AuthorizeEditor(ctx context.Context, creatorID string, userID string) error {
+	organizationID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return errs.NewBadRequestError("Organization is missing")
+	}
+
+	organization, err := s.organizationRepository.GetByID(ctx, organizationID)
+	if err != nil {
+		return err
+	}
+
+	member, err := s.organizationRepository.GetMember(ctx, organizationID, userID)
+	if err != nil {
+		member = nil
+	}
+
+	if member != nil && (member.Role == model.OrganizationRoleOwner || member.Role == model.OrganizationRoleAdmin) {
+		return nil
+	}
+
+	if creatorID == userID && (organization.Open || member != nil) {
+		return nil
+	}
+
+	return errs.NewForbiddenError("Only the creator or the organization owners and admins can change it")
+}
+
+func (s *organizationServiceImpl) authorizeMember(ctx context.Context, organizationID string, userID string) (*model.OrganizationMember, error) {
+	if _, err := s.organizationRepository.GetByID(ctx, organizationID); err != nil {
+		return nil, err
+	}
+
+	member, err := s.organizationRepository.GetMember(ctx, organizationID, userID)
+	if err != nil {
+		return nil, errs.NewForbiddenError("Only members of the organization can see its members")
+	}
+
+	return member, nil
+}
+
+func (s *organizationServiceImpl) authorizeManager(ctx context.Context, organizationID string, userID string) (*model.OrganizationMember, error) {
+	member, err := s.authorizeMember(ctx, organizationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if member.Role != model.OrganizationRoleOwner && member.Role != model.OrganizationRoleAdmin {
+		return nil, errs.NewForbiddenError("Only the organization owners and admins can manage members")
+	}
+
+	return member, nil
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/go-playground/validator/v10"
+	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"github.com/hafiztri123/src/internal/pkg/mergepatch"
+)
+
+var patchValidator = validator.New()
+
+// applyMergePatch merges patch into the JSON form of current and decodes the result
+// into dest. The merged document is validated as a whole, so a patch that clears a
+// required field is rejected even if every member it sent is well-formed.
+func applyMergePatch(current interface{}, patch []byte, dest interface{}) error {
+	document, err := json.Marshal(current)
+	if err != nil {
+		return errs.NewInternalServerError(err.Error())
+	}
+
+	merged, err := mergepatch.Apply(document, patch)
+	if err != nil {
+		return errs.NewBadRequestError("Request body is not a valid merge patch")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(merged))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(dest); err != nil {
+		return errs.NewValidationError("Patch contains unknown or mistyped fields")
+	}
+
+	if err := patchValidator.Struct(dest); err != nil {
+		return errs.NewValidationError("Request is not valid")
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hafiztri123/src/internal/model"
+	errs "github.com/hafiztri123/src/internal/pkg/error"
+)
+
+func TestApplyMergePatchToEvent(t *testing.T) {
+	start := time.Date(2025, 7, 15, 9, 0, 0, 0, time.UTC)
+	current := model.EventSnapshot{
+		Title:       "Tech Workshop",
+		Description: "Hands-on session",
+		Venue:       "Main Hall",
+		CategoryID:  "category-1",
+		StartDate:   start,
+		EndDate:     start.Add(3 * time.Hour),
+	}
+
+	tests := []struct {
+		name    string
+		patch   string
+		want    *model.EventSnapshot
+		wantErr interface{}
+	}{
+		{
+			name:  "omitted fields are kept",
+			patch: `{"venue":"Annex"}`,
+			want: &model.EventSnapshot{
+				Title: "Tech Workshop", Description: "Hands-on session", Venue: "Annex",
+				CategoryID: "category-1", StartDate: start, EndDate: start.Add(3 * time.Hour),
+			},
+		},
+		{
+			name:  "null clears an optional field",
+			patch: `{"description":null}`,
+			want: &model.EventSnapshot{
+				Title: "Tech Workshop", Venue: "Main Hall",
+				CategoryID: "category-1", StartDate: start, EndDate: start.Add(3 * time.Hour),
+			},
+		},
+		{
+			name:  "both dates move together",
+			patch: `{"start_date":"2025-07-16T09:00:00Z","end_date":"2025-07-16T12:00:00Z"}`,
+			want: &model.EventSnapshot{
+				Title: "Tech Workshop", Description: "Hands-on session", Venue: "Main Hall",
+				CategoryID: "category-1", StartDate: start.AddDate(0, 0, 1), EndDate: start.AddDate(0, 0, 1).Add(3 * time.Hour),
+			},
+		},
+		{name: "null on a required field", patch: `{"title":null}`, wantErr: &errs.ValidationError{}},
+		{name: "end before the kept start", patch: `{"end_date":"2025-07-15T08:00:00Z"}`, wantErr: &errs.ValidationError{}},
+		{name: "unknown field", patch: `{"organizer":"someone"}`, wantErr: &errs.ValidationError{}},
+		{name: "mistyped field", patch: `{"title":42}`, wantErr: &errs.ValidationError{}},
+		{name: "not JSON", patch: `{"title":`, wantErr: &errs.BadRequestError{}},
+		{name: "not an object", patch: `"Tech Workshop"`, wantErr: &errs.ValidationError{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got model.EventSnapshot
+			err := applyMergePatch(current, []byte(tt.patch), &got)
+
+			switch want := tt.wantErr.(type) {
+			case *errs.ValidationError:
+				if !errors.As(err, &want) {
+					t.Fatalf("applyMergePatch() error = %v, want a ValidationError", err)
+				}
+				return
+			case *errs.BadRequestError:
+				if !errors.As(err, &want) {
+					t.Fatalf("applyMergePatch() error = %v, want a BadRequestError", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("applyMergePatch() error = %v", err)
+			}
+			if got != *tt.want {
+				t.Errorf("applyMergePatch() = %+v, want %+v", got, *tt.want)
+			}
+		})
+	}
+}
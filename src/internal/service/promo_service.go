@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/hafiztri123/src/internal/model"
+	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"github.com/hafiztri123/src/internal/repository"
+)
+
+type PromoService interface {
+	CreatePromoCode(ctx context.Context, input *model.CreatePromoCodeInput, userID string) (*model.PromoCode, error)
+	ListPromoCodes(ctx context.Context, userID string) ([]*model.PromoCode, error)
+	DeletePromoCode(ctx context.Context, id string, userID string) error
+}
+
+type promoServiceImpl struct {
+	promoRepository repository.PromoRepository
+	orderRepository repository.OrderRepository
+	eventRepository repository.EventRepository
+}
+
+func NewPromoService(promoRepo repository.PromoRepository, orderRepo repository.OrderRepository, eventRepo repository.EventRepository) PromoService {
+	return &promoServiceImpl{
+		promoRepository: promoRepo,
+		orderRepository: orderRepo,
+		eventRepository: eventRepo,
+	}
+}
+
+// CreatePromoCode creates a code owned by the user. Codes scoped to an event and
+// ticket type restrictions must point at the user's own events.
+func (s *promoServiceImpl) CreatePromoCode(ctx context.Context, input *model.CreatePromoCodeInput, userID string) (*model.PromoCode, error) {
+	if input.DiscountType == model.DiscountPercentage && input.DiscountValue > 100 {
+		return nil, errs.NewBadRequestError("Percentage discounts cannot exceed 100")
+	}
+
+	if input.DiscountType == model.DiscountFixed && input.Currency == "" {
+		return nil, errs.NewBadRequestError("Fixed discounts need a currency")
+	}
+
+	if input.StartsAt != nil && input.EndsAt != nil && !input.EndsAt.After(*input.StartsAt) {
+		return nil, errs.NewBadRequestError("ends_at must be after starts_at")
+	}
+
+	if input.EventID != nil {
+		if err := s.authorizeEvent(ctx, *input.EventID, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, ticketTypeID := range input.TicketTypeIDs {
+		ticketType, err := s.orderRepository.GetTicketType(ctx, ticketTypeID)
+		if err != nil {
+			return nil, err
+		}
+
+		if input.EventID != nil && ticketType.EventID != *input.EventID {
+			return nil, errs.NewBadRequestError("Ticket type does not belong to this event")
+		}
+
+		if err := s.authorizeEvent(ctx, ticketType.EventID, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	promo := &model.PromoCode{
+		Code:           strings.ToUpper(input.Code),
+		OrganizerID:    userID,
+		EventID:        input.EventID,
+		DiscountType:   input.DiscountType,
+		DiscountValue:  input.DiscountValue,
+		Currency:       strings.ToUpper(input.Currency),
+		MaxUses:        input.MaxUses,
+		MaxUsesPerUser: input.MaxUsesPerUser,
+		StartsAt:       input.StartsAt,
+		EndsAt:         input.EndsAt,
+		TicketTypeIDs:  input.TicketTypeIDs,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+
+	if err := s.promoRepository.Create(ctx, promo); err != nil {
+		return nil, err
+	}
+
+	return promo, nil
+}
+
+func (s *promoServiceImpl) ListPromoCodes(ctx context.Context, userID string) ([]*model.PromoCode, error) {
+	return s.promoRepository.ListByOrganizer(ctx, userID)
+}
+
+func (s *promoServiceImpl) DeletePromoCode(ctx context.Context, id string, userID string) error {
+	promo, err := s.promoRepository.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if promo.OrganizerID != userID {
+		return errs.NewForbiddenError("You can only delete your own promo codes")
+	}
+
+	return s.promoRepository.Delete(ctx, id)
+}
+
+func (s *promoServiceImpl) authorizeEvent(ctx context.Context, eventID string, userID string) error {
+	event, err := s.eventRepository.GetByID(ctx, eventID)
+	if err != nil {
+		return err
+	}
+
+	if event.CreatorID != userID {
+		return errs.NewForbiddenError("Only the event organizers can create promo codes for it")
+	}
+
+	return nil
+}
@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/hafiztri123/src/internal/model"
+	"github.com/hafiztri123/src/internal/repository"
+)
+
+const (
+	recommendationsPerSubject = 20
+	// recommendationHistory is how far back a user's registrations shape their
+	// recommendations.
+	recommendationHistory = 365 * 24 * time.Hour
+
+	weightSharedTag     = 3.0
+	weightSameCategory  = 2.0
+	weightSameOrganizer = 1.5
+	weightCoAttendance  = 2.0
+)
+
+// RecommendationBuilder precomputes the "you might also like" events for every
+// upcoming event and for every user with recent registrations. Candidates are
+// upcoming events, scored by the tags, category and organizer they share with the
+// subject and by how many attendees they have in common with it. Events are only
+// recommended within their own organization; users get recommendations in every
+// organization with upcoming events.
+type RecommendationBuilder struct {
+	recommendationRepository repository.RecommendationRepository
+}
+
+func NewRecommendationBuilder(recommendationRepo repository.RecommendationRepository) *RecommendationBuilder {
+	return &RecommendationBuilder{
+		recommendationRepository: recommendationRepo,
+	}
+}
+
+func (j *RecommendationBuilder) Name() string {
+	return "recommendations"
+}
+
+func (j *RecommendationBuilder) Run(ctx context.Context) error {
+	now := time.Now()
+
+	upcoming, err := j.recommendationRepository.ListUpcomingEvents(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	candidates := newCandidateIndex(upcoming)
+
+	byOrganization := map[string][]*model.Event{}
+	for _, event := range upcoming {
+		byOrganization[event.OrganizationID] = append(byOrganization[event.OrganizationID], event)
+	}
+	organizationCandidates := make(map[string]*candidateIndex, len(byOrganization))
+	for organizationID, events := range byOrganization {
+		organizationCandidates[organizationID] = newCandidateIndex(events)
+	}
+
+	pairs, err := j.recommendationRepository.ListCoAttendance(ctx, candidates.ids())
+	if err != nil {
+		return err
+	}
+
+	coAttendance := map[string]map[string]int{}
+	for _, pair := range pairs {
+		if coAttendance[pair.SourceID] == nil {
+			coAttendance[pair.SourceID] = map[string]int{}
+		}
+		coAttendance[pair.SourceID][pair.TargetID] = pair.Shared
+	}
+
+	var forEvents []*model.Recommendation
+	for _, event := range upcoming {
+		profile := newRecommendationProfile()
+		profile.add(event, coAttendance)
+
+		forEvents = append(forEvents, organizationCandidates[event.OrganizationID].recommend(profile, model.RecommendationForEvent, event.ID, now)...)
+	}
+
+	if err := j.recommendationRepository.Replace(ctx, model.RecommendationForEvent, forEvents); err != nil {
+		return err
+	}
+
+	attendance, err := j.recommendationRepository.ListAttendance(ctx, now.Add(-recommendationHistory))
+	if err != nil {
+		return err
+	}
+
+	eventsByUser := map[string][]string{}
+	var missing []string
+	seen := map[string]bool{}
+	for _, a := range attendance {
+		eventsByUser[a.UserID] = append(eventsByUser[a.UserID], a.EventID)
+		if _, ok := candidates.events[a.EventID]; !ok && !seen[a.EventID] {
+			seen[a.EventID] = true
+			missing = append(missing, a.EventID)
+		}
+	}
+
+	// Past events are not candidates, but they still describe what a user likes.
+	attended := map[string]*model.Event{}
+	for id, event := range candidates.events {
+		attended[id] = event
+	}
+
+	past, err := j.recommendationRepository.ListEventsByIDs(ctx, missing)
+	if err != nil {
+		return err
+	}
+	for _, event := range past {
+		attended[event.ID] = event
+	}
+
+	var forUsers []*model.Recommendation
+	for userID, eventIDs := range eventsByUser {
+		profile := newRecommendationProfile()
+		for _, eventID := range eventIDs {
+			if event, ok := attended[eventID]; ok {
+				profile.add(event, coAttendance)
+			}
+			profile.exclude[eventID] = true
+		}
+
+		for _, index := range organizationCandidates {
+			forUsers = append(forUsers, index.recommend(profile, model.RecommendationForUser, userID, now)...)
+		}
+	}
+
+	return j.recommendationRepository.Replace(ctx, model.RecommendationForUser, forUsers)
+}
+
+// recommendationProfile is what a subject is interested in, weighted by how often
+// it comes up among the subject's events.
+type recommendationProfile struct {
+	tags         map[string]float64
+	categories   map[string]float64
+	organizers   map[string]float64
+	coAttendance map[string]int
+	exclude      map[string]bool
+}
+
+func newRecommendationProfile() *recommendationProfile {
+	return &recommendationProfile{
+		tags:         map[string]float64{},
+		categories:   map[string]float64{},
+		organizers:   map[string]float64{},
+		coAttendance: map[string]int{},
+		exclude:      map[string]bool{},
+	}
+}
+
+func (p *recommendationProfile) add(event *model.Event, coAttendance map[string]map[string]int) {
+	for _, tag := range event.Tags {
+		p.tags[tag.ID]++
+	}
+	if event.CategoryID != "" {
+		p.categories[event.CategoryID]++
+	}
+	p.organizers[event.CreatorID]++
+
+	for target, shared := range coAttendance[event.ID] {
+		p.coAttendance[target] += shared
+	}
+
+	p.exclude[event.ID] = true
+}
+
+// candidateIndex looks up the upcoming events by the attributes they are scored on.
+type candidateIndex struct {
+	events      map[string]*model.Event
+	byTag       map[string][]*model.Event
+	byCategory  map[string][]*model.Event
+	byOrganizer map[string][]*model.Event
+}
+
+func newCandidateIndex(events []*model.Event) *candidateIndex {
+	index := &candidateIndex{
+		events:      map[string]*model.Event{},
+		byTag:       map[string][]*model.Event{},
+		byCategory:  map[string][]*model.Event{},
+		byOrganizer: map[string][]*model.Event{},
+	}
+
+	for _, event := range events {
+		index.events[event.ID] = event
+		for _, tag := range event.Tags {
+			index.byTag[tag.ID] = append(index.byTag[tag.ID], event)
+		}
+		if event.CategoryID != "" {
+			index.byCategory[event.CategoryID] = append(index.byCategory[event.CategoryID], event)
+		}
+		index.byOrganizer[event.CreatorID] = append(index.byOrganizer[event.CreatorID], event)
+	}
+
+	return index
+}
+
+func (c *candidateIndex) ids() []string {
+	ids := make([]string, 0, len(c.events))
+	for id := range c.events {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+type scoredCandidate struct {
+	score   float64
+	reasons map[string]bool
+}
+
+func (c *candidateIndex) recommend(profile *recommendationProfile, subjectType, subjectID string, now time.Time) []*model.Recommendation {
+	scores := map[string]*scoredCandidate{}
+	bump := func(eventID string, amount float64, reason string) {
+		if profile.exclude[eventID] {
+			return
+		}
+		scored, ok := scores[eventID]
+		if !ok {
+			scored = &scoredCandidate{reasons: map[string]bool{}}
+			scores[eventID] = scored
+		}
+		scored.score += amount
+		scored.reasons[reason] = true
+	}
+
+	for tagID, weight := range profile.tags {
+		for _, event := range c.byTag[tagID] {
+			bump(event.ID, weightSharedTag*weight, model.ReasonSharedTags)
+		}
+	}
+	for categoryID, weight := range profile.categories {
+		for _, event := range c.byCategory[categoryID] {
+			bump(event.ID, weightSameCategory*weight, model.ReasonSameCategory)
+		}
+	}
+	for organizerID, weight := range profile.organizers {
+		for _, event := range c.byOrganizer[organizerID] {
+			bump(event.ID, weightSameOrganizer*weight, model.ReasonSameOrganizer)
+		}
+	}
+	for eventID, shared := range profile.coAttendance {
+		if _, ok := c.events[eventID]; ok {
+			bump(eventID, weightCoAttendance*math.Log1p(float64(shared)), model.ReasonCoAttendance)
+		}
+	}
+
+	recommendations := make([]*model.Recommendation, 0, len(scores))
+	for eventID, scored := range scores {
+		var reasons []string
+		for _, reason := range []string{model.ReasonSharedTags, model.ReasonSameCategory, model.ReasonSameOrganizer, model.ReasonCoAttendance} {
+			if scored.reasons[reason] {
+				reasons = append(reasons, reason)
+			}
+		}
+
+		recommendations = append(recommendations, &model.Recommendation{
+			SubjectType: subjectType,
+			SubjectID:   subjectID,
+			EventID:     eventID,
+			Score:       scored.score,
+			Reasons:     reasons,
+			ComputedAt:  now,
+		})
+	}
+
+	sort.Slice(recommendations, func(i, j int) bool {
+		if recommendations[i].Score != recommendations[j].Score {
+			return recommendations[i].Score > recommendations[j].Score
+		}
+		return c.events[recommendations[i].EventID].StartDate.Before(c.events[recommendations[j].EventID].StartDate)
+	})
+
+	if len(recommendations) > recommendationsPerSubject {
+		recommendations = recommendations[:recommendationsPerSubject]
+	}
+
+	return recommendations
+}
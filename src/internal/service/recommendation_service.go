@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/hafiztri123/src/internal/model"
+	"github.com/hafiztri123/src/internal/repository"
+)
+
+type RecommendationService interface {
+	RecommendForEvent(ctx context.Context, eventID string, limit int) ([]*model.RecommendedEvent, error)
+	RecommendForUser(ctx context.Context, userID string, limit int) ([]*model.RecommendedEvent, error)
+}
+
+type recommendationServiceImpl struct {
+	recommendationRepository repository.RecommendationRepository
+	registrationRepository   repository.RegistrationRepository
+	eventRepository          repository.EventRepository
+}
+
+func NewRecommendationService(recommendationRepo repository.RecommendationRepository, registrationRepo repository.RegistrationRepository, eventRepo repository.EventRepository) RecommendationService {
+	return &recommendationServiceImpl{
+		recommendationRepository: recommendationRepo,
+		registrationRepository:   registrationRepo,
+		eventRepository:          eventRepo,
+	}
+}
+
+// RecommendForEvent returns the upcoming events most similar to the given one.
+func (s *recommendationServiceImpl) RecommendForEvent(ctx context.Context, eventID string, limit int) ([]*model.RecommendedEvent, error) {
+	event, err := s.eventRepository.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	recommendations, err := s.recommendationRepository.List(ctx, model.RecommendationForEvent, event.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.hydrate(ctx, recommendations, map[string]bool{event.ID: true}, limit), nil
+}
+
+// RecommendForUser returns upcoming events the user may like based on the events
+// they registered for. Users without registrations get no recommendations until the
+// next time they are computed.
+func (s *recommendationServiceImpl) RecommendForUser(ctx context.Context, userID string, limit int) ([]*model.RecommendedEvent, error) {
+	recommendations, err := s.recommendationRepository.List(ctx, model.RecommendationForUser, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	joined, err := s.registrationRepository.ListEventIDsByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	exclude := map[string]bool{}
+	for _, eventID := range joined {
+		exclude[eventID] = true
+	}
+
+	return s.hydrate(ctx, recommendations, exclude, limit), nil
+}
+
+// hydrate loads the recommended events, skipping the excluded ones and those that
+// have started, been cancelled or been deleted since the recommendations were
+// computed.
+func (s *recommendationServiceImpl) hydrate(ctx context.Context, recommendations []*model.Recommendation, exclude map[string]bool, limit int) []*model.RecommendedEvent {
+	now := time.Now()
+	recommended := make([]*model.RecommendedEvent, 0, limit)
+
+	for _, recommendation := range recommendations {
+		if len(recommended) == limit {
+			break
+		}
+
+		if exclude[recommendation.EventID] {
+			continue
+		}
+
+		event, err := s.eventRepository.GetByID(ctx, recommendation.EventID)
+		if err != nil {
+			continue
+		}
+
+		if event.CancelledAt != nil || !event.StartDate.After(now) {
+			continue
+		}
+
+		recommended = append(recommended, &model.RecommendedEvent{
+			Event:   event,
+			Score:   recommendation.Score,
+			Reasons: recommendation.Reasons,
+		})
+	}
+
+	return recommended
+}
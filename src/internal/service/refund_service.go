@@ -0,0 +1,424 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/hafiztri123/src/internal/model"
+	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"github.com/hafiztri123/src/internal/pkg/payment"
+	"github.com/hafiztri123/src/internal/repository"
+)
+
+type RefundService interface {
+	GetPolicy(ctx context.Context, eventID string) (*model.RefundPolicy, error)
+	SetPolicy(ctx context.Context, eventID string, input *model.RefundPolicyInput, userID string) (*model.RefundPolicy, error)
+	RequestRefund(ctx context.Context, orderID string, input *model.RefundRequestInput, userID string) (*model.Refund, error)
+	ApproveRefund(ctx context.Context, refundID string, userID string) (*model.Refund, error)
+	RejectRefund(ctx context.Context, refundID string, input *model.RefundRequestInput, userID string) (*model.Refund, error)
+	ListOrderRefunds(ctx context.Context, orderID string, userID string) ([]*model.Refund, error)
+	ListEventRefunds(ctx context.Context, eventID string, userID string) ([]*model.Refund, error)
+	ListLedger(ctx context.Context, eventID string, userID string) ([]*model.RefundLedgerEntry, error)
+	CancelEvent(ctx context.Context, eventID string, input *model.CancelEventInput, userID string) (*model.CancelEventOutput, error)
+}
+
+type refundServiceImpl struct {
+	refundRepository       repository.RefundRepository
+	orderRepository        repository.OrderRepository
+	eventRepository        repository.EventRepository
+	registrationRepository repository.RegistrationRepository
+	provider               payment.Provider
+}
+
+func NewRefundService(refundRepo repository.RefundRepository, orderRepo repository.OrderRepository, eventRepo repository.EventRepository, registrationRepo repository.RegistrationRepository, provider payment.Provider) RefundService {
+	return &refundServiceImpl{
+		refundRepository:       refundRepo,
+		orderRepository:        orderRepo,
+		eventRepository:        eventRepo,
+		registrationRepository: registrationRepo,
+		provider:               provider,
+	}
+}
+
+// GetPolicy returns the refund policy of an event. Events without a stored policy
+// get an empty one, which means no refunds outside of cancellation.
+func (s *refundServiceImpl) GetPolicy(ctx context.Context, eventID string) (*model.RefundPolicy, error) {
+	if _, err := s.eventRepository.GetByID(ctx, eventID); err != nil {
+		return nil, err
+	}
+
+	policy, err := s.refundRepository.GetPolicy(ctx, eventID)
+	var notFoundErr *errs.NotFoundError
+	if errors.As(err, &notFoundErr) {
+		return &model.RefundPolicy{EventID: eventID, Tiers: []model.RefundPolicyTier{}}, nil
+	}
+
+	return policy, err
+}
+
+func (s *refundServiceImpl) SetPolicy(ctx context.Context, eventID string, input *model.RefundPolicyInput, userID string) (*model.RefundPolicy, error) {
+	if _, err := s.authorizeEventOwner(ctx, eventID, userID); err != nil {
+		return nil, err
+	}
+
+	tiers := append([]model.RefundPolicyTier{}, input.Tiers...)
+	sort.Slice(tiers, func(i, j int) bool {
+		return tiers[i].DaysBefore > tiers[j].DaysBefore
+	})
+
+	for i := 1; i < len(tiers); i++ {
+		if tiers[i].DaysBefore == tiers[i-1].DaysBefore {
+			return nil, errs.NewBadRequestError("Refund tiers must have distinct days_before values")
+		}
+	}
+
+	policy := &model.RefundPolicy{
+		EventID:   eventID,
+		Tiers:     tiers,
+		UpdatedAt: time.Now(),
+	}
+
+	if err := s.refundRepository.SavePolicy(ctx, policy); err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// RequestRefund opens a refund for a paid order. The amount is fixed at request
+// time from the event's refund policy and waits for the organizer's approval.
+func (s *refundServiceImpl) RequestRefund(ctx context.Context, orderID string, input *model.RefundRequestInput, userID string) (*model.Refund, error) {
+	order, err := s.orderRepository.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.UserID != userID {
+		return nil, errs.NewForbiddenError("You can only refund your own orders")
+	}
+
+	if order.Status != model.OrderStatusPaid {
+		return nil, errs.NewBadRequestError("Only paid orders can be refunded")
+	}
+
+	if order.TotalAmount == 0 {
+		return nil, errs.NewBadRequestError("Free orders cannot be refunded")
+	}
+
+	if err := s.ensureNoOpenRefund(ctx, order.ID); err != nil {
+		return nil, err
+	}
+
+	_, err = s.registrationRepository.GetByEventAndUser(ctx, order.EventID, userID)
+	var notFoundErr *errs.NotFoundError
+	if errors.As(err, &notFoundErr) {
+		return nil, errs.NewBadRequestError("Tickets that have been transferred cannot be refunded")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	event, err := s.eventRepository.GetByID(ctx, order.EventID)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := s.GetPolicy(ctx, event.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	percent := policy.PercentAt(event.StartDate, time.Now())
+	if percent == 0 {
+		return nil, errs.NewBadRequestError("Order is not eligible for a refund under the event's refund policy")
+	}
+
+	refund, err := newRefund(order, percent, input.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.refundRepository.Create(ctx, refund, userID); err != nil {
+		return nil, err
+	}
+
+	return refund, nil
+}
+
+// ApproveRefund sends a requested refund to the payment provider.
+func (s *refundServiceImpl) ApproveRefund(ctx context.Context, refundID string, userID string) (*model.Refund, error) {
+	refund, err := s.refundRepository.GetByID(ctx, refundID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.authorizeEventOwner(ctx, refund.EventID, userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.refundRepository.Claim(ctx, refund.ID); err != nil {
+		return nil, err
+	}
+
+	if err := s.processRefund(ctx, refund, userID); err != nil {
+		return nil, err
+	}
+
+	return refund, nil
+}
+
+func (s *refundServiceImpl) RejectRefund(ctx context.Context, refundID string, input *model.RefundRequestInput, userID string) (*model.Refund, error) {
+	refund, err := s.refundRepository.GetByID(ctx, refundID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.authorizeEventOwner(ctx, refund.EventID, userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.refundRepository.Reject(ctx, refund, userID, input.Reason); err != nil {
+		return nil, err
+	}
+
+	return refund, nil
+}
+
+func (s *refundServiceImpl) ListOrderRefunds(ctx context.Context, orderID string, userID string) ([]*model.Refund, error) {
+	order, err := s.orderRepository.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.UserID != userID {
+		return nil, errs.NewForbiddenError("You can only view your own orders")
+	}
+
+	return s.refundRepository.ListByOrder(ctx, orderID)
+}
+
+func (s *refundServiceImpl) ListEventRefunds(ctx context.Context, eventID string, userID string) ([]*model.Refund, error) {
+	if _, err := s.authorizeEventOwner(ctx, eventID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.refundRepository.ListByEvent(ctx, eventID)
+}
+
+func (s *refundServiceImpl) ListLedger(ctx context.Context, eventID string, userID string) ([]*model.RefundLedgerEntry, error) {
+	if _, err := s.authorizeEventOwner(ctx, eventID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.refundRepository.ListLedger(ctx, eventID)
+}
+
+// CancelEvent cancels an event, releases its pending orders and fully refunds every
+// paid order regardless of the refund policy. Calling it again on a cancelled event
+// retries the orders whose refund failed.
+func (s *refundServiceImpl) CancelEvent(ctx context.Context, eventID string, input *model.CancelEventInput, userID string) (*model.CancelEventOutput, error) {
+	event, err := s.authorizeEventOwner(ctx, eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	cancelledAt := time.Now()
+	if event.CancelledAt != nil {
+		cancelledAt = *event.CancelledAt
+	} else if err := s.eventRepository.Cancel(ctx, event.ID, cancelledAt); err != nil {
+		return nil, err
+	}
+
+	output := &model.CancelEventOutput{
+		EventID:     event.ID,
+		CancelledAt: cancelledAt,
+		Refunds:     []*model.Refund{},
+	}
+
+	pending, err := s.orderRepository.ListByEvent(ctx, event.ID, model.OrderStatusPending)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, order := range pending {
+		if err := s.orderRepository.Release(ctx, order.ID, model.OrderStatusCancelled, ""); err != nil {
+			return nil, err
+		}
+		output.ReleasedOrders++
+	}
+
+	paid, err := s.orderRepository.ListByEvent(ctx, event.ID, model.OrderStatusPaid)
+	if err != nil {
+		return nil, err
+	}
+
+	// Partially refunded orders get back what the earlier refund kept.
+	partiallyRefunded, err := s.orderRepository.ListByEvent(ctx, event.ID, model.OrderStatusPartiallyRefunded)
+	if err != nil {
+		return nil, err
+	}
+	paid = append(paid, partiallyRefunded...)
+
+	reason := input.Reason
+	if reason == "" {
+		reason = "Event cancelled"
+	}
+
+	for _, order := range paid {
+		refunded, open, err := s.refundedAmount(ctx, order.ID)
+		if err != nil {
+			return nil, err
+		}
+		if open {
+			log.Printf("[WARN] skipping refund of order %s: a refund for it is already in progress", order.ID)
+			continue
+		}
+
+		if order.TotalAmount > 0 && refunded >= order.TotalAmount {
+			continue
+		}
+
+		refund, err := newRefund(order, 100, reason)
+		if err != nil {
+			log.Printf("[FAIL] cannot refund order %s: %v", order.ID, err)
+			output.FailedRefunds++
+			continue
+		}
+		refund.Amount -= refunded
+
+		if err := s.refundRepository.Create(ctx, refund, userID); err != nil {
+			return nil, err
+		}
+
+		if err := s.refundRepository.Claim(ctx, refund.ID); err != nil {
+			return nil, err
+		}
+
+		if err := s.processRefund(ctx, refund, userID); err != nil {
+			output.FailedRefunds++
+		}
+
+		output.Refunds = append(output.Refunds, refund)
+	}
+
+	return output, nil
+}
+
+// processRefund pays out a claimed refund through the provider and records the
+// outcome in the ledger. Zero-amount refunds complete without the provider.
+func (s *refundServiceImpl) processRefund(ctx context.Context, refund *model.Refund, actorID string) error {
+	if refund.Amount == 0 || refund.PaymentID == nil {
+		return s.refundRepository.Complete(ctx, refund, actorID, "")
+	}
+
+	order, err := s.orderRepository.GetByID(ctx, refund.OrderID)
+	if err != nil {
+		return err
+	}
+
+	var intentID string
+	for _, p := range order.Payments {
+		if p.ID == *refund.PaymentID {
+			intentID = p.ProviderRef
+		}
+	}
+
+	result, err := s.provider.Refund(ctx, payment.RefundRequest{
+		IntentID: intentID,
+		Amount:   refund.Amount,
+		Currency: refund.Currency,
+		Reason:   refund.Reason,
+	})
+	if err != nil {
+		log.Printf("[FAIL] refund %s failed at the provider: %v", refund.ID, err)
+		if failErr := s.refundRepository.Fail(ctx, refund, actorID, err.Error()); failErr != nil {
+			return failErr
+		}
+		return errs.NewInternalServerError("Refund failed at the payment provider")
+	}
+
+	return s.refundRepository.Complete(ctx, refund, actorID, result.ID)
+}
+
+// ensureNoOpenRefund rejects a new refund while another one for the order is
+// still waiting or already paid out.
+func (s *refundServiceImpl) ensureNoOpenRefund(ctx context.Context, orderID string) error {
+	refunds, err := s.refundRepository.ListByOrder(ctx, orderID)
+	if err != nil {
+		return err
+	}
+
+	for _, refund := range refunds {
+		switch refund.Status {
+		case model.RefundStatusRequested, model.RefundStatusProcessing, model.RefundStatusSucceeded:
+			return errs.NewConflictError("A refund for this order is already in progress")
+		}
+	}
+
+	return nil
+}
+
+// refundedAmount returns how much of an order has been paid back so far and
+// whether a refund for it is still waiting or being processed.
+func (s *refundServiceImpl) refundedAmount(ctx context.Context, orderID string) (int64, bool, error) {
+	refunds, err := s.refundRepository.ListByOrder(ctx, orderID)
+	if err != nil {
+		return 0, false, err
+	}
+
+	var refunded int64
+	open := false
+	for _, refund := range refunds {
+		switch refund.Status {
+		case model.RefundStatusRequested, model.RefundStatusProcessing:
+			open = true
+		case model.RefundStatusSucceeded:
+			refunded += refund.Amount
+		}
+	}
+
+	return refunded, open, nil
+}
+
+func (s *refundServiceImpl) authorizeEventOwner(ctx context.Context, eventID string, userID string) (*model.Event, error) {
+	event, err := s.eventRepository.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if event.CreatorID != userID {
+		return nil, errs.NewForbiddenError("Only the event organizers can manage refunds")
+	}
+
+	return event, nil
+}
+
+// newRefund builds a refund of percent of the order against its successful payment.
+func newRefund(order *model.Order, percent int, reason string) (*model.Refund, error) {
+	refund := &model.Refund{
+		OrderID:  order.ID,
+		EventID:  order.EventID,
+		UserID:   order.UserID,
+		Amount:   order.TotalAmount * int64(percent) / 100,
+		Currency: order.Currency,
+		Percent:  percent,
+		Reason:   reason,
+		Status:   model.RefundStatusRequested,
+	}
+
+	for _, p := range order.Payments {
+		if p.Status == model.PaymentStatusSucceeded {
+			paymentID := p.ID
+			refund.PaymentID = &paymentID
+		}
+	}
+
+	if refund.Amount > 0 && refund.PaymentID == nil {
+		return nil, errs.NewBadRequestError("Order has no successful payment to refund")
+	}
+
+	return refund, nil
+}
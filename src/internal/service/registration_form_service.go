@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+	"unicode/utf8"
+
+	"github.com/hafiztri123/src/internal/model"
+	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"github.com/hafiztri123/src/internal/repository"
+)
+
+type RegistrationFormService interface {
+	GetForm(ctx context.Context, eventID string) (*model.RegistrationForm, error)
+	PutForm(ctx context.Context, eventID string, input *model.PutRegistrationFormInput, userID string) (*model.RegistrationForm, error)
+	DeleteForm(ctx context.Context, eventID string, userID string) error
+	ValidateAnswers(ctx context.Context, eventID string, answers map[string]interface{}) (map[string]interface{}, error)
+}
+
+type registrationFormServiceImpl struct {
+	registrationFormRepository repository.RegistrationFormRepository
+	eventRepository            repository.EventRepository
+}
+
+func NewRegistrationFormService(registrationFormRepo repository.RegistrationFormRepository, eventRepo repository.EventRepository) RegistrationFormService {
+	return &registrationFormServiceImpl{
+		registrationFormRepository: registrationFormRepo,
+		eventRepository:            eventRepo,
+	}
+}
+
+// GetForm returns the event's registration form. Events without one get a form
+// with no fields.
+func (s *registrationFormServiceImpl) GetForm(ctx context.Context, eventID string) (*model.RegistrationForm, error) {
+	event, err := s.eventRepository.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.formOf(ctx, event.ID)
+}
+
+// PutForm replaces the event's registration form. Only the organizer may change it.
+// Registrations made before keep the answers they were given.
+func (s *registrationFormServiceImpl) PutForm(ctx context.Context, eventID string, input *model.PutRegistrationFormInput, userID string) (*model.RegistrationForm, error) {
+	event, err := s.eventRepository.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if event.CreatorID != userID {
+		return nil, errs.NewForbiddenError("Only the event organizers can change the registration form")
+	}
+
+	if err := checkFormFields(input.Fields); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	form := &model.RegistrationForm{
+		EventID:   event.ID,
+		Fields:    input.Fields,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if form.Fields == nil {
+		form.Fields = []model.FormField{}
+	}
+
+	if err := s.registrationFormRepository.Save(ctx, form); err != nil {
+		return nil, err
+	}
+
+	return form, nil
+}
+
+func (s *registrationFormServiceImpl) DeleteForm(ctx context.Context, eventID string, userID string) error {
+	event, err := s.eventRepository.GetByID(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	if event.CreatorID != userID {
+		return errs.NewForbiddenError("Only the event organizers can change the registration form")
+	}
+
+	return s.registrationFormRepository.Delete(ctx, event.ID)
+}
+
+// ValidateAnswers checks answers against the event's registration form and returns
+// them as they should be stored. Unknown fields are rejected, unanswered optional
+// checkboxes are stored as unchecked and empty optional answers are dropped.
+func (s *registrationFormServiceImpl) ValidateAnswers(ctx context.Context, eventID string, answers map[string]interface{}) (map[string]interface{}, error) {
+	form, err := s.formOf(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]model.FormField, len(form.Fields))
+	for _, field := range form.Fields {
+		fields[field.Key] = field
+	}
+
+	for key := range answers {
+		if _, ok := fields[key]; !ok {
+			return nil, errs.NewValidationError(fmt.Sprintf("answers.%s is not a field of the registration form", key))
+		}
+	}
+
+	if len(form.Fields) == 0 {
+		return nil, nil
+	}
+
+	validated := make(map[string]interface{}, len(form.Fields))
+	for _, field := range form.Fields {
+		value, err := validateAnswer(field, answers[field.Key])
+		if err != nil {
+			return nil, errs.NewValidationError(fmt.Sprintf("answers.%s %s", field.Key, err.Error()))
+		}
+		if value != nil {
+			validated[field.Key] = value
+		}
+	}
+
+	return validated, nil
+}
+
+func (s *registrationFormServiceImpl) formOf(ctx context.Context, eventID string) (*model.RegistrationForm, error) {
+	form, err := s.registrationFormRepository.Get(ctx, eventID)
+
+	var notFoundErr *errs.NotFoundError
+	if errors.As(err, &notFoundErr) {
+		return &model.RegistrationForm{EventID: eventID, Fields: []model.FormField{}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return form, nil
+}
+
+// checkFormFields enforces the rules of a form schema that struct validation cannot
+// express.
+func checkFormFields(fields []model.FormField) error {
+	keys := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		if keys[field.Key] {
+			return errs.NewValidationError(fmt.Sprintf("Field key %q is used more than once", field.Key))
+		}
+		keys[field.Key] = true
+
+		switch field.Type {
+		case model.FormFieldSelect:
+			if len(field.Options) == 0 {
+				return errs.NewValidationError(fmt.Sprintf("Select field %q needs options", field.Key))
+			}
+		default:
+			if len(field.Options) > 0 {
+				return errs.NewValidationError(fmt.Sprintf("Only select fields have options, %q is a %s field", field.Key, field.Type))
+			}
+		}
+
+		if field.Type != model.FormFieldText && (field.MinLength != nil || field.MaxLength != nil || field.Pattern != "") {
+			return errs.NewValidationError(fmt.Sprintf("Only text fields have length and pattern rules, %q is a %s field", field.Key, field.Type))
+		}
+
+		if field.MinLength != nil && field.MaxLength != nil && *field.MinLength > *field.MaxLength {
+			return errs.NewValidationError(fmt.Sprintf("Field %q has a min_length above its max_length", field.Key))
+		}
+
+		if field.Pattern != "" {
+			if _, err := regexp.Compile(field.Pattern); err != nil {
+				return errs.NewValidationError(fmt.Sprintf("Field %q has an invalid pattern", field.Key))
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateAnswer checks one answer and returns the value to store, nil for an
+// unanswered optional field.
+func validateAnswer(field model.FormField, answer interface{}) (interface{}, error) {
+	switch field.Type {
+	case model.FormFieldCheckbox:
+		if answer == nil {
+			if field.Required {
+				return nil, errors.New("must be checked")
+			}
+			return false, nil
+		}
+		checked, ok := answer.(bool)
+		if !ok {
+			return nil, errors.New("must be true or false")
+		}
+		if field.Required && !checked {
+			return nil, errors.New("must be checked")
+		}
+		return checked, nil
+
+	case model.FormFieldSelect:
+		if answer == nil || answer == "" {
+			if field.Required {
+				return nil, errors.New("is required")
+			}
+			return nil, nil
+		}
+		choice, ok := answer.(string)
+		if !ok {
+			return nil, errors.New("must be one of the options")
+		}
+		for _, option := range field.Options {
+			if choice == option {
+				return choice, nil
+			}
+		}
+		return nil, errors.New("must be one of the options")
+
+	default:
+		if answer == nil || answer == "" {
+			if field.Required {
+				return nil, errors.New("is required")
+			}
+			return nil, nil
+		}
+		text, ok := answer.(string)
+		if !ok {
+			return nil, errors.New("must be text")
+		}
+		length := utf8.RuneCountInString(text)
+		if field.MinLength != nil && length < *field.MinLength {
+			return nil, fmt.Errorf("must be at least %d characters", *field.MinLength)
+		}
+		if field.MaxLength != nil && length > *field.MaxLength {
+			return nil, fmt.Errorf("must be at most %d characters", *field.MaxLength)
+		}
+		if field.Pattern != "" {
+			pattern, err := regexp.Compile("^(?:" + field.Pattern + ")$")
+			if err != nil || !pattern.MatchString(text) {
+				return nil, errors.New("is not in the expected format")
+			}
+		}
+		return text, nil
+	}
+}
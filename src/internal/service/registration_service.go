@@ -0,0 +1,414 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hafiztri123/src/internal/model"
+	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"github.com/hafiztri123/src/internal/pkg/ticket"
+	"github.com/hafiztri123/src/internal/repository"
+)
+
+type RegistrationService interface {
+	Register(ctx context.Context, eventID string, input *model.EventRegistrationInput, userID string) (*model.RegistrationOutput, error)
+	CheckIn(ctx context.Context, eventID string, input *model.CheckInInput, organizerID string) (*model.Registration, error)
+	GetTicket(ctx context.Context, eventID string, userID string) (*model.Ticket, error)
+	UpdateAttendees(ctx context.Context, eventID string, input *model.UpdateAttendeesInput, userID string) (*model.Ticket, error)
+	GetRegistration(ctx context.Context, eventID string, attendeeID string, userID string) (*model.Registration, error)
+	ExportAttendees(ctx context.Context, eventID string, organizerID string) (*model.AttendeeExport, error)
+}
+
+type registrationServiceImpl struct {
+	registrationRepository  repository.RegistrationRepository
+	attendeeRepository      repository.AttendeeRepository
+	eventRepository         repository.EventRepository
+	userRepository          repository.UserRepository
+	analyticsService        AnalyticsService
+	registrationFormService RegistrationFormService
+	ticketSigner            *ticket.Signer
+}
+
+func NewRegistrationService(registrationRepo repository.RegistrationRepository, attendeeRepo repository.AttendeeRepository, eventRepo repository.EventRepository, userRepo repository.UserRepository, analyticsService AnalyticsService, registrationFormService RegistrationFormService, ticketSigner *ticket.Signer) RegistrationService {
+	return &registrationServiceImpl{
+		registrationRepository:  registrationRepo,
+		attendeeRepository:      attendeeRepo,
+		eventRepository:         eventRepo,
+		userRepository:          userRepo,
+		analyticsService:        analyticsService,
+		registrationFormService: registrationFormService,
+		ticketSigner:            ticketSigner,
+	}
+}
+
+// Register signs the user up for an event with their answers to its registration
+// form. Overlapping registrations are allowed, but they are reported back as
+// warnings so the client can surface them.
+func (s *registrationServiceImpl) Register(ctx context.Context, eventID string, input *model.EventRegistrationInput, userID string) (*model.RegistrationOutput, error) {
+	event, err := s.eventRepository.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if event.CancelledAt != nil {
+		return nil, errs.NewBadRequestError("Event has been cancelled")
+	}
+
+	_, err = s.registrationRepository.GetByEventAndUser(ctx, eventID, userID)
+	if err == nil {
+		return nil, errs.NewDuplicateEntryError("Already registered for this event")
+	}
+
+	var notFoundErr *errs.NotFoundError
+	if !errors.As(err, &notFoundErr) {
+		return nil, err
+	}
+
+	answers, err := s.registrationFormService.ValidateAnswers(ctx, event.ID, input.Answers)
+	if err != nil {
+		return nil, err
+	}
+
+	overlapping, err := s.registrationRepository.ListOverlappingForUser(ctx, userID, event.StartDate, event.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	registration := &model.Registration{
+		EventID:   event.ID,
+		UserID:    userID,
+		Answers:   answers,
+		CreatedAt: time.Now(),
+	}
+
+	if err := s.registrationRepository.Create(ctx, registration); err != nil {
+		return nil, err
+	}
+
+	s.analyticsService.RecordRegistration(ctx, event.ID)
+
+	output := &model.RegistrationOutput{
+		Registration: registration,
+		Conflicts:    overlapping,
+	}
+
+	for _, other := range overlapping {
+		output.Warnings = append(output.Warnings, fmt.Sprintf("Overlaps with %q (%s - %s)",
+			other.Title, other.StartDate.Format(time.RFC3339), other.EndDate.Format(time.RFC3339)))
+	}
+
+	return output, nil
+}
+
+// CheckIn marks a registered attendee as present. Only the event creator may check
+// attendees in, and each attendee can only be checked in once. Attendees are named
+// by user ID, by attendee ID or by their ticket code; codes of tickets that have
+// since been reissued are refused. Group bookings are checked in per attendee, and
+// the booking is returned with its attendees.
+func (s *registrationServiceImpl) CheckIn(ctx context.Context, eventID string, input *model.CheckInInput, organizerID string) (*model.Registration, error) {
+	event, err := s.eventRepository.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if event.CreatorID != organizerID {
+		return nil, errs.NewForbiddenError("Only the event organizers can check attendees in")
+	}
+
+	var registration *model.Registration
+	var attendee *model.Attendee
+	switch {
+	case input.Ticket != "":
+		registration, attendee, err = s.ticketHolder(ctx, event.ID, input.Ticket)
+	case input.AttendeeID != "":
+		attendee, err = s.eventAttendee(ctx, event.ID, input.AttendeeID)
+	default:
+		registration, err = s.registrationRepository.GetByEventAndUser(ctx, event.ID, input.UserID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if attendee != nil {
+		if err := s.attendeeRepository.CheckIn(ctx, attendee, now); err != nil {
+			return nil, err
+		}
+
+		registration, err = s.registrationRepository.GetByID(ctx, attendee.RegistrationID)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		if len(registration.Attendees) > 0 {
+			return nil, errs.NewBadRequestError("This is a group booking; check its attendees in by ticket or attendee ID")
+		}
+
+		registration, err = s.registrationRepository.CheckIn(ctx, event.ID, registration.UserID, now)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	s.analyticsService.RecordCheckIn(ctx, event.ID)
+
+	return registration, nil
+}
+
+// GetTicket returns the user's ticket for the event with the code for its QR code.
+// Bookers of a group get every attendee's ticket, and users named as an attendee
+// in someone else's booking get their own.
+func (s *registrationServiceImpl) GetTicket(ctx context.Context, eventID string, userID string) (*model.Ticket, error) {
+	event, err := s.eventRepository.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	registration, err := s.registrationRepository.GetByEventAndUser(ctx, event.ID, userID)
+	var notFoundErr *errs.NotFoundError
+	if errors.As(err, &notFoundErr) {
+		return s.attendeeTicket(ctx, event.ID, userID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.bookingTicket(registration), nil
+}
+
+// UpdateAttendees names the attendees of the user's group booking. Attendees whose
+// name or email changes get a new ticket code; attendees who have checked in can
+// no longer be changed.
+func (s *registrationServiceImpl) UpdateAttendees(ctx context.Context, eventID string, input *model.UpdateAttendeesInput, userID string) (*model.Ticket, error) {
+	event, err := s.eventRepository.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	registration, err := s.registrationRepository.GetByEventAndUser(ctx, event.ID, userID)
+	var notFoundErr *errs.NotFoundError
+	if errors.As(err, &notFoundErr) {
+		return nil, errs.NewNotFoundError("You have no ticket for this event")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(registration.Attendees) == 0 {
+		return nil, errs.NewBadRequestError("Only group bookings have attendees to name")
+	}
+
+	attendees := make(map[string]*model.Attendee, len(registration.Attendees))
+	for i := range registration.Attendees {
+		attendees[registration.Attendees[i].ID] = &registration.Attendees[i]
+	}
+
+	seen := make(map[string]bool, len(input.Attendees))
+	var changed []*model.Attendee
+	for _, details := range input.Attendees {
+		attendee, ok := attendees[details.ID]
+		if !ok {
+			return nil, errs.NewNotFoundError("Attendee " + details.ID + " not found")
+		}
+
+		if seen[details.ID] {
+			return nil, errs.NewBadRequestError("Attendee " + details.ID + " is listed more than once")
+		}
+		seen[details.ID] = true
+
+		name := strings.TrimSpace(details.Name)
+		email := strings.TrimSpace(details.Email)
+		if name == attendee.Name && email == attendee.Email {
+			continue
+		}
+
+		if attendee.CheckedInAt != nil {
+			return nil, errs.NewBadRequestError("Attendee " + details.ID + " has already checked in")
+		}
+
+		nonce, err := ticket.NewNonce()
+		if err != nil {
+			return nil, errs.NewInternalServerError("Failed to reissue ticket")
+		}
+
+		attendee.Name = name
+		attendee.Email = email
+		attendee.TicketNonce = nonce
+		changed = append(changed, attendee)
+	}
+
+	if len(changed) > 0 {
+		if err := s.attendeeRepository.UpdateDetails(ctx, changed); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.bookingTicket(registration), nil
+}
+
+// bookingTicket returns a registration's ticket. A group booking carries its
+// attendees' tickets instead of a code of its own.
+func (s *registrationServiceImpl) bookingTicket(registration *model.Registration) *model.Ticket {
+	if len(registration.Attendees) == 0 {
+		return &model.Ticket{
+			Registration: registration,
+			Code:         s.ticketSigner.Sign(registration.ID, registration.TicketNonce),
+		}
+	}
+
+	output := &model.Ticket{Registration: registration}
+	for i := range registration.Attendees {
+		attendee := &registration.Attendees[i]
+		output.Attendees = append(output.Attendees, &model.AttendeeTicket{
+			Attendee: attendee,
+			Code:     s.ticketSigner.Sign(attendee.ID, attendee.TicketNonce),
+		})
+	}
+
+	return output
+}
+
+// attendeeTicket returns the tickets the user holds as a named attendee of other
+// users' group bookings. Attendees are matched by email, so the user must have
+// verified theirs; otherwise anyone could register with someone else's address
+// and take their tickets.
+func (s *registrationServiceImpl) attendeeTicket(ctx context.Context, eventID string, userID string) (*model.Ticket, error) {
+	user, err := s.userRepository.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.EmailVerifiedAt == nil {
+		return nil, errs.NewForbiddenError("Verify your email address to see tickets booked for you")
+	}
+
+	var attendees []*model.Attendee
+	if user.Email != "" {
+		attendees, err = s.attendeeRepository.ListByEventAndEmail(ctx, eventID, user.Email)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(attendees) == 0 {
+		return nil, errs.NewNotFoundError("You have no ticket for this event")
+	}
+
+	output := &model.Ticket{}
+	for _, attendee := range attendees {
+		output.Attendees = append(output.Attendees, &model.AttendeeTicket{
+			Attendee: attendee,
+			Code:     s.ticketSigner.Sign(attendee.ID, attendee.TicketNonce),
+		})
+	}
+
+	return output, nil
+}
+
+// ticketHolder returns the registration or group booking attendee a ticket code
+// currently belongs to.
+func (s *registrationServiceImpl) ticketHolder(ctx context.Context, eventID string, code string) (*model.Registration, *model.Attendee, error) {
+	ticketID, nonce, err := s.ticketSigner.Parse(code)
+	if err != nil {
+		return nil, nil, errs.NewBadRequestError("Ticket is not valid")
+	}
+
+	registration, err := s.registrationRepository.GetByID(ctx, ticketID)
+	var notFoundErr *errs.NotFoundError
+	if errors.As(err, &notFoundErr) {
+		attendee, err := s.attendeeRepository.GetByID(ctx, ticketID)
+		if err != nil {
+			return nil, nil, errs.NewBadRequestError("Ticket is not valid")
+		}
+
+		if err := checkTicketCode(attendee.EventID, attendee.TicketNonce, eventID, nonce); err != nil {
+			return nil, nil, err
+		}
+
+		return nil, attendee, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := checkTicketCode(registration.EventID, registration.TicketNonce, eventID, nonce); err != nil {
+		return nil, nil, err
+	}
+
+	return registration, nil, nil
+}
+
+// checkTicketCode checks a scanned code against the ticket it names.
+func checkTicketCode(ticketEventID, ticketNonce, eventID, nonce string) error {
+	if ticketEventID != eventID {
+		return errs.NewBadRequestError("Ticket is for another event")
+	}
+
+	if ticketNonce != nonce {
+		return errs.NewBadRequestError("Ticket has been reissued to another attendee")
+	}
+
+	return nil
+}
+
+// eventAttendee returns an attendee of one of the event's group bookings.
+func (s *registrationServiceImpl) eventAttendee(ctx context.Context, eventID string, attendeeID string) (*model.Attendee, error) {
+	attendee, err := s.attendeeRepository.GetByID(ctx, attendeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if attendee.EventID != eventID {
+		return nil, errs.NewNotFoundError("Attendee not found")
+	}
+
+	return attendee, nil
+}
+
+// GetRegistration returns an attendee's registration with their answers. Attendees
+// can see their own registration and organizers can see every registration of
+// their event.
+func (s *registrationServiceImpl) GetRegistration(ctx context.Context, eventID string, attendeeID string, userID string) (*model.Registration, error) {
+	event, err := s.eventRepository.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if attendeeID != userID && event.CreatorID != userID {
+		return nil, errs.NewForbiddenError("Only the attendee and the event organizers can see the registration")
+	}
+
+	return s.registrationRepository.GetDetail(ctx, event.ID, attendeeID)
+}
+
+// ExportAttendees returns every registration of the event together with the form
+// its answers belong to. Only the organizer may export attendees.
+func (s *registrationServiceImpl) ExportAttendees(ctx context.Context, eventID string, organizerID string) (*model.AttendeeExport, error) {
+	event, err := s.eventRepository.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if event.CreatorID != organizerID {
+		return nil, errs.NewForbiddenError("Only the event organizers can export attendees")
+	}
+
+	form, err := s.registrationFormService.GetForm(ctx, event.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	registrations, err := s.registrationRepository.ListByEvent(ctx, event.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.AttendeeExport{
+		Event:         event,
+		Form:          form,
+		Registrations: registrations,
+	}, nil
+}
@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hafiztri123/src/internal/model"
+	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"github.com/hafiztri123/src/internal/repository"
+)
+
+func TestNewEventRevision(t *testing.T) {
+	start := time.Date(2025, 7, 15, 9, 0, 0, 0, time.UTC)
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	before := model.EventSnapshot{
+		Title:       "Tech Workshop",
+		Description: "Hands-on session",
+		Venue:       "Main Hall",
+		CategoryID:  "category-1",
+		StartDate:   start,
+		EndDate:     start.Add(3 * time.Hour),
+	}
+
+	withVenue := before
+	withVenue.Venue = "Annex"
+
+	sameInstants := before
+	sameInstants.StartDate = start.In(jakarta)
+	sameInstants.EndDate = start.Add(3 * time.Hour).In(jakarta)
+
+	moved := before
+	moved.StartDate = start.Add(time.Hour)
+	moved.EndDate = start.Add(4 * time.Hour)
+
+	tests := []struct {
+		name   string
+		before *model.EventSnapshot
+		after  model.EventSnapshot
+		want   map[string]model.FieldChange
+	}{
+		{
+			name:  "first revision records every field",
+			after: before,
+			want: map[string]model.FieldChange{
+				"title":       {Old: nil, New: "Tech Workshop"},
+				"description": {Old: nil, New: "Hands-on session"},
+				"venue":       {Old: nil, New: "Main Hall"},
+				"category_id": {Old: nil, New: "category-1"},
+				"start_date":  {Old: nil, New: "2025-07-15T09:00:00Z"},
+				"end_date":    {Old: nil, New: "2025-07-15T12:00:00Z"},
+			},
+		},
+		{
+			name:   "only changed fields are recorded",
+			before: &before,
+			after:  withVenue,
+			want: map[string]model.FieldChange{
+				"venue": {Old: "Main Hall", New: "Annex"},
+			},
+		},
+		{
+			name:   "dates are compared as instants",
+			before: &before,
+			after:  moved,
+			want: map[string]model.FieldChange{
+				"start_date": {Old: "2025-07-15T09:00:00Z", New: "2025-07-15T10:00:00Z"},
+				"end_date":   {Old: "2025-07-15T12:00:00Z", New: "2025-07-15T13:00:00Z"},
+			},
+		},
+		{name: "no change records nothing", before: &before, after: before},
+		{name: "same instants in another zone record nothing", before: &before, after: sameInstants},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			revision, err := newEventRevision("actor-1", tt.before, tt.after)
+			if err != nil {
+				t.Fatalf("newEventRevision() error = %v", err)
+			}
+
+			if tt.want == nil {
+				if revision != nil {
+					t.Fatalf("newEventRevision() = %s, want no revision", revision.Changes)
+				}
+				return
+			}
+			if revision == nil {
+				t.Fatal("newEventRevision() = nil, want a revision")
+			}
+
+			if revision.ActorID != "actor-1" {
+				t.Errorf("ActorID = %q, want %q", revision.ActorID, "actor-1")
+			}
+
+			var changes map[string]model.FieldChange
+			if err := json.Unmarshal(revision.Changes, &changes); err != nil {
+				t.Fatalf("Changes is not JSON: %v", err)
+			}
+			if !reflect.DeepEqual(changes, tt.want) {
+				t.Errorf("Changes = %v, want %v", changes, tt.want)
+			}
+
+			var snapshot model.EventSnapshot
+			if err := json.Unmarshal(revision.Snapshot, &snapshot); err != nil {
+				t.Fatalf("Snapshot is not JSON: %v", err)
+			}
+			if !snapshot.StartDate.Equal(tt.after.StartDate) || !snapshot.EndDate.Equal(tt.after.EndDate) ||
+				snapshot.Title != tt.after.Title || snapshot.Venue != tt.after.Venue {
+				t.Errorf("Snapshot = %+v, want %+v", snapshot, tt.after)
+			}
+		})
+	}
+}
+
+// rollbackEvents holds a single event and records the updates made to it.
+type rollbackEvents struct {
+	repository.EventRepository
+	event     *model.Event
+	revisions []*model.EventRevision
+}
+
+func (r *rollbackEvents) GetByID(ctx context.Context, id string) (*model.Event, error) {
+	if id != r.event.ID {
+		return nil, errs.NewNotFoundError("Event not found")
+	}
+	event := *r.event
+	return &event, nil
+}
+
+func (r *rollbackEvents) Update(ctx context.Context, event *model.Event, revision *model.EventRevision) error {
+	r.event = event
+	r.revisions = append(r.revisions, revision)
+	return nil
+}
+
+type rollbackRevisions struct {
+	repository.RevisionRepository
+	revisions map[string]*model.EventRevision
+}
+
+func (r *rollbackRevisions) GetByID(ctx context.Context, eventID, id string) (*model.EventRevision, error) {
+	revision, ok := r.revisions[id]
+	if !ok {
+		return nil, errs.NewNotFoundError("Revision not found")
+	}
+	return revision, nil
+}
+
+type rollbackCategories struct {
+	repository.CategoryRepository
+}
+
+func (r *rollbackCategories) IsIDExists(ctx context.Context, id string) bool {
+	return true
+}
+
+type rollbackOrganizations struct {
+	OrganizationService
+}
+
+func (o *rollbackOrganizations) AuthorizeEditor(ctx context.Context, creatorID string, userID string) error {
+	if creatorID != userID {
+		return errs.NewForbiddenError("Only the creator or the organization owners and admins can change it")
+	}
+	return nil
+}
+
+func TestRollbackEvent(t *testing.T) {
+	start := time.Date(2025, 7, 15, 9, 0, 0, 0, time.UTC)
+	original := model.EventSnapshot{
+		Title:      "Tech Workshop",
+		Venue:      "Main Hall",
+		CategoryID: "category-1",
+		StartDate:  start,
+		EndDate:    start.Add(3 * time.Hour),
+	}
+	first, err := newEventRevision("creator-1", nil, original)
+	if err != nil {
+		t.Fatalf("newEventRevision() error = %v", err)
+	}
+
+	current := original
+	current.Title = "Tech Workshop (moved)"
+	current.Venue = "Annex"
+	event := &model.Event{ID: "event-1", CreatorID: "creator-1", Version: 2}
+	current.ApplyTo(event)
+
+	newService := func() (*eventService, *rollbackEvents) {
+		events := &rollbackEvents{event: event}
+		return &eventService{
+			eventRepository:     events,
+			categoryRepository:  &rollbackCategories{},
+			revisionRepository:  &rollbackRevisions{revisions: map[string]*model.EventRevision{"revision-1": first}},
+			organizationService: &rollbackOrganizations{},
+		}, events
+	}
+
+	t.Run("restores the recorded fields as a new revision", func(t *testing.T) {
+		s, events := newService()
+
+		if err := s.RollbackEvent(context.Background(), "event-1", "revision-1", "creator-1"); err != nil {
+			t.Fatalf("RollbackEvent() error = %v", err)
+		}
+
+		if got := model.NewEventSnapshot(events.event); got != original {
+			t.Errorf("event = %+v, want %+v", got, original)
+		}
+		if len(events.revisions) != 1 || events.revisions[0] == nil {
+			t.Fatalf("recorded %d revisions, want 1", len(events.revisions))
+		}
+
+		var changes map[string]model.FieldChange
+		if err := json.Unmarshal(events.revisions[0].Changes, &changes); err != nil {
+			t.Fatalf("Changes is not JSON: %v", err)
+		}
+		want := map[string]model.FieldChange{
+			"title": {Old: "Tech Workshop (moved)", New: "Tech Workshop"},
+			"venue": {Old: "Annex", New: "Main Hall"},
+		}
+		if !reflect.DeepEqual(changes, want) {
+			t.Errorf("Changes = %v, want %v", changes, want)
+		}
+	})
+
+	t.Run("refuses other users", func(t *testing.T) {
+		s, events := newService()
+
+		err := s.RollbackEvent(context.Background(), "event-1", "revision-1", "someone-else")
+		var forbidden *errs.ForbiddenError
+		if !errors.As(err, &forbidden) {
+			t.Fatalf("RollbackEvent() error = %v, want a ForbiddenError", err)
+		}
+		if len(events.revisions) != 0 {
+			t.Errorf("event was updated")
+		}
+	})
+
+	t.Run("unknown revision", func(t *testing.T) {
+		s, _ := newService()
+
+		err := s.RollbackEvent(context.Background(), "event-1", "revision-9", "creator-1")
+		var notFound *errs.NotFoundError
+		if !errors.As(err, &notFound) {
+			t.Fatalf("RollbackEvent() error = %v, want a NotFoundError", err)
+		}
+	})
+}
@@ -0,0 +1,315 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hafiztri123/src/internal/model"
+	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"github.com/hafiztri123/src/internal/repository"
+)
+
+type SeatingService interface {
+	GetSeatMap(ctx context.Context, eventID string) (*model.SeatMap, error)
+	PutSeatMap(ctx context.Context, eventID string, input *model.PutSeatMapInput, userID string) (*model.SeatMap, error)
+	HoldSeats(ctx context.Context, eventID string, input *model.HoldSeatsInput, userID string) (*model.SeatHold, error)
+	ReleaseHold(ctx context.Context, holdID string, userID string) error
+	ConfirmHold(ctx context.Context, holdID string, input *model.ConfirmSeatHoldInput, userID string) (*model.CheckoutOutput, error)
+}
+
+type seatingServiceImpl struct {
+	seatRepository     repository.SeatRepository
+	seatHoldRepository repository.SeatHoldRepository
+	eventRepository    repository.EventRepository
+	orderRepository    repository.OrderRepository
+	orderService       OrderService
+	holdDuration       time.Duration
+}
+
+func NewSeatingService(seatRepo repository.SeatRepository, seatHoldRepo repository.SeatHoldRepository, eventRepo repository.EventRepository, orderRepo repository.OrderRepository, orderService OrderService, holdDuration time.Duration) SeatingService {
+	return &seatingServiceImpl{
+		seatRepository:     seatRepo,
+		seatHoldRepository: seatHoldRepo,
+		eventRepository:    eventRepo,
+		orderRepository:    orderRepo,
+		orderService:       orderService,
+		holdDuration:       holdDuration,
+	}
+}
+
+// GetSeatMap returns the event's seat map with the current status of every seat.
+func (s *seatingServiceImpl) GetSeatMap(ctx context.Context, eventID string) (*model.SeatMap, error) {
+	event, err := s.eventRepository.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	sections, err := s.seatRepository.GetMap(ctx, event.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var seatIDs []string
+	for _, section := range sections {
+		for _, row := range section.Rows {
+			for _, seat := range row.Seats {
+				seatIDs = append(seatIDs, seat.ID)
+			}
+		}
+	}
+
+	held, err := s.seatHoldRepository.HeldSeats(ctx, event.ID, seatIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, section := range sections {
+		for i := range section.Rows {
+			for j := range section.Rows[i].Seats {
+				seat := &section.Rows[i].Seats[j]
+				switch {
+				case seat.OrderID != nil:
+					seat.Status = model.SeatStatusTaken
+				case held[seat.ID]:
+					seat.Status = model.SeatStatusHeld
+				default:
+					seat.Status = model.SeatStatusAvailable
+				}
+			}
+		}
+	}
+
+	return &model.SeatMap{
+		EventID:  event.ID,
+		Sections: sections,
+	}, nil
+}
+
+// PutSeatMap replaces the event's seat map. Only the organizer may change it, and
+// only while none of its seats is booked. Every seat is priced by one of the
+// event's ticket types, which then sells exactly one ticket per seat.
+func (s *seatingServiceImpl) PutSeatMap(ctx context.Context, eventID string, input *model.PutSeatMapInput, userID string) (*model.SeatMap, error) {
+	event, err := s.eventRepository.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if event.CreatorID != userID {
+		return nil, errs.NewForbiddenError("Only the event organizers can change the seat map")
+	}
+
+	ticketTypes, err := s.orderRepository.ListTicketTypes(ctx, event.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	tiers := make(map[string]bool, len(ticketTypes))
+	for _, ticketType := range ticketTypes {
+		tiers[ticketType.ID] = true
+	}
+
+	now := time.Now()
+	sections := make([]*model.SeatSection, 0, len(input.Sections))
+	for i, sectionInput := range input.Sections {
+		section := &model.SeatSection{
+			EventID:   event.ID,
+			Name:      sectionInput.Name,
+			Position:  i,
+			CreatedAt: now,
+		}
+
+		rowLabels := map[string]bool{}
+		for j, rowInput := range sectionInput.Rows {
+			if rowLabels[rowInput.Label] {
+				return nil, errs.NewValidationError(fmt.Sprintf("Row %q appears twice in section %q", rowInput.Label, section.Name))
+			}
+			rowLabels[rowInput.Label] = true
+
+			row := model.SeatRow{
+				EventID:  event.ID,
+				Label:    rowInput.Label,
+				Position: j,
+			}
+
+			seatLabels := map[string]bool{}
+			for k, seatInput := range rowInput.Seats {
+				if seatLabels[seatInput.Label] {
+					return nil, errs.NewValidationError(fmt.Sprintf("Seat %q appears twice in row %q of section %q", seatInput.Label, row.Label, section.Name))
+				}
+				seatLabels[seatInput.Label] = true
+
+				if !tiers[seatInput.TicketTypeID] {
+					return nil, errs.NewValidationError(fmt.Sprintf("Ticket type %s does not belong to this event", seatInput.TicketTypeID))
+				}
+
+				row.Seats = append(row.Seats, model.Seat{
+					EventID:      event.ID,
+					Label:        seatInput.Label,
+					Position:     k,
+					TicketTypeID: seatInput.TicketTypeID,
+				})
+			}
+
+			section.Rows = append(section.Rows, row)
+		}
+
+		sections = append(sections, section)
+	}
+
+	if err := s.seatRepository.ReplaceMap(ctx, event.ID, sections); err != nil {
+		return nil, err
+	}
+
+	return s.GetSeatMap(ctx, event.ID)
+}
+
+// HoldSeats locks seats for the user for the hold duration. A user holds seats for
+// one event at a time; the hold has to be confirmed or released before another
+// one can be taken.
+func (s *seatingServiceImpl) HoldSeats(ctx context.Context, eventID string, input *model.HoldSeatsInput, userID string) (*model.SeatHold, error) {
+	event, err := s.eventRepository.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if event.CancelledAt != nil {
+		return nil, errs.NewBadRequestError("Event has been cancelled")
+	}
+
+	if !event.EndDate.After(time.Now()) {
+		return nil, errs.NewBadRequestError("Event has already ended")
+	}
+
+	seatIDs := make([]string, 0, len(input.SeatIDs))
+	requested := map[string]bool{}
+	for _, seatID := range input.SeatIDs {
+		if !requested[seatID] {
+			requested[seatID] = true
+			seatIDs = append(seatIDs, seatID)
+		}
+	}
+
+	seats, err := s.seatRepository.ListByIDs(ctx, event.ID, seatIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(seats) != len(seatIDs) {
+		return nil, errs.NewNotFoundError("Seat not found")
+	}
+
+	for _, seat := range seats {
+		if seat.OrderID != nil {
+			return nil, errs.NewConflictError("Seat " + seat.ID + " has already been booked")
+		}
+	}
+
+	hold := &model.SeatHold{
+		ID:        uuid.NewString(),
+		EventID:   event.ID,
+		UserID:    userID,
+		SeatIDs:   seatIDs,
+		ExpiresAt: time.Now().Add(s.holdDuration),
+	}
+
+	if err := s.seatHoldRepository.Hold(ctx, hold); err != nil {
+		return nil, err
+	}
+
+	return hold, nil
+}
+
+func (s *seatingServiceImpl) ReleaseHold(ctx context.Context, holdID string, userID string) error {
+	hold, err := s.ownHold(ctx, holdID, userID)
+	if err != nil {
+		return err
+	}
+
+	return s.seatHoldRepository.Release(ctx, hold)
+}
+
+// ConfirmHold books the held seats. Each seat is bought at its ticket type, so free
+// seats are registered straight away and paid seats open an order that has to be
+// paid like any other checkout. The hold is released once the order has the seats.
+func (s *seatingServiceImpl) ConfirmHold(ctx context.Context, holdID string, input *model.ConfirmSeatHoldInput, userID string) (*model.CheckoutOutput, error) {
+	hold, err := s.ownHold(ctx, holdID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	owns, err := s.seatHoldRepository.Owns(ctx, hold)
+	if err != nil {
+		return nil, err
+	}
+	if !owns {
+		return nil, errs.NewConflictError("Seat hold has expired")
+	}
+
+	seats, err := s.seatRepository.ListByIDs(ctx, hold.EventID, hold.SeatIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(seats) != len(hold.SeatIDs) {
+		return nil, errs.NewConflictError("The seat map has changed since the seats were held")
+	}
+
+	positions := map[string]int{}
+	var items []model.CheckoutItemInput
+	for _, seat := range seats {
+		if i, ok := positions[seat.TicketTypeID]; ok {
+			items[i].Quantity++
+			continue
+		}
+		positions[seat.TicketTypeID] = len(items)
+		items = append(items, model.CheckoutItemInput{
+			TicketTypeID: seat.TicketTypeID,
+			Quantity:     1,
+		})
+	}
+
+	output, err := s.orderService.CheckoutSeats(ctx, &model.CheckoutInput{
+		EventID:   hold.EventID,
+		PromoCode: input.PromoCode,
+		Items:     items,
+		Answers:   input.Answers,
+	}, hold.SeatIDs, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(output.Order.Seats) == 0 {
+		for _, seat := range seats {
+			seat.OrderID = &output.Order.ID
+			seat.Status = model.SeatStatusTaken
+			output.Order.Seats = append(output.Order.Seats, *seat)
+		}
+	}
+
+	if err := s.seatHoldRepository.Release(ctx, hold); err != nil {
+		log.Printf("[WARN] failed to release seat hold %s: %v", hold.ID, err)
+	}
+
+	return output, nil
+}
+
+// ownHold returns the user's hold. Holds of other users are reported as missing.
+func (s *seatingServiceImpl) ownHold(ctx context.Context, holdID string, userID string) (*model.SeatHold, error) {
+	hold, err := s.seatHoldRepository.Get(ctx, holdID)
+	if err != nil {
+		return nil, err
+	}
+
+	if hold.UserID != userID {
+		return nil, errs.NewNotFoundError("Seat hold not found")
+	}
+
+	if _, err := s.eventRepository.GetByID(ctx, hold.EventID); err != nil {
+		return nil, err
+	}
+
+	return hold, nil
+}
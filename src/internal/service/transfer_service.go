@@ -0,0 +1,298 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/hafiztri123/src/internal/model"
+	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"github.com/hafiztri123/src/internal/pkg/ticket"
+	"github.com/hafiztri123/src/internal/repository"
+)
+
+type TransferService interface {
+	GetPolicy(ctx context.Context, eventID string) (*model.TransferPolicy, error)
+	SetPolicy(ctx context.Context, eventID string, input *model.TransferPolicyInput, userID string) (*model.TransferPolicy, error)
+	InitiateTransfer(ctx context.Context, eventID string, input *model.InitiateTransferInput, userID string) (*model.TicketTransfer, error)
+	ListIncoming(ctx context.Context, userID string) ([]*model.TicketTransfer, error)
+	ListOutgoing(ctx context.Context, userID string) ([]*model.TicketTransfer, error)
+	AcceptTransfer(ctx context.Context, transferID string, userID string) (*model.TicketTransfer, error)
+	DeclineTransfer(ctx context.Context, transferID string, userID string) (*model.TicketTransfer, error)
+	CancelTransfer(ctx context.Context, transferID string, userID string) (*model.TicketTransfer, error)
+	ListEventTransfers(ctx context.Context, eventID string, userID string) ([]*model.TicketTransfer, error)
+}
+
+type transferServiceImpl struct {
+	transferRepository     repository.TransferRepository
+	registrationRepository repository.RegistrationRepository
+	eventRepository        repository.EventRepository
+	userRepository         repository.UserRepository
+}
+
+func NewTransferService(transferRepo repository.TransferRepository, registrationRepo repository.RegistrationRepository, eventRepo repository.EventRepository, userRepo repository.UserRepository) TransferService {
+	return &transferServiceImpl{
+		transferRepository:     transferRepo,
+		registrationRepository: registrationRepo,
+		eventRepository:        eventRepo,
+		userRepository:         userRepo,
+	}
+}
+
+// GetPolicy returns the transfer policy of an event. Events without a stored policy
+// allow unlimited transfers.
+func (s *transferServiceImpl) GetPolicy(ctx context.Context, eventID string) (*model.TransferPolicy, error) {
+	event, err := s.eventRepository.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := s.transferRepository.GetPolicy(ctx, event.ID)
+	var notFoundErr *errs.NotFoundError
+	if errors.As(err, &notFoundErr) {
+		return &model.TransferPolicy{EventID: event.ID, Enabled: true}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+func (s *transferServiceImpl) SetPolicy(ctx context.Context, eventID string, input *model.TransferPolicyInput, userID string) (*model.TransferPolicy, error) {
+	event, err := s.eventRepository.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if event.CreatorID != userID {
+		return nil, errs.NewForbiddenError("Only the event organizers can change the transfer policy")
+	}
+
+	policy := &model.TransferPolicy{
+		EventID:      event.ID,
+		Enabled:      *input.Enabled,
+		MaxTransfers: input.MaxTransfers,
+		UpdatedAt:    time.Now(),
+	}
+
+	if err := s.transferRepository.SavePolicy(ctx, policy); err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// InitiateTransfer offers the user's ticket for the event to the owner of an email
+// address. The ticket stays with the user until the recipient accepts.
+func (s *transferServiceImpl) InitiateTransfer(ctx context.Context, eventID string, input *model.InitiateTransferInput, userID string) (*model.TicketTransfer, error) {
+	event, err := s.eventRepository.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if event.CancelledAt != nil {
+		return nil, errs.NewBadRequestError("Event has been cancelled")
+	}
+
+	if !event.EndDate.After(time.Now()) {
+		return nil, errs.NewBadRequestError("Event has already ended")
+	}
+
+	policy, err := s.GetPolicy(ctx, event.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !policy.Enabled {
+		return nil, errs.NewForbiddenError("Tickets for this event cannot be transferred")
+	}
+
+	registration, err := s.registrationRepository.GetByEventAndUser(ctx, event.ID, userID)
+	var notFoundErr *errs.NotFoundError
+	if errors.As(err, &notFoundErr) {
+		return nil, errs.NewNotFoundError("You have no ticket for this event")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if registration.CheckedInAt != nil {
+		return nil, errs.NewBadRequestError("Tickets that have been used for check-in cannot be transferred")
+	}
+
+	if len(registration.Attendees) > 0 {
+		return nil, errs.NewBadRequestError("Tickets of a group booking are passed on by changing their attendees")
+	}
+
+	if policy.MaxTransfers > 0 && registration.TransferCount >= policy.MaxTransfers {
+		return nil, errs.NewBadRequestError("This ticket has reached the event's transfer limit")
+	}
+
+	sender, err := s.userRepository.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	email := strings.TrimSpace(input.Email)
+	if strings.EqualFold(email, sender.Email) {
+		return nil, errs.NewBadRequestError("You cannot transfer a ticket to yourself")
+	}
+
+	now := time.Now()
+	transfer := &model.TicketTransfer{
+		EventID:        event.ID,
+		RegistrationID: registration.ID,
+		FromUserID:     userID,
+		ToEmail:        email,
+		Status:         model.TransferStatusPending,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	if err := s.transferRepository.Create(ctx, transfer); err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+// ListIncoming returns the pending transfers addressed to the user's email. The
+// email must be verified.
+func (s *transferServiceImpl) ListIncoming(ctx context.Context, userID string) ([]*model.TicketTransfer, error) {
+	user, err := s.userRepository.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.EmailVerifiedAt == nil {
+		return nil, errs.NewForbiddenError("Verify your email address to receive ticket transfers")
+	}
+
+	return s.transferRepository.ListPendingByEmail(ctx, user.Email)
+}
+
+func (s *transferServiceImpl) ListOutgoing(ctx context.Context, userID string) ([]*model.TicketTransfer, error) {
+	return s.transferRepository.ListByUser(ctx, userID)
+}
+
+// AcceptTransfer moves the ticket to the recipient and reissues it, so the code the
+// sender holds no longer checks in. The recipient must own the email the transfer
+// was sent to and must not already have a ticket for the event.
+func (s *transferServiceImpl) AcceptTransfer(ctx context.Context, transferID string, userID string) (*model.TicketTransfer, error) {
+	transfer, user, err := s.incomingTransfer(ctx, transferID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.registrationRepository.GetByEventAndUser(ctx, transfer.EventID, user.ID)
+	if err == nil {
+		return nil, errs.NewDuplicateEntryError("You already have a ticket for this event")
+	}
+
+	var notFoundErr *errs.NotFoundError
+	if !errors.As(err, &notFoundErr) {
+		return nil, err
+	}
+
+	policy, err := s.GetPolicy(ctx, transfer.EventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !policy.Enabled {
+		return nil, errs.NewForbiddenError("Tickets for this event cannot be transferred")
+	}
+
+	nonce, err := ticket.NewNonce()
+	if err != nil {
+		return nil, errs.NewInternalServerError("Failed to reissue ticket")
+	}
+
+	if err := s.transferRepository.Accept(ctx, transfer, user.ID, nonce, policy.MaxTransfers); err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+func (s *transferServiceImpl) DeclineTransfer(ctx context.Context, transferID string, userID string) (*model.TicketTransfer, error) {
+	transfer, user, err := s.incomingTransfer(ctx, transferID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.transferRepository.Close(ctx, transfer, model.TransferStatusDeclined, user.ID); err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+// CancelTransfer withdraws a pending transfer. Only the sender may cancel it.
+func (s *transferServiceImpl) CancelTransfer(ctx context.Context, transferID string, userID string) (*model.TicketTransfer, error) {
+	transfer, err := s.transferRepository.GetByID(ctx, transferID)
+	if err != nil {
+		return nil, err
+	}
+
+	if transfer.FromUserID != userID {
+		return nil, errs.NewNotFoundError("Transfer not found")
+	}
+
+	if _, err := s.eventRepository.GetByID(ctx, transfer.EventID); err != nil {
+		return nil, err
+	}
+
+	if err := s.transferRepository.Close(ctx, transfer, model.TransferStatusCancelled, userID); err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+// ListEventTransfers returns every transfer of the event's tickets with its audit
+// trail. Only the organizer may see them.
+func (s *transferServiceImpl) ListEventTransfers(ctx context.Context, eventID string, userID string) ([]*model.TicketTransfer, error) {
+	event, err := s.eventRepository.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if event.CreatorID != userID {
+		return nil, errs.NewForbiddenError("Only the event organizers can see ticket transfers")
+	}
+
+	return s.transferRepository.ListByEvent(ctx, event.ID)
+}
+
+// incomingTransfer returns a transfer addressed to the user's email. Transfers sent
+// to someone else are reported as missing. Transfers are matched by email, so the
+// user must have verified theirs; otherwise anyone could register with someone
+// else's address and claim their tickets.
+func (s *transferServiceImpl) incomingTransfer(ctx context.Context, transferID string, userID string) (*model.TicketTransfer, *model.User, error) {
+	transfer, err := s.transferRepository.GetByID(ctx, transferID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user, err := s.userRepository.GetByID(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !strings.EqualFold(transfer.ToEmail, user.Email) {
+		return nil, nil, errs.NewNotFoundError("Transfer not found")
+	}
+
+	if user.EmailVerifiedAt == nil {
+		return nil, nil, errs.NewForbiddenError("Verify your email address to receive ticket transfers")
+	}
+
+	if _, err := s.eventRepository.GetByID(ctx, transfer.EventID); err != nil {
+		return nil, nil, err
+	}
+
+	return transfer, user, nil
+}
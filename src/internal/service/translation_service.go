@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/hafiztri123/src/internal/model"
+	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"github.com/hafiztri123/src/internal/pkg/i18n"
+	"github.com/hafiztri123/src/internal/repository"
+)
+
+type TranslationService interface {
+	PutEventTranslation(ctx context.Context, eventID string, locale string, input *model.EventTranslationInput, userID string) (*model.EventTranslation, error)
+	ListEventTranslations(ctx context.Context, eventID string) ([]*model.EventTranslation, error)
+	DeleteEventTranslation(ctx context.Context, eventID string, locale string, userID string) error
+	PutCategoryTranslation(ctx context.Context, categoryID string, locale string, input *model.CategoryTranslationInput, userID string) (*model.CategoryTranslation, error)
+	ListCategoryTranslations(ctx context.Context, categoryID string) ([]*model.CategoryTranslation, error)
+	DeleteCategoryTranslation(ctx context.Context, categoryID string, locale string, userID string) error
+	LocalizeEvents(ctx context.Context, locale string, events ...*model.Event)
+	LocalizeCategories(ctx context.Context, locale string, categories ...*model.Category)
+}
+
+type translationServiceImpl struct {
+	translationRepository repository.TranslationRepository
+	eventRepository       repository.EventRepository
+	categoryRepository    repository.CategoryRepository
+	catalog               *i18n.Catalog
+}
+
+func NewTranslationService(translationRepo repository.TranslationRepository, eventRepo repository.EventRepository, categoryRepo repository.CategoryRepository, catalog *i18n.Catalog) TranslationService {
+	return &translationServiceImpl{
+		translationRepository: translationRepo,
+		eventRepository:       eventRepo,
+		categoryRepository:    categoryRepo,
+		catalog:               catalog,
+	}
+}
+
+// PutEventTranslation creates or replaces the event's content in a locale. Only the
+// organizer may translate an event.
+func (s *translationServiceImpl) PutEventTranslation(ctx context.Context, eventID string, locale string, input *model.EventTranslationInput, userID string) (*model.EventTranslation, error) {
+	locale, err := s.translationLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+
+	event, err := s.eventRepository.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if event.CreatorID != userID {
+		return nil, errs.NewForbiddenError("Only the event organizers can translate the event")
+	}
+
+	now := time.Now()
+	translation := &model.EventTranslation{
+		EventID:     event.ID,
+		Locale:      locale,
+		Title:       input.Title,
+		Description: input.Description,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := s.translationRepository.UpsertEvent(ctx, translation); err != nil {
+		return nil, err
+	}
+
+	return translation, nil
+}
+
+func (s *translationServiceImpl) ListEventTranslations(ctx context.Context, eventID string) ([]*model.EventTranslation, error) {
+	event, err := s.eventRepository.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.translationRepository.ListEvent(ctx, event.ID)
+}
+
+func (s *translationServiceImpl) DeleteEventTranslation(ctx context.Context, eventID string, locale string, userID string) error {
+	locale, err := s.translationLocale(locale)
+	if err != nil {
+		return err
+	}
+
+	event, err := s.eventRepository.GetByID(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	if event.CreatorID != userID {
+		return errs.NewForbiddenError("Only the event organizers can translate the event")
+	}
+
+	return s.translationRepository.DeleteEvent(ctx, event.ID, locale)
+}
+
+// PutCategoryTranslation creates or replaces the category's content in a locale.
+// Only the creator of the category may translate it.
+func (s *translationServiceImpl) PutCategoryTranslation(ctx context.Context, categoryID string, locale string, input *model.CategoryTranslationInput, userID string) (*model.CategoryTranslation, error) {
+	locale, err := s.translationLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+
+	category, err := s.categoryRepository.GetByID(ctx, categoryID)
+	if err != nil {
+		return nil, errs.NewNotFoundError("Category not found")
+	}
+	if category.CreatorID == nil || *category.CreatorID != userID {
+		return nil, errs.NewForbiddenError("Only the category creator can translate it")
+	}
+
+	now := time.Now()
+	translation := &model.CategoryTranslation{
+		CategoryID:  category.ID,
+		Locale:      locale,
+		Name:        input.Name,
+		Description: input.Description,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := s.translationRepository.UpsertCategory(ctx, translation); err != nil {
+		return nil, err
+	}
+
+	return translation, nil
+}
+
+func (s *translationServiceImpl) ListCategoryTranslations(ctx context.Context, categoryID string) ([]*model.CategoryTranslation, error) {
+	category, err := s.categoryRepository.GetByID(ctx, categoryID)
+	if err != nil {
+		return nil, errs.NewNotFoundError("Category not found")
+	}
+
+	return s.translationRepository.ListCategory(ctx, category.ID)
+}
+
+func (s *translationServiceImpl) DeleteCategoryTranslation(ctx context.Context, categoryID string, locale string, userID string) error {
+	locale, err := s.translationLocale(locale)
+	if err != nil {
+		return err
+	}
+
+	category, err := s.categoryRepository.GetByID(ctx, categoryID)
+	if err != nil {
+		return errs.NewNotFoundError("Category not found")
+	}
+	if category.CreatorID == nil || *category.CreatorID != userID {
+		return errs.NewForbiddenError("Only the category creator can translate it")
+	}
+
+	return s.translationRepository.DeleteCategory(ctx, category.ID, locale)
+}
+
+// LocalizeEvents replaces the title and description of each event with its
+// translation in locale and sets the locale its content is in. Events without a
+// translation keep the default locale. Lookup failures are logged and the events
+// are served untranslated.
+func (s *translationServiceImpl) LocalizeEvents(ctx context.Context, locale string, events ...*model.Event) {
+	ids := make([]string, 0, len(events))
+	for _, event := range events {
+		event.Locale = s.catalog.Default()
+		ids = append(ids, event.ID)
+	}
+
+	if locale == "" || locale == s.catalog.Default() {
+		return
+	}
+
+	translations, err := s.translationRepository.FindEvents(ctx, ids, locale)
+	if err != nil {
+		log.Printf("[WARN] failed to load %s event translations: %v", locale, err)
+		return
+	}
+
+	byEvent := make(map[string]*model.EventTranslation, len(translations))
+	for _, translation := range translations {
+		byEvent[translation.EventID] = translation
+	}
+
+	for _, event := range events {
+		if translation, ok := byEvent[event.ID]; ok {
+			event.Title = translation.Title
+			event.Description = translation.Description
+			event.Locale = locale
+		}
+	}
+}
+
+// LocalizeCategories does for categories what LocalizeEvents does for events.
+func (s *translationServiceImpl) LocalizeCategories(ctx context.Context, locale string, categories ...*model.Category) {
+	ids := make([]string, 0, len(categories))
+	for _, category := range categories {
+		category.Locale = s.catalog.Default()
+		ids = append(ids, category.ID)
+	}
+
+	if locale == "" || locale == s.catalog.Default() {
+		return
+	}
+
+	translations, err := s.translationRepository.FindCategories(ctx, ids, locale)
+	if err != nil {
+		log.Printf("[WARN] failed to load %s category translations: %v", locale, err)
+		return
+	}
+
+	byCategory := make(map[string]*model.CategoryTranslation, len(translations))
+	for _, translation := range translations {
+		byCategory[translation.CategoryID] = translation
+	}
+
+	for _, category := range categories {
+		if translation, ok := byCategory[category.ID]; ok {
+			category.Name = translation.Name
+			category.Description = translation.Description
+			category.Locale = locale
+		}
+	}
+}
+
+// translationLocale canonicalizes a locale that content may be translated into.
+// The default locale is not one of them since it is edited on the resource itself.
+func (s *translationServiceImpl) translationLocale(locale string) (string, error) {
+	canonical, ok := s.catalog.Lookup(locale)
+	if !ok {
+		return "", errs.NewBadRequestError("Locale is not supported")
+	}
+	if canonical == s.catalog.Default() {
+		return "", errs.NewBadRequestError("Content in the default locale is edited on the resource itself")
+	}
+
+	return canonical, nil
+}
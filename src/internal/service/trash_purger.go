@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/hafiztri123/src/internal/pkg/storage"
+	"github.com/hafiztri123/src/internal/repository"
+)
+
+// TrashPurger permanently removes events and categories that have been in the
+// trash for longer than the retention period, including their stored files.
+type TrashPurger struct {
+	eventRepository    repository.EventRepository
+	categoryRepository repository.CategoryRepository
+	storage            storage.StorageService
+	retention          time.Duration
+}
+
+func NewTrashPurger(eventRepo repository.EventRepository, categoryRepo repository.CategoryRepository, storage storage.StorageService, retention time.Duration) *TrashPurger {
+	return &TrashPurger{
+		eventRepository:    eventRepo,
+		categoryRepository: categoryRepo,
+		storage:            storage,
+		retention:          retention,
+	}
+}
+
+func (p *TrashPurger) Name() string {
+	return "trash-purge"
+}
+
+// Run purges every expired item on its own. An item that fails is logged and left
+// for the next run, so it cannot hold up the others. Stored files are only deleted
+// once their event's rows are gone; before that the event can still be restored.
+func (p *TrashPurger) Run(ctx context.Context) error {
+	cutoff := time.Now().Add(-p.retention)
+
+	events, err := p.eventRepository.ListPurgeable(ctx, cutoff)
+	if err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		if err := p.eventRepository.Purge(ctx, event.ID); err != nil {
+			log.Printf("[FAIL] failed to purge event %s: %v", event.ID, err)
+			continue
+		}
+
+		for _, file := range event.Files {
+			if err := p.storage.DeleteFile(ctx, storage.ExtractPublicID(file.FileURL)); err != nil {
+				log.Printf("[FAIL] failed to delete file %s of event %s: %v", file.ID, event.ID, err)
+			}
+		}
+	}
+
+	categories, err := p.categoryRepository.ListPurgeable(ctx, cutoff)
+	if err != nil {
+		return err
+	}
+
+	for _, category := range categories {
+		if err := p.categoryRepository.Purge(ctx, category.ID); err != nil {
+			log.Printf("[FAIL] failed to purge category %s: %v", category.ID, err)
+		}
+	}
+
+	return nil
+}
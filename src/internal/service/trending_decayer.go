@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/hafiztri123/src/internal/model"
+	"github.com/hafiztri123/src/internal/repository"
+)
+
+// TrendingDecayer scales down the trending scores so that older signals count for
+// less than recent ones. Each window decays at its own half-life, by the time that
+// has passed since it was last decayed.
+type TrendingDecayer struct {
+	trendingRepository repository.TrendingRepository
+}
+
+func NewTrendingDecayer(trendingRepo repository.TrendingRepository) *TrendingDecayer {
+	return &TrendingDecayer{
+		trendingRepository: trendingRepo,
+	}
+}
+
+func (j *TrendingDecayer) Name() string {
+	return "trending-decay"
+}
+
+func (j *TrendingDecayer) Run(ctx context.Context) error {
+	now := time.Now()
+	factors := map[string]float64{}
+
+	for window, halfLife := range model.TrendingHalfLife {
+		last, err := j.trendingRepository.LastDecay(ctx, window)
+		if err != nil {
+			return err
+		}
+
+		if err := j.trendingRepository.SetLastDecay(ctx, window, now); err != nil {
+			return err
+		}
+
+		// Nothing to catch up on the very first run.
+		if last.IsZero() || !now.After(last) {
+			continue
+		}
+
+		factors[window] = math.Pow(0.5, float64(now.Sub(last))/float64(halfLife))
+	}
+
+	keys, err := j.trendingRepository.ListKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		factor, ok := factors[repository.TrendingKeyWindow(key)]
+		if !ok {
+			continue
+		}
+
+		if err := j.trendingRepository.Decay(ctx, key, factor); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
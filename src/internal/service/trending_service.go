@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/hafiztri123/src/internal/model"
+	"github.com/hafiztri123/src/internal/pkg/tenant"
+	"github.com/hafiztri123/src/internal/repository"
+)
+
+type TrendingService interface {
+	Record(ctx context.Context, eventID string, signal string)
+	GetTrending(ctx context.Context, input *model.TrendingInput) ([]*model.TrendingEvent, error)
+}
+
+type trendingServiceImpl struct {
+	trendingRepository repository.TrendingRepository
+	eventRepository    repository.EventRepository
+}
+
+func NewTrendingService(trendingRepo repository.TrendingRepository, eventRepo repository.EventRepository) TrendingService {
+	return &trendingServiceImpl{
+		trendingRepository: trendingRepo,
+		eventRepository:    eventRepo,
+	}
+}
+
+// Record adds a signal to the event's score in every window of its organization,
+// both overall and in its category. Events that are cancelled or over are not ranked. Failures are
+// logged rather than returned since ranking is best effort.
+func (s *trendingServiceImpl) Record(ctx context.Context, eventID string, signal string) {
+	event, err := s.eventRepository.GetByID(ctx, eventID)
+	if err != nil {
+		log.Printf("[WARN] failed to record %s of event %s for trending: %v", signal, eventID, err)
+		return
+	}
+
+	if event.CancelledAt != nil || !event.EndDate.After(time.Now()) {
+		return
+	}
+
+	var keys []string
+	for window := range model.TrendingHalfLife {
+		keys = append(keys, repository.TrendingKey(event.OrganizationID, window, ""))
+		if event.CategoryID != "" {
+			keys = append(keys, repository.TrendingKey(event.OrganizationID, window, event.CategoryID))
+		}
+	}
+
+	if err := s.trendingRepository.Increment(ctx, keys, event.ID, model.TrendingWeight[signal]); err != nil {
+		log.Printf("[WARN] failed to record %s of event %s for trending: %v", signal, eventID, err)
+	}
+}
+
+// GetTrending returns the highest scoring events of a window in the organization of
+// the request. Events that have since been deleted, cancelled or ended are dropped
+// from the ranking as they are found.
+func (s *trendingServiceImpl) GetTrending(ctx context.Context, input *model.TrendingInput) ([]*model.TrendingEvent, error) {
+	organizationID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return nil, tenant.ErrMissing
+	}
+	key := repository.TrendingKey(organizationID, input.Window, input.CategoryID)
+
+	// Over-fetch so that stale entries do not leave the list short.
+	ranked, err := s.trendingRepository.Top(ctx, key, input.Limit*2)
+	if err != nil {
+		return nil, err
+	}
+
+	trending := make([]*model.TrendingEvent, 0, input.Limit)
+	var stale []string
+
+	for _, entry := range ranked {
+		if len(trending) == input.Limit {
+			break
+		}
+
+		eventID, _ := entry.Member.(string)
+		event, err := s.eventRepository.GetByID(ctx, eventID)
+		if err != nil {
+			stale = append(stale, eventID)
+			continue
+		}
+
+		if event.CancelledAt != nil || !event.EndDate.After(time.Now()) {
+			stale = append(stale, eventID)
+			continue
+		}
+
+		trending = append(trending, &model.TrendingEvent{
+			Event: event,
+			Score: entry.Score,
+		})
+	}
+
+	if err := s.trendingRepository.Remove(ctx, key, stale...); err != nil {
+		log.Printf("[WARN] failed to remove stale events from %s: %v", key, err)
+	}
+
+	return trending, nil
+}
@@ -2,13 +2,14 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"mime/multipart"
-	"time"
 
 	"github.com/hafiztri123/src/internal/model"
 	errs "github.com/hafiztri123/src/internal/pkg/error"
+	"github.com/hafiztri123/src/internal/pkg/slug"
 	"github.com/hafiztri123/src/internal/pkg/storage"
 	"github.com/hafiztri123/src/internal/repository"
 	"golang.org/x/crypto/bcrypt"
@@ -16,8 +17,10 @@ import (
 
 
 type UserService interface {
-    UpdateProfile(userID string, input *model.UpdateProfileInput) error
+    UpdateProfile(userID string, input *model.UpdateProfileInput, version int) error
+    PatchProfile(userID string, patch []byte, version int) error
     GetProfile(userID string) (*model.User, error)
+    GetOrganizer(idOrSlug string) (*model.OrganizerProfile, error)
     ChangePassword(userID string, input *model.ChangePasswordInput) error
     UploadProfileImage(ctx context.Context, userID string, file multipart.File, fileName string) error
 }
@@ -35,19 +38,39 @@ func NewUserService(userRepo repository.UserRepository, storage storage.StorageS
 	}
 }
 
-func (s userServiceImpl) UpdateProfile(userID string, input *model.UpdateProfileInput) error {
+func (s userServiceImpl) UpdateProfile(userID string, input *model.UpdateProfileInput, version int) error {
 	if input == nil {
 		return errs.NewBadRequestError("Request is missing")
 	}
 
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+
 	updatedModel := &model.User{
 		ID: userID,
-		FullName: input.FullName,
-		PhoneNumber: input.PhoneNumber,
-		Organization: input.Organization,
-		Bio: input.Bio,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		FullName: user.FullName,
+		PhoneNumber: user.PhoneNumber,
+		Organization: user.Organization,
+		Bio: user.Bio,
+		Version: version,
+	}
+
+	if input.FullName != "" {
+		updatedModel.FullName = input.FullName
+	}
+
+	if input.PhoneNumber != "" {
+		updatedModel.PhoneNumber = input.PhoneNumber
+	}
+
+	if input.Organization != "" {
+		updatedModel.Organization = input.Organization
+	}
+
+	if input.Bio != "" {
+		updatedModel.Bio = input.Bio
 	}
 
 	if err := s.userRepo.Update(userID,updatedModel); err != nil {
@@ -57,6 +80,67 @@ func (s userServiceImpl) UpdateProfile(userID string, input *model.UpdateProfile
 	return nil
 }
 
+// PatchProfile applies an RFC 7396 merge patch to the user's profile. Explicit null
+// clears a field and omitted fields stay unchanged.
+func (s userServiceImpl) PatchProfile(userID string, patch []byte, version int) error {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+
+	current := model.ProfileSnapshot{
+		FullName: user.FullName,
+		PhoneNumber: user.PhoneNumber,
+		Organization: user.Organization,
+		Bio: user.Bio,
+	}
+
+	var merged model.ProfileSnapshot
+	if err := applyMergePatch(current, patch, &merged); err != nil {
+		return err
+	}
+
+	return s.userRepo.Update(userID, &model.User{
+		ID: userID,
+		FullName: merged.FullName,
+		PhoneNumber: merged.PhoneNumber,
+		Organization: merged.Organization,
+		Bio: merged.Bio,
+		Version: version,
+	})
+}
+
+// GetOrganizer returns the public profile of a user by ID or slug, including slugs
+// they had before changing their name.
+func (s userServiceImpl) GetOrganizer(idOrSlug string) (*model.OrganizerProfile, error) {
+	id := idOrSlug
+	if !slug.IsID(id) {
+		userID, err := s.userRepo.ResolveSlug(id)
+		if err != nil {
+			return nil, err
+		}
+		id = userID
+	}
+
+	user, err := s.userRepo.GetByID(id)
+	if err != nil {
+		var notFoundErr *errs.NotFoundError
+		if errors.As(err, &notFoundErr) {
+			return nil, errs.NewNotFoundError("Organizer not found")
+		}
+		return nil, err
+	}
+
+	return &model.OrganizerProfile{
+		ID:           user.ID,
+		Slug:         user.Slug,
+		FullName:     user.FullName,
+		Organization: user.Organization,
+		Bio:          user.Bio,
+		ProfileImage: user.ProfileImage,
+	}, nil
+}
+
 func (s userServiceImpl) GetProfile(userID string) (*model.User, error) {
 	if userID == "" {
 		return nil, errs.NewBadRequestError("Request is missing")